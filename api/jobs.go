@@ -0,0 +1,163 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/lologarithm/wowsim/tbc"
+)
+
+// States of a job.
+const (
+	JobQueued    = "queued"    // waiting for a free sim slot.
+	JobRunning   = "running"   // simulating.
+	JobDone      = "done"      // finished, the result is in the status.
+	JobCancelled = "cancelled" // cancelled before it finished, there is no result.
+	JobFailed    = "failed"    // the sim crashed, the status has the error.
+)
+
+// JobStatus is what a job is doing, returned when it is submitted, polled or cancelled.
+type JobStatus struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"` // the endpoint, like statweights.
+	State    string       `json:"state"`
+	Progress tbc.Progress `json:"progress"`
+	Result   interface{}  `json:"result,omitempty"` // the same result the endpoint returns, once done.
+	Error    string       `json:"error,omitempty"`  // why the job failed.
+}
+
+// queuedJob is a submitted sim and its result.
+type queuedJob struct {
+	id     string
+	typ    string
+	job    *tbc.Job
+	cancel context.CancelFunc
+
+	state  string
+	result interface{}
+	err    string
+}
+
+// jobQueue keeps the jobs of a server, finished or not, so they can be polled.
+type jobQueue struct {
+	mu    sync.Mutex
+	max   int
+	jobs  map[string]*queuedJob
+	order []string // IDs in the order submitted, to drop the oldest first.
+}
+
+func newJobQueue(max int) *jobQueue {
+	return &jobQueue{max: max, jobs: map[string]*queuedJob{}}
+}
+
+// status must be called with the queue locked.
+func (q *jobQueue) status(j *queuedJob) JobStatus {
+	return JobStatus{ID: j.id, Type: j.typ, State: j.state, Progress: j.job.Progress(), Result: j.result, Error: j.err}
+}
+
+// add makes room for a job by dropping the oldest finished job if needed, false if every job is unfinished.
+func (q *jobQueue) add(j *queuedJob) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.jobs) >= q.max {
+		for i, id := range q.order {
+			if state := q.jobs[id].state; state == JobDone || state == JobCancelled || state == JobFailed {
+				delete(q.jobs, id)
+				q.order = append(q.order[:i], q.order[i+1:]...)
+				break
+			}
+		}
+		if len(q.jobs) >= q.max {
+			return false
+		}
+	}
+	q.jobs[j.id] = j
+	q.order = append(q.order, j.id)
+	return true
+}
+
+func (q *jobQueue) setState(j *queuedJob, state string, result interface{}) {
+	q.mu.Lock()
+	j.state, j.result = state, result
+	q.mu.Unlock()
+}
+
+func (q *jobQueue) fail(j *queuedJob, err string) {
+	q.mu.Lock()
+	j.state, j.err = JobFailed, err
+	q.mu.Unlock()
+}
+
+// submit queues a sim to run once there is a free slot and returns its status.
+func (s *Server) submit(typ string, sim func(job *tbc.Job) interface{}) (interface{}, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &queuedJob{id: hex.EncodeToString(id), typ: typ, job: tbc.NewJob(ctx, nil), cancel: cancel, state: JobQueued}
+	if !s.jobs.add(j) {
+		cancel()
+		return nil, &requestError{status: http.StatusServiceUnavailable, message: "too many unfinished jobs, try again later"}
+	}
+
+	go func() {
+		defer cancel()
+		if !s.takeSlot(ctx) {
+			s.jobs.setState(j, JobCancelled, nil)
+			return
+		}
+		defer s.freeSlot()
+		// A crashed sim fails its job instead of taking the server down with it.
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Job %s (%s) crashed: %v", j.id, typ, r)
+				s.jobs.fail(j, fmt.Sprintf("sim crashed: %v", r))
+			}
+		}()
+		s.jobs.setState(j, JobRunning, nil)
+		res := sim(j.job)
+		if j.job.Err() != nil {
+			s.jobs.setState(j, JobCancelled, nil)
+			return
+		}
+		s.jobs.setState(j, JobDone, res)
+	}()
+
+	s.jobs.mu.Lock()
+	status := s.jobs.status(j)
+	s.jobs.mu.Unlock()
+	return status, nil
+}
+
+// serveJob returns the status of a job for a GET of /api/jobs/<id>, and cancels it for a DELETE.
+// A cancelled job is kept so its status can still be polled.
+func (s *Server) serveJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodDelete {
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodDelete)
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "jobs are polled with a GET and cancelled with a DELETE"})
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, "/api/jobs/")
+	s.jobs.mu.Lock()
+	j, ok := s.jobs.jobs[id]
+	s.jobs.mu.Unlock()
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "no job " + id})
+		return
+	}
+	if r.Method == http.MethodDelete {
+		j.cancel() // the state changes once the sim notices.
+	}
+
+	s.jobs.mu.Lock()
+	status := s.jobs.status(j)
+	s.jobs.mu.Unlock()
+	writeJSON(w, http.StatusOK, status)
+}
@@ -0,0 +1,456 @@
+// Package api has the requests the web UI makes to the sim, and runs them.
+//
+// The wasm bridge and the HTTP server both take these shapes as JSON, so anything that
+// drives the UI's sim can call a sim server the same way.
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/lologarithm/wowsim/tbc"
+)
+
+// GearItem is an item of a gear list: by name with its gems and enchant by name,
+// or by ID with gem and enchant IDs like the UI keeps its gear.
+type GearItem struct {
+	Name    string   `json:",omitempty"`
+	ID      int32    `json:",omitempty"`
+	Gems    []string `json:",omitempty"` // in socket order, "" for an empty socket.
+	Enchant string   `json:",omitempty"`
+
+	G []int32 `json:"g,omitempty"` // gem IDs in socket order, 0 for an empty socket.
+	E int32   `json:"e,omitempty"` // enchant ID.
+}
+
+// Gear is a list of items to wear, they go in the first free slot that fits.
+type Gear []GearItem
+
+// Equipment looks up the items and returns them as equipment, along with any problems with the gear.
+// Items that can't be found are left out.
+func (g Gear) Equipment() (tbc.Equipment, tbc.GearProblems) {
+	problems := tbc.GearProblems{}
+	items := make([]tbc.Item, 0, len(g))
+	for _, gi := range g {
+		var ic tbc.Item
+		var ok bool
+		if gi.Name != "" {
+			ic, ok = tbc.ItemsByName[gi.Name]
+			if !ok {
+				problems = append(problems, tbc.GearProblem{Item: gi.Name, Message: "not in the item data"})
+			}
+		} else if gi.ID != 0 {
+			ic, ok = tbc.ItemsByID[gi.ID]
+			if !ok {
+				problems = append(problems, tbc.GearProblem{Item: fmt.Sprintf("ID %d", gi.ID), Message: "not in the item data"})
+			}
+		}
+		if !ok {
+			continue
+		}
+		if len(gi.Gems) > 0 {
+			ic.Gems = make([]tbc.Gem, len(ic.GemSlots))
+			for i := range ic.Gems {
+				if i >= len(gi.Gems) || gi.Gems[i] == "" {
+					continue
+				}
+				gv, ok := tbc.GemLookup[gi.Gems[i]]
+				if !ok {
+					problems = append(problems, tbc.GearProblem{Slot: ic.Slot, Item: ic.Name, Message: fmt.Sprintf("gem %q is not in the gem data", gi.Gems[i])})
+					continue
+				}
+				ic.Gems[i] = gv
+			}
+		} else if len(gi.G) > 0 {
+			ic.Gems = make([]tbc.Gem, len(ic.GemSlots))
+			for i := range ic.Gems {
+				if i >= len(gi.G) || gi.G[i] == 0 {
+					continue
+				}
+				gv, ok := tbc.GemsByID[gi.G[i]]
+				if !ok {
+					problems = append(problems, tbc.GearProblem{Slot: ic.Slot, Item: ic.Name, Message: fmt.Sprintf("gem ID %d is not in the gem data", gi.G[i])})
+					continue
+				}
+				ic.Gems[i] = gv
+			}
+		}
+		if gi.Enchant != "" {
+			en, ok := tbc.EnchantLookup[gi.Enchant]
+			if !ok {
+				problems = append(problems, tbc.GearProblem{Slot: ic.Slot, Item: ic.Name, Message: fmt.Sprintf("enchant %q is not in the enchant data", gi.Enchant)})
+			}
+			ic.Enchant = en
+		} else if gi.E != 0 {
+			en, ok := tbc.EnchantByID[gi.E]
+			if !ok {
+				problems = append(problems, tbc.GearProblem{Slot: ic.Slot, Item: ic.Name, Message: fmt.Sprintf("enchant ID %d is not in the enchant data", gi.E)})
+			}
+			ic.Enchant = en
+		}
+		items = append(items, ic)
+	}
+	gear, placement := tbc.NewEquipment(items...)
+	problems = append(problems, placement...)
+	return gear, append(problems, gear.Validate()...)
+}
+
+// GearOf is the gear list of equipment, by ID.
+func GearOf(equip tbc.Equipment) Gear {
+	gear := Gear{}
+	for _, item := range equip {
+		if item.ID == 0 {
+			continue
+		}
+		gi := GearItem{ID: item.ID, E: item.Enchant.ID}
+		for _, g := range item.Gems {
+			gi.G = append(gi.G, g.ID)
+		}
+		gear = append(gear, gi)
+	}
+	return gear
+}
+
+// Options are the sim options as the UI sends them, named after the inputs of the options form.
+// Talents aren't in the form, every sim uses the standard elemental talents.
+type Options struct {
+	ExitOnOOM bool `json:"exitoom"`
+	UseAI     bool `json:"useai"`
+
+	NumBloodlust int `json:"buffbl"`
+	NumDrums     int `json:"buffdrums"`
+
+	ArcaneInt                bool   `json:"buffai"`
+	GiftOftheWild            bool   `json:"buffgotw"`
+	BlessingOfKings          bool   `json:"buffbk"`
+	ImprovedBlessingOfWisdom bool   `json:"buffibow"`
+	ImprovedDivineSpirit     bool   `json:"buffids"`
+	JudgementOfWisdom        bool   `json:"debuffjow"`
+	ImpSealofCrusader        bool   `json:"debuffisoc"`
+	Misery                   bool   `json:"debuffmis"`
+	Moonkin                  bool   `json:"buffmoon"`
+	MoonkinRavenGoddess      bool   `json:"buffmoonrg"`
+	SpriestDPS               uint16 `json:"buffspriest"`
+	WaterShield              bool   `json:"sbufws"`
+	EyeOfNight               bool   `json:"buffeyenight"`
+	TwilightOwl              bool   `json:"bufftwilightowl"`
+	Race                     int    `json:"sbufrace"`
+
+	Custom CustomStats `json:"custom"`
+
+	FlaskOfBlindingLight     bool `json:"confbl"`
+	FlaskOfMightyRestoration bool `json:"confmr"`
+	BrilliantWizardOil       bool `json:"conbwo"`
+	MajorMageblood           bool `json:"conmm"`
+	BlackendBasilisk         bool `json:"conbb"`
+	DestructionPotion        bool `json:"condp"`
+	SuperManaPotion          bool `json:"consmp"`
+	DarkRune                 bool `json:"condr"`
+
+	TotemOfWrath int  `json:"totwr"`
+	WrathOfAir   bool `json:"totwoa"`
+	Cyclone2PC   bool `json:"totcycl2p"`
+	ManaStream   bool `json:"totms"`
+
+	DPSReportTime int `json:"dpsReportTime"`
+}
+
+// CustomStats are the custom stat bonuses of the options form.
+type CustomStats struct {
+	Int       float64 `json:"custint"`
+	SpellCrit float64 `json:"custsc"`
+	SpellHit  float64 `json:"custsh"`
+	SpellDmg  float64 `json:"custsp"`
+	Haste     float64 `json:"custha"`
+	MP5       float64 `json:"custmp5"`
+	Mana      float64 `json:"custmana"`
+}
+
+// TBC converts the options to the options the sim runs with.
+func (o Options) TBC() tbc.Options {
+	return tbc.Options{
+		ExitOnOOM:    o.ExitOnOOM,
+		NumBloodlust: o.NumBloodlust,
+		NumDrums:     o.NumDrums,
+		UseAI:        o.UseAI,
+		Buffs: tbc.Buffs{
+			ArcaneInt:                o.ArcaneInt,
+			GiftOftheWild:            o.GiftOftheWild,
+			BlessingOfKings:          o.BlessingOfKings,
+			ImprovedBlessingOfWisdom: o.ImprovedBlessingOfWisdom,
+			ImprovedDivineSpirit:     o.ImprovedDivineSpirit,
+			JudgementOfWisdom:        o.JudgementOfWisdom,
+			ImpSealofCrusader:        o.ImpSealofCrusader,
+			Misery:                   o.Misery,
+			Moonkin:                  o.Moonkin,
+			MoonkinRavenGoddess:      o.MoonkinRavenGoddess,
+			SpriestDPS:               o.SpriestDPS,
+			WaterShield:              o.WaterShield,
+			EyeOfNight:               o.EyeOfNight,
+			TwilightOwl:              o.TwilightOwl,
+			Race:                     tbc.RaceBonusType(o.Race),
+			Custom: tbc.Stats{
+				tbc.StatInt:       o.Custom.Int,
+				tbc.StatSpellCrit: o.Custom.SpellCrit,
+				tbc.StatSpellHit:  o.Custom.SpellHit,
+				tbc.StatSpellDmg:  o.Custom.SpellDmg,
+				tbc.StatHaste:     o.Custom.Haste,
+				tbc.StatMP5:       o.Custom.MP5,
+				tbc.StatMana:      o.Custom.Mana,
+			},
+		},
+		Consumes: tbc.Consumes{
+			FlaskOfBlindingLight:     o.FlaskOfBlindingLight,
+			FlaskOfMightyRestoration: o.FlaskOfMightyRestoration,
+			BrilliantWizardOil:       o.BrilliantWizardOil,
+			MajorMageblood:           o.MajorMageblood,
+			BlackendBasilisk:         o.BlackendBasilisk,
+			DestructionPotion:        o.DestructionPotion,
+			SuperManaPotion:          o.SuperManaPotion,
+			DarkRune:                 o.DarkRune,
+		},
+		Talents: tbc.Talents{
+			LightninOverload:   5,
+			ElementalPrecision: 3,
+			NaturesGuidance:    3,
+			TidalMastery:       5,
+			ElementalMastery:   true,
+			UnrelentingStorm:   3,
+			CallOfThunder:      5,
+			Concussion:         5,
+			Convection:         5,
+		},
+		Totems: tbc.Totems{
+			TotemOfWrath: o.TotemOfWrath,
+			WrathOfAir:   o.WrathOfAir,
+			Cyclone2PC:   o.Cyclone2PC,
+			ManaStream:   o.ManaStream,
+		},
+		DPSReportTime: o.DPSReportTime,
+	}
+}
+
+// OptionsOf is the reverse of Options.TBC, for the UI to show options it didn't make.
+// Anything the options form doesn't have is lost.
+func OptionsOf(opt tbc.Options) Options {
+	custom := func(s tbc.Stat) float64 {
+		if int(s) < len(opt.Buffs.Custom) {
+			return opt.Buffs.Custom[s]
+		}
+		return 0
+	}
+	b, c, t := opt.Buffs, opt.Consumes, opt.Totems
+	return Options{
+		ExitOnOOM:    opt.ExitOnOOM,
+		UseAI:        opt.UseAI,
+		NumBloodlust: opt.NumBloodlust,
+		NumDrums:     opt.NumDrums,
+
+		ArcaneInt:                b.ArcaneInt,
+		GiftOftheWild:            b.GiftOftheWild,
+		BlessingOfKings:          b.BlessingOfKings,
+		ImprovedBlessingOfWisdom: b.ImprovedBlessingOfWisdom,
+		ImprovedDivineSpirit:     b.ImprovedDivineSpirit,
+		JudgementOfWisdom:        b.JudgementOfWisdom,
+		ImpSealofCrusader:        b.ImpSealofCrusader,
+		Misery:                   b.Misery,
+		Moonkin:                  b.Moonkin,
+		MoonkinRavenGoddess:      b.MoonkinRavenGoddess,
+		SpriestDPS:               b.SpriestDPS,
+		WaterShield:              b.WaterShield,
+		EyeOfNight:               b.EyeOfNight,
+		TwilightOwl:              b.TwilightOwl,
+		Race:                     int(b.Race),
+		Custom: CustomStats{
+			Int:       custom(tbc.StatInt),
+			SpellCrit: custom(tbc.StatSpellCrit),
+			SpellHit:  custom(tbc.StatSpellHit),
+			SpellDmg:  custom(tbc.StatSpellDmg),
+			Haste:     custom(tbc.StatHaste),
+			MP5:       custom(tbc.StatMP5),
+			Mana:      custom(tbc.StatMana),
+		},
+
+		FlaskOfBlindingLight:     c.FlaskOfBlindingLight,
+		FlaskOfMightyRestoration: c.FlaskOfMightyRestoration,
+		BrilliantWizardOil:       c.BrilliantWizardOil,
+		MajorMageblood:           c.MajorMageblood,
+		BlackendBasilisk:         c.BlackendBasilisk,
+		DestructionPotion:        c.DestructionPotion,
+		SuperManaPotion:          c.SuperManaPotion,
+		DarkRune:                 c.DarkRune,
+
+		TotemOfWrath: t.TotemOfWrath,
+		WrathOfAir:   t.WrathOfAir,
+		Cyclone2PC:   t.Cyclone2PC,
+		ManaStream:   t.ManaStream,
+
+		DPSReportTime: opt.DPSReportTime,
+	}
+}
+
+// SimRequest is the part every request that simulates has.
+type SimRequest struct {
+	Iters    int     `json:"iters"` // iterations, of each rotation or setup simulated.
+	Dur      int     `json:"dur"`   // fight duration in seconds.
+	Gearlist Gear    `json:"gearlist"`
+	Opts     Options `json:"opts"`
+	Seed     int64   `json:"seed,omitempty"` // random seed, 0 for a seed from the request so the same request sims the same.
+}
+
+// requestSeed is seed if it is set, otherwise a hash of the request. Repeating a request then repeats
+// the same sims, which come from the result cache.
+func requestSeed(seed int64, req interface{}) int64 {
+	if seed != 0 {
+		return seed
+	}
+	data, _ := json.Marshal(req)
+	h := fnv.New64a()
+	h.Write(data)
+	return int64(h.Sum64())
+}
+
+// SimulateRequest sims the gear with the AI rotation, or with each of a list of fixed rotations.
+type SimulateRequest struct {
+	SimRequest
+	Rots     [][]string `json:"rots,omitempty"`     // fixed rotations to sim instead of the AI.
+	Haste    float64    `json:"haste,omitempty"`    // haste rating to sim with instead of the gear's.
+	FullLogs bool       `json:"fullLogs,omitempty"` // return the combat log of the sim in Logs.
+
+	Debug bool `json:"-"` // print the combat log as it happens.
+}
+
+// Simulate runs the request, with a result for each rotation.
+// Every function that simulates takes a job to report progress to and cancel the sims, it can be nil.
+func Simulate(req SimulateRequest, job *tbc.Job) []tbc.SimResult {
+	gear, _ := req.Gearlist.Equipment()
+	opts := req.Opts.TBC()
+	opts.Debug = req.Debug
+	opts.Job = job
+	stats := tbc.CalculateTotalStats(opts, gear)
+	if req.Haste != 0 {
+		stats[tbc.StatHaste] = req.Haste
+	}
+
+	results := []tbc.SimResult{}
+	dosim := func(spells []string) {
+		st := time.Now()
+		optNow := opts
+		optNow.SpellOrder = spells
+		optNow.RSeed = requestSeed(req.Seed, req)
+		sim := tbc.NewSim(stats, gear, optNow)
+		logs := &strings.Builder{}
+		if req.FullLogs {
+			sim.Debug = func(s string, vals ...interface{}) {
+				logs.WriteString(fmt.Sprintf("[%0.1f] "+s, append([]interface{}{(float64(sim.CurrentTick) / float64(tbc.TicksPerSecond))}, vals...)...))
+			}
+		}
+		res := tbc.SummarizeSim(sim, req.Dur, req.Iters)
+		res.Rotation = spells
+		if opts.UseAI {
+			res.Rotation = []string{"AI Optimized"}
+		}
+		res.Logs = logs.String()
+		res.RealDuration = time.Now().Sub(st).Seconds()
+		results = append(results, res)
+	}
+
+	if len(req.Rots) == 0 && opts.UseAI {
+		job.Phase("simulating", req.Iters)
+		dosim([]string{"AI Optimized"}) // Let AI determine best possible DPS
+	} else {
+		job.Phase("simulating", len(req.Rots)*req.Iters)
+		for _, spells := range req.Rots {
+			dosim(spells)
+		}
+	}
+	return results
+}
+
+// StatWeightsRequest calculates the weight of every stat.
+type StatWeightsRequest struct {
+	SimRequest
+	Delta   float64 `json:"delta,omitempty"`   // how much of each stat to add, defaults to 50.
+	Central bool    `json:"central,omitempty"` // also sim each stat reduced by delta, see tbc.StatWeightOptions.
+}
+
+func StatWeights(req StatWeightsRequest, job *tbc.Job) tbc.StatWeightsResult {
+	gear, _ := req.Gearlist.Equipment()
+	opts := req.Opts.TBC()
+	opts.RSeed = requestSeed(req.Seed, req)
+	opts.Job = job
+	return tbc.CalculateStatWeights(opts, gear, req.Dur, req.Iters, tbc.StatWeightOptions{Delta: req.Delta, Central: req.Central})
+}
+
+// ComputeStatsRequest totals the stats of gear, with the buffs of the options if they are given.
+type ComputeStatsRequest struct {
+	Gear Gear     `json:"gear"`
+	Opts *Options `json:"opts"`
+}
+
+// ComputeStatsResult is the total stats of the gear and the sets it activates.
+type ComputeStatsResult struct {
+	Stats    tbc.Stats
+	Sets     []string
+	Warnings []string // problems with the gear.
+}
+
+func ComputeStats(req ComputeStatsRequest) ComputeStatsResult {
+	gear, problems := req.Gear.Equipment()
+	if req.Opts == nil {
+		return ComputeStatsResult{Stats: gear.Stats().CalculatedTotal(), Warnings: problems.Strings()}
+	}
+	opt := req.Opts.TBC()
+	stats := tbc.CalculateTotalStats(opt, gear)
+	opt.UseAI = true // stupid complaining sim...maybe I should just default AI on.
+	fakesim := tbc.NewSim(stats, gear, opt)
+	sets := fakesim.ActivateSets()
+
+	finalStats := stats
+	for i, v := range fakesim.Buffs {
+		finalStats[i] += v
+	}
+	return ComputeStatsResult{Stats: finalStats, Sets: sets, Warnings: problems.Strings()}
+}
+
+// GearListResult is every item allowed by a filter, with every gem and enchant.
+type GearListResult struct {
+	Items    []tbc.Item
+	Gems     []tbc.Gem
+	Enchants []tbc.Enchant
+}
+
+func GearList(filter tbc.ItemFilter) GearListResult {
+	return GearListResult{Items: tbc.QueryItems(filter), Gems: tbc.Gems, Enchants: tbc.Enchants}
+}
+
+// GemsRequest finds the best gems for the gear, see tbc.OptimalGems.
+type GemsRequest struct {
+	SimRequest
+	tbc.GemOptimizerOptions
+}
+
+func OptimalGems(req GemsRequest, job *tbc.Job) tbc.GemOptimizerResult {
+	gear, _ := req.Gearlist.Equipment()
+	opts := req.Opts.TBC()
+	opts.RSeed = requestSeed(req.Seed, req)
+	opts.Job = job
+	return tbc.OptimalGems(opts, gear, req.Dur, req.Iters, req.GemOptimizerOptions)
+}
+
+// UpgradesRequest ranks the upgrades for each slot of the gear, see tbc.FindUpgrades.
+type UpgradesRequest struct {
+	SimRequest
+	tbc.UpgradeOptions
+}
+
+func FindUpgrades(req UpgradesRequest, job *tbc.Job) tbc.UpgradeResult {
+	gear, _ := req.Gearlist.Equipment()
+	opts := req.Opts.TBC()
+	opts.RSeed = requestSeed(req.Seed, req)
+	opts.Job = job
+	return tbc.FindUpgrades(opts, gear, req.Dur, req.Iters, req.UpgradeOptions)
+}
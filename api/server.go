@@ -0,0 +1,316 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"runtime"
+	"strings"
+
+	"github.com/lologarithm/wowsim/tbc"
+	"github.com/lologarithm/wowsim/wire"
+)
+
+// Limits keep a sim server from being tied up by a few huge requests.
+type Limits struct {
+	MaxIterations int   // most iterations of a single sim. Defaults to 100,000.
+	MaxDuration   int   // longest fight in seconds. Defaults to 1,200.
+	MaxRotations  int   // most fixed rotations in a single simulate request. Defaults to 20.
+	MaxConcurrent int   // requests simulating at once, the rest wait for a turn. Defaults to the number of CPUs.
+	MaxBodyBytes  int64 // largest request body. Defaults to 1MB.
+	MaxJobs       int   // most jobs kept, the oldest finished job is dropped to make room. Defaults to 100.
+}
+
+func (l Limits) withDefaults() Limits {
+	if l.MaxIterations <= 0 {
+		l.MaxIterations = 100000
+	}
+	if l.MaxDuration <= 0 {
+		l.MaxDuration = 1200
+	}
+	if l.MaxRotations <= 0 {
+		l.MaxRotations = 20
+	}
+	if l.MaxConcurrent <= 0 {
+		l.MaxConcurrent = runtime.NumCPU()
+	}
+	if l.MaxBodyBytes <= 0 {
+		l.MaxBodyBytes = 1 << 20
+	}
+	if l.MaxJobs <= 0 {
+		l.MaxJobs = 100
+	}
+	return l
+}
+
+// Server serves the sim as a JSON API. Every endpoint takes a POST of its request as JSON:
+//
+//	/api/simulate     SimulateRequest    -> []tbc.SimResult
+//	/api/statweights  StatWeightsRequest -> tbc.StatWeightsResult
+//	/api/computestats ComputeStatsRequest -> ComputeStatsResult
+//	/api/gearlist     tbc.ItemFilter     -> GearListResult (GET for every item)
+//	/api/gems         GemsRequest        -> tbc.GemOptimizerResult
+//	/api/upgrades     UpgradesRequest    -> tbc.UpgradeResult
+//
+// The endpoints that simulate can also run as a job: a POST to /api/jobs/simulate (or statweights,
+// gems, upgrades) answers right away with the JobStatus, which is polled with a GET of /api/jobs/<id>
+// and cancelled with a DELETE.
+//
+// Requests and results can be binary instead (see package wire and Messages): a request body with
+// a Content-Type of wire.ContentType is read as binary, and a result is written as binary if the
+// Accept header has it. Job statuses and errors are always JSON, check the Content-Type of the response.
+//
+// Errors are {"error": "...", "problems": [...]}, with problems listing what is wrong with the gear.
+type Server struct {
+	limits Limits
+	slots  chan struct{} // a slot is taken for the whole time a request sims.
+	mux    *http.ServeMux
+	jobs   *jobQueue
+}
+
+// simEndpoint checks a request and returns the sim to run for it.
+type simEndpoint func(r *http.Request) (func(job *tbc.Job) interface{}, error)
+
+func NewServer(limits Limits) *Server {
+	s := &Server{limits: limits.withDefaults(), mux: http.NewServeMux()}
+	s.slots = make(chan struct{}, s.limits.MaxConcurrent)
+	s.jobs = newJobQueue(s.limits.MaxJobs)
+
+	s.handleSim("simulate", func(r *http.Request) (func(job *tbc.Job) interface{}, error) {
+		req := SimulateRequest{}
+		if err := s.decode(r, &req); err != nil {
+			return nil, err
+		}
+		if err := s.checkSim(req.SimRequest); err != nil {
+			return nil, err
+		}
+		if len(req.Rots) == 0 && !req.Opts.UseAI {
+			return nil, badRequest("opts.useai must be set if there are no rots")
+		}
+		if len(req.Rots) > s.limits.MaxRotations {
+			return nil, badRequest("at most %d rots can be simulated at once", s.limits.MaxRotations)
+		}
+		for i, rot := range req.Rots {
+			if err := tbc.CheckRotation(rot); err != nil {
+				return nil, badRequest("rots[%d]: %s", i, err)
+			}
+		}
+		if req.FullLogs && req.Iters != 1 {
+			return nil, badRequest("fullLogs needs iters to be 1")
+		}
+		return func(job *tbc.Job) interface{} { return Simulate(req, job) }, nil
+	})
+	s.handleSim("statweights", func(r *http.Request) (func(job *tbc.Job) interface{}, error) {
+		req := StatWeightsRequest{}
+		if err := s.decode(r, &req); err != nil {
+			return nil, err
+		}
+		if err := s.checkSim(req.SimRequest); err != nil {
+			return nil, err
+		}
+		if req.Delta < 0 {
+			return nil, badRequest("delta can't be negative")
+		}
+		return func(job *tbc.Job) interface{} { return StatWeights(req, job) }, nil
+	})
+	s.handle("/api/computestats", false, func(r *http.Request) (interface{}, error) {
+		req := ComputeStatsRequest{}
+		if err := s.decode(r, &req); err != nil {
+			return nil, err
+		}
+		return ComputeStats(req), nil
+	})
+	s.handle("/api/gearlist", true, func(r *http.Request) (interface{}, error) {
+		filter := tbc.ItemFilter{}
+		if r.Method != http.MethodGet {
+			if err := s.decode(r, &filter); err != nil {
+				return nil, err
+			}
+		}
+		return GearList(filter), nil
+	})
+	s.handleSim("gems", func(r *http.Request) (func(job *tbc.Job) interface{}, error) {
+		req := GemsRequest{}
+		if err := s.decode(r, &req); err != nil {
+			return nil, err
+		}
+		if err := s.checkSim(req.SimRequest); err != nil {
+			return nil, err
+		}
+		return func(job *tbc.Job) interface{} { return OptimalGems(req, job) }, nil
+	})
+	s.handleSim("upgrades", func(r *http.Request) (func(job *tbc.Job) interface{}, error) {
+		req := UpgradesRequest{}
+		if err := s.decode(r, &req); err != nil {
+			return nil, err
+		}
+		if err := s.checkSim(req.SimRequest); err != nil {
+			return nil, err
+		}
+		for _, slot := range req.Slots {
+			if !tbc.UpgradeSlot(slot) {
+				return nil, badRequest("slot %d can't be upgraded", slot)
+			}
+		}
+		return func(job *tbc.Job) interface{} { return FindUpgrades(req, job) }, nil
+	})
+	s.mux.HandleFunc("/api/jobs/", s.serveJob)
+	return s
+}
+
+// handleSim serves a sim both right away at /api/<name> and as a job at /api/jobs/<name>.
+func (s *Server) handleSim(name string, endpoint simEndpoint) {
+	s.handle("/api/"+name, false, func(r *http.Request) (interface{}, error) {
+		sim, err := endpoint(r)
+		if err != nil {
+			return nil, err
+		}
+		return s.run(r, sim)
+	})
+	s.handle("/api/jobs/"+name, false, func(r *http.Request) (interface{}, error) {
+		sim, err := endpoint(r)
+		if err != nil {
+			return nil, err
+		}
+		return s.submit(name, sim)
+	})
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// requestError is an error with the request, reported with its status code.
+type requestError struct {
+	status   int
+	message  string
+	problems []string
+}
+
+func (e *requestError) Error() string {
+	return e.message
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return &requestError{status: http.StatusBadRequest, message: fmt.Sprintf(format, args...)}
+}
+
+type errorResponse struct {
+	Error    string   `json:"error"`
+	Problems []string `json:"problems,omitempty"`
+}
+
+// handle serves an endpoint, writing the result as JSON or binary and the error as JSON.
+// Endpoints take a POST, and a GET too if get is set.
+func (s *Server) handle(path string, get bool, endpoint func(r *http.Request) (interface{}, error)) {
+	s.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost && !(get && r.Method == http.MethodGet) {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "requests must be a POST of JSON"})
+			return
+		}
+		res, err := endpoint(r)
+		if err != nil {
+			reqErr := &requestError{}
+			if !errors.As(err, &reqErr) {
+				reqErr = &requestError{status: http.StatusInternalServerError, message: err.Error()}
+			}
+			writeJSON(w, reqErr.status, errorResponse{Error: reqErr.message, Problems: reqErr.problems})
+			return
+		}
+		if strings.Contains(r.Header.Get("Accept"), wire.ContentType) {
+			if out, err := wire.Marshal(res); err == nil {
+				w.Header().Set("Content-Type", wire.ContentType)
+				w.Write(out)
+				return
+			} // results with no binary form, like a job status, are JSON.
+		}
+		writeJSON(w, http.StatusOK, res)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	out, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("Failed to format JSON output: %s", err)
+		status = http.StatusInternalServerError
+		out = []byte(`{"error": "failed to format output"}`)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(out)
+}
+
+// decode reads the request body into v, as JSON or binary by its Content-Type. Unknown JSON fields
+// are an error, so typos don't silently sim the defaults.
+func (s *Server) decode(r *http.Request, v interface{}) error {
+	body := http.MaxBytesReader(nil, r.Body, s.limits.MaxBodyBytes)
+	var err error
+	if r.Header.Get("Content-Type") == wire.ContentType {
+		var data []byte
+		if data, err = ioutil.ReadAll(body); err == nil {
+			err = wire.Unmarshal(data, v)
+		}
+	} else {
+		dec := json.NewDecoder(body)
+		dec.DisallowUnknownFields()
+		err = dec.Decode(v)
+	}
+	if err != nil {
+		if err.Error() == "http: request body too large" {
+			return &requestError{status: http.StatusRequestEntityTooLarge, message: fmt.Sprintf("request is over %d bytes", s.limits.MaxBodyBytes)}
+		}
+		return badRequest("invalid request: %s", err)
+	}
+	return nil
+}
+
+// checkSim validates what every simulating request has, including the gear.
+func (s *Server) checkSim(req SimRequest) error {
+	if req.Iters < 1 || req.Iters > s.limits.MaxIterations {
+		return badRequest("iters must be between 1 and %d", s.limits.MaxIterations)
+	}
+	if req.Dur < 1 || req.Dur > s.limits.MaxDuration {
+		return badRequest("dur must be between 1 and %d seconds", s.limits.MaxDuration)
+	}
+	if len(req.Gearlist) == 0 {
+		return badRequest("gearlist is empty")
+	}
+	_, problems := req.Gearlist.Equipment()
+	if errs := problems.Errors(); len(errs) > 0 {
+		return &requestError{status: http.StatusBadRequest, message: "invalid gear", problems: errs.Strings()}
+	}
+	return nil
+}
+
+// run waits for a free slot and then sims. If the client goes away the sim is cancelled.
+func (s *Server) run(r *http.Request, sim func(job *tbc.Job) interface{}) (interface{}, error) {
+	if !s.takeSlot(r.Context()) {
+		return nil, &requestError{status: http.StatusServiceUnavailable, message: "request cancelled while waiting for a free sim slot"}
+	}
+	defer s.freeSlot()
+	job := tbc.NewJob(r.Context(), nil)
+	res := sim(job)
+	if err := job.Err(); err != nil {
+		return nil, &requestError{status: http.StatusServiceUnavailable, message: "request cancelled while simming"}
+	}
+	return res, nil
+}
+
+// takeSlot waits for a free sim slot, returning false if ctx is done first.
+func (s *Server) takeSlot(ctx context.Context) bool {
+	select {
+	case s.slots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (s *Server) freeSlot() {
+	<-s.slots
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lologarithm/wowsim/tbc"
+)
+
+const testGear = `[{"Name": "Tidefury Helm", "Gems": ["Chaotic Skyfire Diamond", ""]}, {"ID": 28231}, {"Name": "Gavel of Unearthed Secrets"}]`
+
+func request(t *testing.T, s *Server, method string, path string, body string, v interface{}) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("%s %s: invalid JSON response %q: %s", method, path, rec.Body.String(), err)
+	}
+	return rec.Code
+}
+
+func TestSimulate(t *testing.T) {
+	s := NewServer(Limits{})
+	results := []tbc.SimResult{}
+	code := request(t, s, http.MethodPost, "/api/simulate", `{"iters": 20, "dur": 60, "gearlist": `+testGear+`, "opts": {"useai": true}, "rots": [["LB12"]]}`, &results)
+	if code != http.StatusOK || len(results) != 1 || results[0].DPSAvg <= 0 {
+		t.Fatalf("got %d with %+v", code, results)
+	}
+
+	// The same request gets the same seed, so it sims the same (from the cache).
+	again := []tbc.SimResult{}
+	request(t, s, http.MethodPost, "/api/simulate", `{"iters": 20, "dur": 60, "gearlist": `+testGear+`, "opts": {"useai": true}, "rots": [["LB12"]]}`, &again)
+	if len(again) != 1 || again[0].DPSAvg != results[0].DPSAvg || !reflect.DeepEqual(again[0].Casts, results[0].Casts) {
+		t.Fatalf("repeating the request changed the result: %+v, then %+v", results, again)
+	}
+}
+
+func TestRequestErrors(t *testing.T) {
+	s := NewServer(Limits{MaxIterations: 100})
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		errMsg string
+	}{
+		{"get", http.MethodGet, "/api/simulate", ``, http.StatusMethodNotAllowed, "POST"},
+		{"typo", http.MethodPost, "/api/simulate", `{"iter": 10}`, http.StatusBadRequest, `unknown field "iter"`},
+		{"iterations", http.MethodPost, "/api/simulate", `{"iters": 101, "dur": 60, "gearlist": ` + testGear + `}`, http.StatusBadRequest, "iters must be between 1 and 100"},
+		{"no gear", http.MethodPost, "/api/statweights", `{"iters": 10, "dur": 60}`, http.StatusBadRequest, "gearlist is empty"},
+		{"no rotation", http.MethodPost, "/api/simulate", `{"iters": 10, "dur": 60, "gearlist": ` + testGear + `}`, http.StatusBadRequest, "useai"},
+		{"unknown spell", http.MethodPost, "/api/simulate", `{"iters": 10, "dur": 60, "gearlist": ` + testGear + `, "rots": [["NOPE"]]}`, http.StatusBadRequest, `unknown spell "NOPE"`},
+		{"empty rotation", http.MethodPost, "/api/simulate", `{"iters": 10, "dur": 60, "gearlist": ` + testGear + `, "rots": [["CL6"], []]}`, http.StatusBadRequest, "rots[1]: rotation has no spells"},
+		{"upgrade slot", http.MethodPost, "/api/upgrades", `{"iters": 10, "dur": 60, "gearlist": ` + testGear + `, "Slots": [200]}`, http.StatusBadRequest, "slot 200 can't be upgraded"},
+		{"invalid gear", http.MethodPost, "/api/gems", `{"iters": 10, "dur": 60, "gearlist": [{"Name": "Tidefury Chestpiece"}, {"ID": 28231}]}`, http.StatusBadRequest, "invalid gear"},
+	}
+	for _, tt := range tests {
+		res := errorResponse{}
+		code := request(t, s, tt.method, tt.path, tt.body, &res)
+		if code != tt.status || !strings.Contains(res.Error, tt.errMsg) {
+			t.Errorf("%s: got %d %q, want %d %q", tt.name, code, res.Error, tt.status, tt.errMsg)
+		}
+		if tt.name == "invalid gear" && len(res.Problems) == 0 {
+			t.Errorf("%s: the gear problems weren't listed", tt.name)
+		}
+	}
+}
+
+func TestGearList(t *testing.T) {
+	s := NewServer(Limits{})
+	res := GearListResult{}
+	if code := request(t, s, http.MethodGet, "/api/gearlist", ``, &res); code != http.StatusOK || len(res.Items) == 0 || len(res.Gems) == 0 || len(res.Enchants) == 0 {
+		t.Fatalf("got %d with %d items, %d gems and %d enchants", code, len(res.Items), len(res.Gems), len(res.Enchants))
+	}
+}
+
+// TestGearRoundTrip checks gear and options come back the same after going to the web UI's shape.
+func TestGearRoundTrip(t *testing.T) {
+	gear := Gear{}
+	if err := json.Unmarshal([]byte(testGear), &gear); err != nil {
+		t.Fatal(err)
+	}
+	equip, problems := gear.Equipment()
+	if len(problems.Errors()) > 0 {
+		t.Fatalf("unexpected problems %v", problems.Strings())
+	}
+	again, _ := GearOf(equip).Equipment()
+	for i := range equip {
+		if equip[i].ID != again[i].ID || len(equip[i].Gems) != len(again[i].Gems) {
+			t.Fatalf("%s: got %d, want %d", tbc.SlotName(byte(i)), again[i].ID, equip[i].ID)
+		}
+	}
+
+	opts := Options{UseAI: true, NumBloodlust: 1, ArcaneInt: true, TotemOfWrath: 1}
+	if got := OptionsOf(opts.TBC()); got != opts {
+		t.Fatalf("options changed:\ngot  %+v\nwant %+v", got, opts)
+	}
+}
+
+func TestJobs(t *testing.T) {
+	s := NewServer(Limits{MaxConcurrent: 1})
+	body := `{"iters": 20, "dur": 60, "gearlist": ` + testGear + `, "opts": {"useai": true}}`
+
+	// The first job takes the only slot, so the second waits in the queue until it is cancelled.
+	done, waiting := JobStatus{}, JobStatus{}
+	request(t, s, http.MethodPost, "/api/jobs/simulate", body, &done)
+	request(t, s, http.MethodPost, "/api/jobs/simulate", strings.Replace(body, `"iters": 20`, `"iters": 100000`, 1), &waiting)
+	if done.ID == "" || done.ID == waiting.ID || done.Type != "simulate" {
+		t.Fatalf("jobs weren't submitted: %+v, %+v", done, waiting)
+	}
+	request(t, s, http.MethodDelete, "/api/jobs/"+waiting.ID, ``, &waiting)
+
+	for _, id := range []string{done.ID, waiting.ID} {
+		status := JobStatus{}
+		for start := time.Now(); time.Since(start) < 10*time.Second; time.Sleep(10 * time.Millisecond) {
+			request(t, s, http.MethodGet, "/api/jobs/"+id, ``, &status)
+			if status.State == JobDone || status.State == JobCancelled {
+				break
+			}
+		}
+		if id == done.ID && (status.State != JobDone || status.Result == nil || status.Progress.Iterations != 20) {
+			t.Errorf("expected the first job to finish with a result, got %+v", status)
+		}
+		if id == waiting.ID && (status.State != JobCancelled || status.Result != nil) {
+			t.Errorf("expected the second job to be cancelled, got %+v", status)
+		}
+	}
+
+	res := errorResponse{}
+	if code := request(t, s, http.MethodGet, "/api/jobs/nope", ``, &res); code != http.StatusNotFound {
+		t.Errorf("expected an unknown job to be not found, got %d %q", code, res.Error)
+	}
+}
+
+func TestJobCrash(t *testing.T) {
+	s := NewServer(Limits{MaxConcurrent: 1})
+	crashed, err := s.submit("simulate", func(job *tbc.Job) interface{} {
+		var gear tbc.Equipment
+		return gear[1]
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := JobStatus{}
+	id := crashed.(JobStatus).ID
+	for start := time.Now(); time.Since(start) < 10*time.Second && status.State != JobFailed; time.Sleep(10 * time.Millisecond) {
+		request(t, s, http.MethodGet, "/api/jobs/"+id, ``, &status)
+	}
+	if status.State != JobFailed || !strings.Contains(status.Error, "index out of range") {
+		t.Fatalf("expected the job to fail with the panic, got %+v", status)
+	}
+
+	// The slot was freed, so another job still runs.
+	done := JobStatus{}
+	request(t, s, http.MethodPost, "/api/jobs/simulate", `{"iters": 5, "dur": 60, "gearlist": `+testGear+`, "opts": {"useai": true}}`, &done)
+	for start := time.Now(); time.Since(start) < 10*time.Second && done.State != JobDone; time.Sleep(10 * time.Millisecond) {
+		request(t, s, http.MethodGet, "/api/jobs/"+done.ID, ``, &done)
+	}
+	if done.State != JobDone {
+		t.Fatalf("expected a job after the crash to finish, got %+v", done)
+	}
+}
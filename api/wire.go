@@ -0,0 +1,32 @@
+package api
+
+import (
+	"io"
+
+	"github.com/lologarithm/wowsim/tbc"
+	"github.com/lologarithm/wowsim/wire"
+)
+
+//go:generate go run ./wiregen ../ui/wire.js
+
+// Messages are the requests and results with a binary form, see package wire. ui/wire.js has
+// a decoder and encoder of each, named after the message.
+var Messages = []wire.Message{
+	{Name: "SimulateRequest", Value: SimulateRequest{}},
+	{Name: "SimResults", Value: []tbc.SimResult{}},
+	{Name: "StatWeightsRequest", Value: StatWeightsRequest{}},
+	{Name: "StatWeightsResult", Value: tbc.StatWeightsResult{}},
+	{Name: "ComputeStatsRequest", Value: ComputeStatsRequest{}},
+	{Name: "ComputeStatsResult", Value: ComputeStatsResult{}},
+	{Name: "ItemFilter", Value: tbc.ItemFilter{}},
+	{Name: "GearListResult", Value: GearListResult{}},
+	{Name: "GemsRequest", Value: GemsRequest{}},
+	{Name: "GemOptimizerResult", Value: tbc.GemOptimizerResult{}},
+	{Name: "UpgradesRequest", Value: UpgradesRequest{}},
+	{Name: "UpgradeResult", Value: tbc.UpgradeResult{}},
+}
+
+// WriteJS writes ui/wire.js, the JS decoders and encoders of the Messages.
+func WriteJS(w io.Writer) error {
+	return wire.GenerateJS(w, "go generate ./api", Messages)
+}
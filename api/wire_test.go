@@ -0,0 +1,110 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lologarithm/wowsim/tbc"
+	"github.com/lologarithm/wowsim/wire"
+)
+
+func TestWireJSUpToDate(t *testing.T) {
+	want := &bytes.Buffer{}
+	if err := WriteJS(want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadFile("../ui/wire.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want.Bytes()) {
+		t.Fatalf("ui/wire.js is out of date, run go generate ./api")
+	}
+}
+
+func TestWireHTTP(t *testing.T) {
+	s := NewServer(Limits{})
+	body := `{"iters": 20, "dur": 60, "gearlist": ` + testGear + `, "opts": {"useai": true}}`
+	want := []tbc.SimResult{}
+	request(t, s, http.MethodPost, "/api/simulate", body, &want)
+
+	req := SimulateRequest{}
+	json.Unmarshal([]byte(body), &req)
+	data, err := wire.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/api/simulate", bytes.NewReader(data))
+	r.Header.Set("Content-Type", wire.ContentType)
+	r.Header.Set("Accept", wire.ContentType)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, r)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != wire.ContentType {
+		t.Fatalf("got %d %s: %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+	got := []tbc.SimResult{}
+	if err := wire.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want[0].RealDuration, got[0].RealDuration = 0, 0
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("binary result differs from JSON:\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+// TestWireJS checks the generated JS decodes to what JSON.parse of the JSON gives,
+// and encodes what the JSON decodes to.
+func TestWireJS(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("no node to run the JS with")
+	}
+	run := func(script string, args ...string) string {
+		t.Helper()
+		out, err := exec.Command(node, append([]string{"-e", script, "../ui/wire.js"}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("%s: %s", err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	sameJSON := func(what string, got []byte, want []byte) {
+		t.Helper()
+		var g, w interface{}
+		json.Unmarshal(got, &g)
+		json.Unmarshal(want, &w)
+		if !reflect.DeepEqual(g, w) {
+			t.Fatalf("%s differs:\ngot  %s\nwant %s", what, got, want)
+		}
+	}
+
+	res := Simulate(SimulateRequest{SimRequest: SimRequest{Iters: 20, Dur: 60, Gearlist: Gear{{Name: "Tidefury Helm"}}, Opts: Options{UseAI: true}}}, nil)
+	data, _ := wire.Marshal(res)
+	decoded := run(`const wire = require(process.argv[1]);
+console.log(JSON.stringify(wire.decodeSimResults(Buffer.from(process.argv[2], "base64"))));`, base64.StdEncoding.EncodeToString(data))
+	want, _ := json.Marshal(res)
+	sameJSON("decoded SimResults", []byte(decoded), want)
+
+	body := `{"iters": 20, "dur": 60, "gearlist": ` + testGear + `, "opts": {"useai": true, "buffbl": 2, "custom": {"custsp": 10}}, "rots": [["CL6", "LB12"]]}`
+	encoded := run(`const wire = require(process.argv[1]);
+console.log(Buffer.from(wire.encodeSimulateRequest(JSON.parse(process.argv[2]))).toString("base64"));`, body)
+	data, err = base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, wantReq := SimulateRequest{}, SimulateRequest{}
+	if err := wire.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	json.Unmarshal([]byte(body), &wantReq)
+	if !reflect.DeepEqual(got, wantReq) {
+		t.Fatalf("encoded SimulateRequest differs:\ngot  %+v\nwant %+v", got, wantReq)
+	}
+}
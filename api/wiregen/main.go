@@ -0,0 +1,24 @@
+// Command wiregen writes the JS decoders and encoders of the API's binary messages to a file.
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/lologarithm/wowsim/api"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatalf("Usage: wiregen out.js")
+	}
+	buf := &bytes.Buffer{}
+	if err := api.WriteJS(buf); err != nil {
+		log.Fatalf("Failed to generate: %s", err)
+	}
+	if err := ioutil.WriteFile(os.Args[1], buf.Bytes(), 0644); err != nil {
+		log.Fatalf("Failed to write: %s", err)
+	}
+}
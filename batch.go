@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+
+	"github.com/lologarithm/wowsim/tbc"
+)
+
+// batchFile lists the profiles to compare with the batch command.
+type batchFile struct {
+	Base     string // config file of the baseline, the profile from --config, --import or --from-link if empty.
+	Profiles []batchProfile
+}
+
+// batchProfile is a single profile of a batch: a config file of its own, changes to the base
+// profile, or changes to a config file.
+type batchProfile struct {
+	Name   string
+	Config string // config file in the --config format, the base profile if empty.
+
+	// Options to change, in the config format. Only the options given are changed.
+	Options json.RawMessage
+	// Items to wear instead of the items in the same slots, in the config format.
+	// Rings and trinkets replace the second ring or trinket unless Replace or Remove names the one to take off.
+	Gear []swapItem
+	// Names of items to take off.
+	Remove []string
+}
+
+// batchResult is a single profile of a batch compared to the base.
+type batchResult struct {
+	Name string
+	Base bool `json:",omitempty"`
+	tbc.Comparison
+}
+
+func runBatch(args []string) {
+	fs, c := newFlagSet("batch", "text")
+	parse(fs, c, args)
+
+	batch := batchFile{}
+	dir := "."
+	switch fs.NArg() {
+	case 0:
+		fs.Usage()
+		log.Fatalf("batch needs a batch file or config files to compare")
+	case 1:
+		data, err := ioutil.ReadFile(fs.Arg(0))
+		if err != nil {
+			log.Fatalf("Failed to open batch file(%s): %s", fs.Arg(0), err)
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&batch); err != nil {
+			log.Fatalf("Failed to read batch file(%s): %s", fs.Arg(0), err)
+		}
+		dir = filepath.Dir(fs.Arg(0))
+	default:
+		batch.Base = fs.Arg(0)
+		for _, file := range fs.Args()[1:] {
+			batch.Profiles = append(batch.Profiles, batchProfile{Config: file})
+		}
+	}
+	if len(batch.Profiles) == 0 {
+		log.Fatalf("batch has no profiles to compare")
+	}
+
+	var base tbc.Setup
+	baseName := batch.Base
+	if batch.Base != "" {
+		if c.hasProfile() {
+			log.Fatalf("batch takes the base profile from either --config/--import/--from-link or the batch, not both")
+		}
+		base = loadSetup(c, relativeTo(dir, batch.Base))
+		c.loaded(baseName, base.Equip, base.Options)
+	} else {
+		base.Equip, base.Options = c.loadProfile()
+		baseName = profileName(c)
+	}
+
+	setups := make([]tbc.Setup, len(batch.Profiles))
+	names := make([]string, len(batch.Profiles))
+	for i, p := range batch.Profiles {
+		names[i] = p.Name
+		if names[i] == "" {
+			names[i] = p.Config
+		}
+		if names[i] == "" {
+			names[i] = fmt.Sprintf("Profile %d", i+1)
+		}
+		setups[i] = p.setup(c, base, dir, names[i])
+		c.loaded(names[i], setups[i].Equip, setups[i].Options)
+	}
+	c.resolvedDone()
+
+	comps := tbc.CompareSetups(base, setups, c.duration, c.iterations)
+	results := []batchResult{{Name: baseName, Base: true}}
+	for i, comp := range comps {
+		results = append(results, batchResult{Name: names[i], Comparison: comp})
+		if comp.Iterations >= results[0].Iterations {
+			// base DPS over the most iterations any profile was paired with.
+			results[0].Comparison = tbc.Comparison{
+				BaseDPS: comp.BaseDPS, BaseStdev: comp.BaseStdev,
+				DPS: comp.BaseDPS, Stdev: comp.BaseStdev,
+				Iterations: comp.Iterations,
+			}
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].DPS > results[j].DPS })
+	c.output(results, func() { printBatch(results) }, batchTables(results))
+}
+
+// setup builds the profile: the config file or the base, with the changes made.
+// Problems with the gear exit.
+func (p batchProfile) setup(c *commonFlags, base tbc.Setup, dir string, name string) tbc.Setup {
+	s := base
+	if p.Config != "" {
+		s = loadSetup(c, relativeTo(dir, p.Config))
+	}
+	if len(p.Options) > 0 {
+		opts, err := overlayOptions(s.Options, p.Options)
+		if err != nil {
+			log.Fatalf("Invalid options in batch profile %s: %s", name, err)
+		}
+		s.Options = opts
+	}
+	if len(p.Gear) > 0 || len(p.Remove) > 0 {
+		var problems tbc.GearProblems
+		s.Equip, problems = swapGear(s.Equip, p.Gear, p.Remove)
+		checkGear(name, problems)
+	}
+	return s
+}
+
+// profileName names the profile from --config, --import or --from-link.
+func profileName(c *commonFlags) string {
+	switch {
+	case c.fromLink != "":
+		return "link"
+	case c.importFile != "":
+		return c.importFile
+	case c.config != "":
+		return c.config
+	}
+	return "default profile"
+}
+
+func printBatch(results []batchResult) {
+	fmt.Printf("\nProfiles by DPS (90%% confidence of the difference to the base in brackets):\n")
+	for i, r := range results {
+		if r.Base {
+			fmt.Printf("%3d. %-30s %7.1f +/- %0.1f DPS\t(base)\n", i+1, r.Name, r.DPS, r.Stdev)
+			continue
+		}
+		low, high := r.Conf90()
+		fmt.Printf("%3d. %-30s %7.1f +/- %0.1f DPS\t%+0.1f (%+0.1f%%) [%+0.1f, %+0.1f]\n", i+1, r.Name, r.DPS, r.Stdev, r.Delta, deltaPct(r.Comparison), low, high)
+	}
+}
+
+func batchTables(results []batchResult) []table {
+	t := table{title: "Profiles", header: []string{"Rank", "Name", "Base", "DPS", "Stdev", "Delta", "DeltaPct", "DeltaErr", "Low90", "High90", "Iterations"}}
+	for i, r := range results {
+		low, high := r.Conf90()
+		t.add(i+1, r.Name, r.Base, r.DPS, r.Stdev, r.Delta, deltaPct(r.Comparison), r.DeltaErr, low, high, r.Iterations)
+	}
+	return []table{t}
+}
+
+// deltaPct is the DPS difference as a percent of the base DPS.
+func deltaPct(c tbc.Comparison) float64 {
+	if c.BaseDPS == 0 {
+		return 0
+	}
+	return c.Delta / c.BaseDPS * 100
+}
@@ -0,0 +1,117 @@
+package cluster
+
+import (
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/lologarithm/wowsim/tbc"
+)
+
+// testWorker is a worker on localhost that can drop its connections, like a worker that crashed.
+type testWorker struct {
+	net.Listener
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func (tw *testWorker) Accept() (net.Conn, error) {
+	conn, err := tw.Listener.Accept()
+	if err == nil {
+		tw.mu.Lock()
+		tw.conns = append(tw.conns, conn)
+		tw.mu.Unlock()
+	}
+	return conn, err
+}
+
+func (tw *testWorker) crash() {
+	tw.Close()
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	for _, conn := range tw.conns {
+		conn.Close()
+	}
+}
+
+func startWorkers(t *testing.T, n int) ([]*testWorker, []string) {
+	workers, addrs := []*testWorker{}, []string{}
+	for i := 0; i < n; i++ {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		tw := &testWorker{Listener: l}
+		go Serve(tw)
+		t.Cleanup(tw.crash)
+		workers = append(workers, tw)
+		addrs = append(addrs, l.Addr().String())
+	}
+	return workers, addrs
+}
+
+func TestCoordinator(t *testing.T) {
+	// The workers run in this process, so without the cache the local results would come from the workers' sims.
+	tbc.SetCacheSize(0)
+	defer tbc.SetCacheSize(4 << 20)
+
+	equip := tbc.NewEquipmentSet("Tidefury Helm", "Tidefury Chestpiece", "Gavel of Unearthed Secrets", "Quagmirran's Eye")
+	opts := tbc.Options{RSeed: 3, Talents: tbc.Talents{Concussion: 5, CallOfThunder: 5, LightninOverload: 5}}
+	wopts := tbc.StatWeightOptions{Stats: []tbc.Stat{tbc.StatSpellDmg, tbc.StatSpellCrit, tbc.StatSpellHit}}
+	want := tbc.CalculateStatWeights(opts, equip, 60, 200, wopts)
+
+	workers, addrs := startWorkers(t, 3)
+	c, err := Connect(addrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	tbc.SetRemoteRunner(c)
+	defer tbc.SetRemoteRunner(nil)
+
+	if got := tbc.CalculateStatWeights(opts, equip, 60, 200, wopts); !reflect.DeepEqual(got, want) {
+		t.Fatalf("stat weights from the workers differ:\ngot  %+v\nwant %+v", got, want)
+	}
+
+	// Losing workers moves their sims to the one left.
+	workers[0].crash()
+	workers[1].crash()
+	if got := tbc.CalculateStatWeights(opts, equip, 60, 200, wopts); !reflect.DeepEqual(got, want) {
+		t.Fatalf("stat weights after losing workers differ:\ngot  %+v\nwant %+v", got, want)
+	}
+	c.mu.Lock()
+	alive := c.alive
+	c.mu.Unlock()
+	if alive != 1 {
+		t.Fatalf("expected 1 worker left, got %d", alive)
+	}
+}
+
+func TestConnectErrors(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	if _, err := Connect([]string{addr}); err == nil {
+		t.Fatalf("expected connecting to a closed port to fail")
+	}
+}
+
+func TestWorkerLimits(t *testing.T) {
+	equip := tbc.NewEquipmentSet("Tidefury Helm")
+	stats := tbc.CalculateTotalStats(tbc.Options{}, equip)
+	req := tbc.DPSRequest{Setup: tbc.Setup{Equip: equip}.Pack(), UseAI: true, Stats: stats, Seconds: 60, Iterations: 10, Data: tbc.DataHash()}
+	if _, err := tbc.RunDPSRequest(req); err != nil {
+		t.Fatal(err)
+	}
+	big, long, noStats := req, req, req
+	big.Iterations, long.Seconds, noStats.Stats = 1<<30, 1<<30, nil
+	for _, req := range []tbc.DPSRequest{big, long, noStats} {
+		if _, err := tbc.RunDPSRequest(req); err == nil {
+			t.Errorf("expected %d iterations of %d seconds with %d stats to be refused", req.Iterations, req.Seconds, len(req.Stats))
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/rpc"
+	"sync"
+
+	"github.com/lologarithm/wowsim/tbc"
+)
+
+// Coordinator sends sims to workers, each worker getting as many at once as it has CPUs.
+// A worker that disconnects is dropped and its sims go to the other workers.
+// Set it with tbc.SetRemoteRunner.
+type Coordinator struct {
+	slots chan *worker // a worker is in here once for every sim it can take.
+
+	mu      sync.Mutex
+	workers []*worker
+	alive   int
+	gone    chan struct{} // closed once every worker is gone.
+}
+
+type worker struct {
+	addr   string
+	client *rpc.Client
+	lost   bool
+}
+
+// Connect connects to the workers at addrs (host:port), which must have the same item data loaded.
+func Connect(addrs []string) (*Coordinator, error) {
+	c := &Coordinator{gone: make(chan struct{})}
+	infos := make([]Info, len(addrs))
+	for i, addr := range addrs {
+		client, err := rpc.Dial("tcp", addr)
+		if err == nil {
+			err = client.Call("Worker.Info", InfoArgs{}, &infos[i])
+			if err == nil && infos[i].Data != tbc.DataHash() {
+				err = errors.New("it has different item data loaded, start it with the same --data files")
+			}
+			if err != nil {
+				client.Close()
+			}
+		}
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("worker %s: %w", addr, err)
+		}
+		c.workers = append(c.workers, &worker{addr: addr, client: client})
+	}
+	total := 0
+	for _, info := range infos {
+		total += info.CPUs
+	}
+	c.slots = make(chan *worker, total)
+	for i, w := range c.workers {
+		for n := 0; n < infos[i].CPUs; n++ {
+			c.slots <- w
+		}
+	}
+	c.alive = len(c.workers)
+	if c.alive == 0 {
+		close(c.gone)
+	}
+	return c, nil
+}
+
+// RunDPS sims the request on the next free worker, trying another if a worker is lost on the way.
+func (c *Coordinator) RunDPS(ctx context.Context, req tbc.DPSRequest) ([]float64, error) {
+	for {
+		var w *worker
+		select {
+		case w = <-c.slots:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.gone:
+			return nil, errors.New("every worker is gone")
+		}
+		if c.isLost(w) {
+			continue // its slot isn't given back.
+		}
+
+		var dps []float64
+		call := w.client.Go("Worker.RunDPS", req, &dps, make(chan *rpc.Call, 1))
+		select {
+		case <-call.Done:
+		case <-ctx.Done():
+			go func() {
+				<-call.Done // the worker keeps simming, it's only free once it's done.
+				c.release(w, call.Error)
+			}()
+			return nil, ctx.Err()
+		}
+		c.release(w, call.Error)
+		var serverErr rpc.ServerError
+		if call.Error == nil || errors.As(call.Error, &serverErr) {
+			return dps, call.Error // a server error is a problem with the request, another worker won't do better.
+		}
+	}
+}
+
+// release gives back the slot of w after a call, unless the call lost the connection to it.
+func (c *Coordinator) release(w *worker, err error) {
+	var serverErr rpc.ServerError
+	if err == nil || errors.As(err, &serverErr) {
+		c.slots <- w
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if w.lost {
+		return
+	}
+	log.Printf("Lost worker %s: %s", w.addr, err)
+	w.lost = true
+	w.client.Close()
+	c.alive--
+	if c.alive == 0 {
+		close(c.gone)
+	}
+}
+
+func (c *Coordinator) isLost(w *worker) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return w.lost
+}
+
+// Close disconnects from every worker.
+func (c *Coordinator) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, w := range c.workers {
+		w.client.Close()
+	}
+}
@@ -0,0 +1,46 @@
+// Package cluster spreads sims over worker processes, on this machine or others on the network.
+//
+// A worker serves sims over net/rpc. A Coordinator connects to workers and is set as the
+// tbc.RemoteRunner, so every setup the optimizers sim (every candidate, stat weight variant or
+// comparison) goes to whichever worker has a free CPU. The iterations of a setup are simmed
+// with its seed on one worker, so results are the same as simming them all in one process.
+package cluster
+
+import (
+	"net"
+	"net/rpc"
+	"runtime"
+
+	"github.com/lologarithm/wowsim/tbc"
+)
+
+// Worker is the RPC service of a worker process.
+type Worker struct{}
+
+// InfoArgs is the (empty) request for Worker.Info.
+type InfoArgs struct{}
+
+// Info is what a coordinator needs to know about a worker before sending it sims.
+type Info struct {
+	Data string // tbc.DataHash of the item data the worker has loaded.
+	CPUs int    // sims the worker can run at once.
+}
+
+func (w *Worker) Info(_ InfoArgs, reply *Info) error {
+	*reply = Info{Data: tbc.DataHash(), CPUs: runtime.NumCPU()}
+	return nil
+}
+
+// RunDPS sims a setup and replies with the DPS of each iteration.
+func (w *Worker) RunDPS(req tbc.DPSRequest, reply *[]float64) error {
+	dps, err := tbc.RunDPSRequest(req)
+	*reply = dps
+	return err
+}
+
+// Serve serves sims to coordinators on l until l is closed.
+func Serve(l net.Listener) {
+	srv := rpc.NewServer()
+	srv.Register(&Worker{})
+	srv.Accept(l)
+}
@@ -0,0 +1,446 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lologarithm/wowsim/api"
+	"github.com/lologarithm/wowsim/cluster"
+	"github.com/lologarithm/wowsim/tbc"
+)
+
+// command is a subcommand of the binary, like wowsim sim or wowsim upgrades.
+type command struct {
+	name    string
+	args    string // positional arguments, for the usage line.
+	summary string
+	run     func(args []string)
+}
+
+var commands []command
+
+func init() {
+	// Set in init so the commands can print usage from the list.
+	commands = []command{
+		{"sim", "", "Simulate the profile and print DPS, casts and mana use.", runSim},
+		{"weights", "", "Calculate stat weights of the profile.", runWeights},
+		{"gems", "", "Find the best gems for the profile's gear.", runGems},
+		{"upgrades", "", "Simulate every item in place of the current gear and rank the upgrades for each slot.", runUpgrades},
+		{"compare", "[base] other", "Compare the DPS of two config files, or of the profile and a config file.", runCompare},
+		{"batch", "batch.json | base.json other.json ...", "Compare a list of profiles, or changes to a base profile, ranked by DPS.", runBatch},
+		{"optgear", "", "Search every item for the best full set of gear.", runOptGear},
+		{"buffs", "", "Rank every buff, debuff, totem and consumable by the DPS it adds.", runBuffs},
+		{"cooldowns", "", "Compare every cooldown policy at a list of fight durations.", runCooldowns},
+		{"sweep", "stat:from:to:step [stat:from:to:step]", "Simulate DPS across a range of a stat, or a grid of two stats.", runSweep},
+		{"items", "[slot ...]", "List the items in the item data for some slots, or all of them.", runItems},
+		{"link", "", "Print a share link of the profile, for the web UI or --from-link.", runLink},
+		{"web", "", "Serve the web interface and the JSON API.", runWeb},
+		{"worker", "", "Sim for other wowsim processes started with --workers.", runWorker},
+	}
+}
+
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// newFlagSet makes the flag set of a command with the common flags registered.
+// format is the default output format of the command.
+func newFlagSet(name string, format string) (*flag.FlagSet, *commonFlags) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		cmd, _ := findCommand(name)
+		fmt.Fprintf(fs.Output(), "Usage: wowsim %s [flags] %s\n\n%s\n\nFlags:\n", name, cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+	c := &commonFlags{}
+	c.register(fs, format)
+	return fs, c
+}
+
+// parse parses the flags of a command and loads the --data files.
+// An unknown --format or an --iter below 1 exits here, before any simulating.
+func parse(fs *flag.FlagSet, c *commonFlags, args []string) {
+	fs.Parse(args)
+	c.checkFormat()
+	if c.iterations < 1 {
+		log.Fatalf("--iter must be at least 1, got %d", c.iterations)
+	}
+	c.loadData()
+}
+
+// spellOrders are the fixed rotations simulated by sim --fixed.
+var spellOrders = [][]string{
+	{"CL6", "LB12", "LB12", "LB12"},
+	{"CL6", "LB12", "LB12", "LB12", "LB12"},
+	{"CL6", "LB12", "LB12", "LB12", "LB12", "LB12"},
+	{"pri", "CL6", "LB12"}, // cast CL whenever off CD, otherwise LB
+}
+
+func runSim(args []string) {
+	fs, c := newFlagSet("sim", "text")
+	rotation := fs.String("rotation", "", "Custom comma separated rotation to simulate instead of the AI.\n\tFor Example: --rotation=CL6,LB12")
+	fixed := fs.Bool("fixed", false, "Also simulate a few fixed CL:LB rotations next to the AI.")
+	parse(fs, c, args)
+	gear, opt := c.profile()
+
+	rotations := [][]string{{"AI"}}
+	if *rotation != "" {
+		rotations = [][]string{strings.Split(*rotation, ",")}
+		if err := tbc.CheckRotation(rotations[0]); err != nil {
+			log.Fatalf("Invalid --rotation: %s", err)
+		}
+	} else if *fixed {
+		rotations = append(spellOrders, rotations...)
+	}
+
+	stats := tbc.CalculateTotalStats(opt, gear)
+	res := simOutput{Duration: c.duration, Stats: statMap(stats), Results: make([]tbc.SimResult, len(rotations))}
+	if opt.Precision.StdErr > 0 {
+		opt.Job.Phase("simulating", 0)
+	} else {
+		opt.Job.Phase("simulating", len(rotations)*c.iterations)
+	}
+	done := make(chan bool, len(rotations))
+	for i, spells := range rotations {
+		run := func(i int, spells []string) {
+			res.Results[i] = simRotation(spells, stats, gear, opt, c.duration, c.iterations)
+			done <- true
+		}
+		if opt.Debug {
+			run(i, spells) // keep the logs of each rotation apart.
+		} else {
+			go run(i, spells)
+		}
+	}
+	for range rotations {
+		<-done
+	}
+	c.output(res, func() { printSimOutput(res, stats) }, simTables(res))
+}
+
+func runWeights(args []string) {
+	fs, c := newFlagSet("weights", "text")
+	delta := fs.Float64("swdelta", 50, "Amount of each stat to add when calculating stat weights.")
+	central := fs.Bool("swcentral", false, "Also subtract each stat and use central differences for stat weights.")
+	parse(fs, c, args)
+	gear, opt := c.profile()
+
+	res := tbc.CalculateStatWeights(opt, gear, c.duration, c.iterations, tbc.StatWeightOptions{Delta: *delta, Central: *central})
+	c.output(res, func() { printStatWeights(res) }, weightTables(res))
+}
+
+func runGems(args []string) {
+	fs, c := newFlagSet("gems", "text")
+	delta := fs.Float64("swdelta", 50, "Amount of each stat to add when calculating the stat weights used to rank gems.")
+	phase := fs.Int("phase", 0, "Only use gems from this phase or earlier, 0 allows every phase.")
+	parse(fs, c, args)
+	gear, opt := c.profile()
+
+	weights := tbc.CalculateStatWeights(opt, gear, c.duration, c.iterations, tbc.StatWeightOptions{Delta: *delta}).Vector()
+	res := tbc.OptimalGems(opt, gear, c.duration, c.iterations, tbc.GemOptimizerOptions{Weights: weights, MaxPhase: byte(*phase)})
+	c.output(res, func() { printGemResult(res) }, gemTables(res))
+}
+
+// itemFilterFlags are the flags of the commands that pick items from the item data.
+type itemFilterFlags struct {
+	phase       *int
+	armor       *string
+	professions *string
+}
+
+func registerItemFilter(fs *flag.FlagSet) itemFilterFlags {
+	return itemFilterFlags{
+		phase:       fs.Int("phase", 0, "Only use items and gems from this phase or earlier, 0 allows every phase."),
+		armor:       fs.String("armor", "", "Comma separated armor types to allow, like mail."),
+		professions: fs.String("professions", "", "Comma separated professions the character has, items needing other professions are left out.\n\tUse none for no professions, leave empty to allow every profession."),
+	}
+}
+
+func (f itemFilterFlags) filter() tbc.ItemFilter {
+	return parseItemFilter(byte(*f.phase), *f.armor, *f.professions)
+}
+
+func runUpgrades(args []string) {
+	fs, c := newFlagSet("upgrades", "text")
+	items := registerItemFilter(fs)
+	topN := fs.Int("topn", 5, "Number of upgrades to print per slot.")
+	parse(fs, c, args)
+	gear, opt := c.profile()
+
+	res := tbc.FindUpgrades(opt, gear, c.duration, c.iterations, tbc.UpgradeOptions{ItemFilter: items.filter(), TopN: *topN})
+	c.output(res, func() { printUpgrades(res) }, upgradeTables(res))
+}
+
+func runOptGear(args []string) {
+	fs, c := newFlagSet("optgear", "text")
+	items := registerItemFilter(fs)
+	parse(fs, c, args)
+	gear, opt := c.profile()
+
+	res := tbc.OptimalGear(opt, gear, c.duration, c.iterations, tbc.GearOptimizerOptions{ItemFilter: items.filter()})
+	c.output(res, func() { printGearResult(res) }, gearTables(res))
+}
+
+func runCompare(args []string) {
+	fs, c := newFlagSet("compare", "text")
+	parse(fs, c, args)
+
+	var base, other tbc.Setup
+	var baseName, otherName string
+	switch fs.NArg() {
+	case 1:
+		base.Equip, base.Options = c.loadProfile()
+		baseName, otherName = profileName(c), fs.Arg(0)
+	case 2:
+		if c.hasProfile() {
+			log.Fatalf("compare takes either --config/--import/--from-link and one file, or two files")
+		}
+		baseName, otherName = fs.Arg(0), fs.Arg(1)
+		base = loadSetup(c, baseName)
+		c.loaded(baseName, base.Equip, base.Options)
+	default:
+		fs.Usage()
+		log.Fatalf("compare needs one or two config files")
+	}
+	other = loadSetup(c, otherName)
+	c.loaded(otherName, other.Equip, other.Options)
+	c.resolvedDone()
+
+	res := compareOutput{
+		Base:       baseName,
+		Other:      otherName,
+		Comparison: tbc.Compare(base, other, c.duration, c.iterations),
+		Gear:       gearChanges(base.Equip, other.Equip),
+		Stats:      statChanges(tbc.CalculateTotalStats(base.Options, base.Equip), tbc.CalculateTotalStats(other.Options, other.Equip)),
+	}
+	c.output(res, func() { printComparison(res) }, compareTables(res))
+}
+
+// loadSetup loads a config file for compare, applying the option flags.
+func loadSetup(c *commonFlags, file string) tbc.Setup {
+	gear, opt, problems := loadConfig(file)
+	checkGear(file, problems)
+	return tbc.Setup{Options: c.apply(opt), Equip: gear}
+}
+
+func runBuffs(args []string) {
+	fs, c := newFlagSet("buffs", "text")
+	parse(fs, c, args)
+	gear, opt := c.profile()
+
+	res := tbc.BuffValues(opt, gear, c.duration, c.iterations)
+	c.output(res, func() { printBuffValues(res) }, buffTables(res))
+}
+
+func runCooldowns(args []string) {
+	fs, c := newFlagSet("cooldowns", "text")
+	durationList := fs.String("durations", "120,180,300", "Comma separated fight durations to compare every cooldown policy at.")
+	parse(fs, c, args)
+	gear, opt := c.profile()
+
+	durations := []int{}
+	for _, v := range strings.Split(*durationList, ",") {
+		dur, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			log.Fatalf("Invalid duration in --durations: %s", v)
+		}
+		durations = append(durations, dur)
+	}
+	res := tbc.EvaluateCooldownPolicies(opt, gear, nil, durations, c.iterations)
+	c.output(res, func() { printCooldownPolicies(res) }, cooldownTables(res))
+}
+
+func runSweep(args []string) {
+	fs, c := newFlagSet("sweep", "csv")
+	parse(fs, c, args)
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		log.Fatalf("sweep needs one or two stat ranges, like hit:0:200:10")
+	}
+	gear, opt := c.profile()
+
+	sopts := tbc.ScalingOptions{X: parseSweep(fs.Arg(0))}
+	if fs.NArg() == 2 {
+		y := parseSweep(fs.Arg(1))
+		sopts.Y = &y
+	}
+	res := tbc.StatScaling(opt, gear, c.duration, c.iterations, sopts)
+	if c.format == "csv" || c.format == "markdown" {
+		printScalingNotes(os.Stderr, res) // keep stdout to just the table.
+	}
+	c.output(res, func() { printScaling(res) }, scalingTables(res))
+}
+
+func runItems(args []string) {
+	fs, c := newFlagSet("items", "text")
+	items := registerItemFilter(fs)
+	parse(fs, c, args)
+
+	filter := items.filter()
+	for _, name := range fs.Args() {
+		if name == "all" {
+			continue
+		}
+		slot, ok := tbc.ParseSlot(strings.TrimSpace(name))
+		if !ok {
+			log.Fatalf("Unknown slot: %s", name)
+		}
+		filter.Slots = append(filter.Slots, slot)
+	}
+	res := tbc.QueryItems(filter)
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Slot < res[j].Slot })
+	c.output(res, func() { printItems(res) }, itemTables(res))
+}
+
+func runLink(args []string) {
+	fs, c := newFlagSet("link", "text")
+	parse(fs, c, args)
+	gear, opt := c.profile()
+
+	res := struct{ Link string }{tbc.Setup{Options: opt, Equip: gear}.Link()}
+	t := table{title: "Link", header: []string{"Link"}}
+	t.add(res.Link)
+	c.output(res, func() { fmt.Println(res.Link) }, []table{t})
+}
+
+func runWeb(args []string) {
+	fs := flag.NewFlagSet("web", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:3333", "Address to serve the web interface on. Use :3333 to let other machines reach it.")
+	limits := api.Limits{}
+	fs.IntVar(&limits.MaxIterations, "maxiter", 100000, "Most iterations of a single sim requested through the JSON API.")
+	fs.IntVar(&limits.MaxDuration, "maxduration", 1200, "Longest fight in seconds that can be requested through the JSON API.")
+	fs.IntVar(&limits.MaxConcurrent, "concurrency", runtime.NumCPU(), "Most JSON API requests to sim at once, the rest wait for a turn.")
+	cacheDir := fs.String("cache-dir", "", "Also keep sim results in this directory, so they are reused after a restart.")
+	workers := fs.String("workers", "", "Comma separated host:port of worker processes (see wowsim worker) to spread the JSON API's sims over.")
+	fs.Parse(args)
+	if err := tbc.SetCacheDir(*cacheDir); err != nil {
+		log.Fatalf("Failed to use the cache directory: %s", err)
+	}
+	connectWorkers(*workers)
+	http.Handle("/api/", api.NewServer(limits))
+	log.Printf("Closing: %s", http.ListenAndServe(*addr, nil))
+}
+
+func runWorker(args []string) {
+	fs := flag.NewFlagSet("worker", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:3334", "Address to take sims on, anyone who can reach it can use the worker.")
+	c := &commonFlags{}
+	fs.StringVar(&c.dataFiles, "data", "", "Comma separated data files to load on top of the built in data, the same as the processes sending sims load.")
+	fs.StringVar(&c.cacheDir, "cache-dir", "", "Also keep sim results in this directory, so they are reused after a restart.")
+	fs.Parse(args)
+	c.loadData()
+	l, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatalf("Failed to listen: %s", err)
+	}
+	log.Printf("Taking sims on %s", l.Addr())
+	cluster.Serve(l)
+}
+
+// simOutput is the output of the sim command.
+type simOutput struct {
+	Duration int
+	Stats    map[string]float64 // total stats of the profile.
+	Results  []tbc.SimResult    // one per rotation.
+}
+
+func simRotation(spells []string, stats tbc.Stats, equip tbc.Equipment, opt tbc.Options, seconds int, numSims int) tbc.SimResult {
+	opt.SpellOrder = spells
+	opt.UseAI = len(spells) == 1 && spells[0] == "AI"
+	opt.RSeed = time.Now().Unix()
+	st := time.Now()
+	res := tbc.SummarizeSim(tbc.NewSim(stats, equip, opt), seconds, numSims)
+	res.Rotation = spells
+	res.RealDuration = time.Now().Sub(st).Seconds()
+	return res
+}
+
+// compareOutput is the output of the compare command.
+type compareOutput struct {
+	Base  string
+	Other string
+	tbc.Comparison
+
+	Gear  []gearChange       // slots with different gear.
+	Stats map[string]float64 // total stats of other minus base, only those that differ.
+}
+
+// gearChange is a slot with different gear in the two profiles of a compare.
+type gearChange struct {
+	Slot  string
+	Base  string
+	Other string
+}
+
+func gearChanges(base, other tbc.Equipment) []gearChange {
+	changes := []gearChange{}
+	for i := 0; i < len(base) || i < len(other); i++ {
+		b, o := "", ""
+		if i < len(base) {
+			b = describeItem(base[i])
+		}
+		if i < len(other) {
+			o = describeItem(other[i])
+		}
+		if b != o {
+			changes = append(changes, gearChange{Slot: tbc.SlotName(byte(i)), Base: b, Other: o})
+		}
+	}
+	return changes
+}
+
+// describeItem names an item with its gems and enchant, empty for an empty slot.
+func describeItem(item tbc.Item) string {
+	if item.Name == "" {
+		return ""
+	}
+	extra := []string{}
+	for _, g := range item.Gems {
+		if g.Name != "" {
+			extra = append(extra, g.Name)
+		}
+	}
+	if item.Enchant.Name != "" {
+		extra = append(extra, item.Enchant.Name)
+	}
+	if len(extra) == 0 {
+		return item.Name
+	}
+	return fmt.Sprintf("%s (%s)", item.Name, strings.Join(extra, ", "))
+}
+
+func statChanges(base, other tbc.Stats) map[string]float64 {
+	changes := map[string]float64{}
+	for i := 0; i < len(base) && i < len(other); i++ {
+		if d := other[i] - base[i]; math.Abs(d) > 0.001 {
+			changes[tbc.Stat(i).StatName()] = d
+		}
+	}
+	return changes
+}
+
+// statMap names each stat, for JSON output.
+func statMap(stats tbc.Stats) map[string]float64 {
+	out := map[string]float64{}
+	for i, v := range stats {
+		if name := tbc.Stat(i).StatName(); name != "none" {
+			out[name] = v
+		}
+	}
+	return out
+}
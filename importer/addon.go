@@ -0,0 +1,104 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// simcItemLine matches an equipped item in a SimulationCraft profile, like:
+//
+//	head=,id=31014,enchant_id=3002,gem_id=34220/24030
+var simcItemLine = regexp.MustCompile(`(?m)^\s*[a-z_0-9]+=[^,\n]*,id=\d+`)
+
+// itemString matches an in-game item string, on its own or inside an item link:
+//
+//	item:itemID:enchantID:gem1:gem2:gem3:gem4:...
+var itemString = regexp.MustCompile(`item:(\d+)((?::-?\d*)*)`)
+
+// SimulationCraft converts a profile from the SimulationCraft addon (/simc).
+// Commented out lines, which the addon uses for items in bags, are skipped.
+func SimulationCraft(data []byte) (Result, error) {
+	b := newBuilder("simc")
+	found := false
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		eq := strings.Index(line, "=")
+		if eq < 0 {
+			continue
+		}
+		slot := line[:eq]
+		if _, ok := slotHints[strings.ToUpper(strings.ReplaceAll(slot, "_", ""))]; !ok {
+			continue // character info, or slots we don't sim like the shirt.
+		}
+		var id int32
+		var gems []int32
+		enchant := enchantRef{}
+		for _, field := range strings.Split(line[eq+1:], ",") {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			switch kv[0] {
+			case "id":
+				id = parseID(kv[1])
+			case "enchant_id":
+				enchant.EffectID = parseID(kv[1])
+			case "gem_id":
+				for _, g := range strings.FieldsFunc(kv[1], func(r rune) bool { return r == '/' || r == ':' }) {
+					gems = append(gems, parseID(g))
+				}
+			}
+		}
+		if id == 0 {
+			continue
+		}
+		found = true
+		b.add(slot, id, "", gems, nil, enchant)
+	}
+	if err := scanner.Err(); err != nil {
+		return Result{}, err
+	}
+	if !found {
+		return Result{}, fmt.Errorf("invalid SimulationCraft profile: no items")
+	}
+	return b.result(), nil
+}
+
+// ItemStrings converts in-game item strings or links, as copied from chat or exported by most gear addons.
+// Item strings don't say which slot they are in, so rings and trinkets go in the first free slot.
+func ItemStrings(data []byte) (Result, error) {
+	b := newBuilder("itemstring")
+	matches := itemString.FindAllStringSubmatch(string(data), -1)
+	if len(matches) == 0 {
+		return Result{}, fmt.Errorf("invalid item strings: no items")
+	}
+	for _, m := range matches {
+		fields := strings.Split(strings.TrimPrefix(m[2], ":"), ":")
+		enchant := enchantRef{}
+		var gems []int32
+		if len(fields) > 0 {
+			enchant.EffectID = parseID(fields[0])
+		}
+		for i := 1; i < len(fields) && i <= 4; i++ {
+			gems = append(gems, parseID(fields[i]))
+		}
+		b.add("", parseID(m[1]), "", gems, nil, enchant)
+	}
+	return b.result(), nil
+}
+
+// parseID parses an ID field, empty or invalid fields are 0.
+func parseID(s string) int32 {
+	v, err := strconv.ParseInt(strings.TrimSpace(s), 10, 32)
+	if err != nil || v < 0 {
+		return 0
+	}
+	return int32(v)
+}
@@ -0,0 +1,223 @@
+// Package importer converts gear exported from community gear planners and in-game addons into tbc.Equipment.
+//
+// Supported formats are the Seventy Upgrades JSON export (from the site or its addon),
+// the SimulationCraft addon profile and plain in-game item links/strings.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lologarithm/wowsim/tbc"
+)
+
+// Result is gear converted from an export.
+type Result struct {
+	Format string // which format the export was read as.
+	Gear   tbc.Equipment
+
+	// Missing is everything in the export that couldn't be mapped to the item data.
+	// The rest of the gear is still imported.
+	Missing []Missing `json:",omitempty"`
+
+	// Problems are what tbc.Equipment.Validate found in the imported gear.
+	Problems tbc.GearProblems `json:",omitempty"`
+}
+
+// Missing is a single item, gem or enchant from an export that isn't in the item data.
+type Missing struct {
+	Slot   string // slot as written in the export, empty if the export doesn't say.
+	Kind   string // item, gem or enchant
+	ID     int32
+	Name   string `json:",omitempty"`
+	Reason string // why it couldn't be imported.
+}
+
+func (m Missing) String() string {
+	what := fmt.Sprintf("%s %d", m.Kind, m.ID)
+	if m.Name != "" {
+		what += fmt.Sprintf(" (%s)", m.Name)
+	}
+	if m.Slot != "" {
+		what = m.Slot + ": " + what
+	}
+	return what + " " + m.Reason
+}
+
+// ErrUnknownFormat is returned by Import when the export doesn't look like any supported format.
+var ErrUnknownFormat = errors.New("unrecognized gear export format")
+
+// Import detects the format of an export and converts it.
+func Import(data []byte) (Result, error) {
+	text := strings.TrimSpace(string(data))
+	switch {
+	case strings.HasPrefix(text, "{"):
+		return SeventyUpgrades(data)
+	case simcItemLine.MatchString(text):
+		return SimulationCraft(data)
+	case itemString.MatchString(text):
+		return ItemStrings(data)
+	}
+	return Result{}, ErrUnknownFormat
+}
+
+// slotHints maps the slot names used by exports, upper cased without spaces or underscores, to equipment slots.
+var slotHints = map[string]byte{
+	"HEAD":      tbc.EquipHead,
+	"NECK":      tbc.EquipNeck,
+	"SHOULDER":  tbc.EquipShoulder,
+	"SHOULDERS": tbc.EquipShoulder,
+	"BACK":      tbc.EquipBack,
+	"CHEST":     tbc.EquipChest,
+	"WRIST":     tbc.EquipWrist,
+	"WRISTS":    tbc.EquipWrist,
+	"HANDS":     tbc.EquipHands,
+	"WAIST":     tbc.EquipWaist,
+	"LEGS":      tbc.EquipLegs,
+	"FEET":      tbc.EquipFeet,
+	"FINGER1":   tbc.EquipFinger1,
+	"FINGER2":   tbc.EquipFinger2,
+	"TRINKET1":  tbc.EquipTrinket1,
+	"TRINKET2":  tbc.EquipTrinket2,
+	"MAINHAND":  tbc.EquipWeapon,
+	"OFFHAND":   tbc.EquipOffhand,
+	"RANGED":    tbc.EquipTotem, // totems are in the ranged slot
+	"RELIC":     tbc.EquipTotem,
+}
+
+// builder collects imported items into equipment.
+type builder struct {
+	res Result
+}
+
+// result validates the imported gear and returns it.
+func (b *builder) result() Result {
+	b.res.Problems = b.res.Gear.Validate()
+	return b.res
+}
+
+func newBuilder(format string) *builder {
+	return &builder{res: Result{Format: format, Gear: make(tbc.Equipment, tbc.EquipTotem+1)}}
+}
+
+func (b *builder) missing(m Missing) {
+	b.res.Missing = append(b.res.Missing, m)
+}
+
+// enchantRef is every way an export can identify an enchant, any of them may be zero.
+type enchantRef struct {
+	Name     string
+	ID       int32 // item or spell ID, whichever the item data uses.
+	ItemID   int32
+	SpellID  int32
+	EffectID int32 // ID of the enchantment, used in item links.
+}
+
+func (er enchantRef) empty() bool {
+	return er.Name == "" && er.ID == 0 && er.ItemID == 0 && er.SpellID == 0 && er.EffectID == 0
+}
+
+func (er enchantRef) find() (tbc.Enchant, bool) {
+	if en, ok := tbc.EnchantLookup[er.Name]; ok && er.Name != "" {
+		return en, true
+	}
+	for _, id := range []int32{er.ID, er.ItemID, er.SpellID} {
+		if en, ok := tbc.EnchantByID[id]; ok && id != 0 {
+			return en, true
+		}
+	}
+	if en, ok := tbc.EnchantByEffectID[er.EffectID]; ok && er.EffectID != 0 {
+		return en, true
+	}
+	return tbc.Enchant{}, false
+}
+
+func (er enchantRef) id() int32 {
+	for _, id := range []int32{er.ID, er.ItemID, er.SpellID, er.EffectID} {
+		if id != 0 {
+			return id
+		}
+	}
+	return 0
+}
+
+// add puts a single item into the gear. slot is the slot name from the export, if it has one.
+// Gem IDs of 0 are empty sockets.
+func (b *builder) add(slot string, id int32, name string, gemIDs []int32, gemNames []string, enchant enchantRef) {
+	item, ok := tbc.ItemsByID[id]
+	if !ok && name != "" {
+		item, ok = tbc.ItemsByName[name]
+	}
+	if !ok {
+		b.missing(Missing{Slot: slot, Kind: "item", ID: id, Name: name, Reason: "is not in the item data"})
+		return
+	}
+
+	equipSlot, ok := b.slotFor(slot, item)
+	if !ok {
+		b.missing(Missing{Slot: slot, Kind: "item", ID: item.ID, Name: item.Name, Reason: "has no free slot"})
+		return
+	}
+
+	item.Gems = make([]tbc.Gem, len(item.GemSlots))
+	for i := 0; i < len(gemIDs) || i < len(gemNames); i++ {
+		gemID, gemName := int32(0), ""
+		if i < len(gemIDs) {
+			gemID = gemIDs[i]
+		}
+		if i < len(gemNames) {
+			gemName = gemNames[i]
+		}
+		if gemID == 0 && gemName == "" {
+			continue
+		}
+		gem, ok := tbc.GemsByID[gemID]
+		if !ok && gemName != "" {
+			gem, ok = tbc.GemLookup[gemName]
+		}
+		if !ok {
+			b.missing(Missing{Slot: slot, Kind: "gem", ID: gemID, Name: gemName, Reason: "is not in the gem data"})
+			continue
+		}
+		if i >= len(item.Gems) {
+			b.missing(Missing{Slot: slot, Kind: "gem", ID: gem.ID, Name: gem.Name, Reason: fmt.Sprintf("doesn't fit, %s has %d sockets", item.Name, len(item.GemSlots))})
+			continue
+		}
+		item.Gems[i] = gem
+	}
+
+	if !enchant.empty() {
+		if en, ok := enchant.find(); !ok {
+			b.missing(Missing{Slot: slot, Kind: "enchant", ID: enchant.id(), Name: enchant.Name, Reason: "is not in the enchant data"})
+		} else if !en.Fits(item) {
+			b.missing(Missing{Slot: slot, Kind: "enchant", ID: en.ID, Name: en.Name, Reason: "doesn't fit " + item.Name})
+		} else {
+			item.Enchant = en
+		}
+	}
+	b.res.Gear[equipSlot] = item
+}
+
+// slotFor picks the equipment slot for an item, using the slot from the export for rings and trinkets.
+func (b *builder) slotFor(slot string, item tbc.Item) (byte, bool) {
+	hint, hinted := slotHints[strings.NewReplacer("_", "", " ", "", "-", "").Replace(strings.ToUpper(slot))]
+	var choices []byte
+	switch item.Slot {
+	case tbc.EquipFinger:
+		choices = []byte{tbc.EquipFinger1, tbc.EquipFinger2}
+	case tbc.EquipTrinket:
+		choices = []byte{tbc.EquipTrinket1, tbc.EquipTrinket2}
+	default:
+		return item.Slot, true
+	}
+	if hinted && (hint == choices[0] || hint == choices[1]) {
+		return hint, true
+	}
+	for _, c := range choices {
+		if b.res.Gear[c].ID == 0 {
+			return c, true
+		}
+	}
+	return 0, false
+}
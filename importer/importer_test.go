@@ -0,0 +1,72 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/lologarithm/wowsim/tbc"
+)
+
+func TestSimulationCraft(t *testing.T) {
+	profile := `shaman="Test"
+level=70
+head=,id=24266,enchant_id=3002,gem_id=24030/24047/24037
+# finger1=,id=23031
+finger1=,id=21709
+finger2=,id=23031,enchant_id=2928
+shirt=,id=4330
+legs=,id=99999
+`
+	res, err := Import([]byte(profile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Format != "simc" {
+		t.Errorf("detected format %s", res.Format)
+	}
+	head := res.Gear[tbc.EquipHead]
+	if head.Name != "Spellstrike Hood" || head.Enchant.Name != "Glyph of Power" {
+		t.Errorf("head imported as %s with %s", head.Name, head.Enchant.Name)
+	}
+	if len(head.Gems) != 3 || head.Gems[0].Name != "Runed Living Ruby" || head.Gems[2].Name != "Lustrous Star of Elune" {
+		t.Errorf("head gems imported as %v", head.Gems)
+	}
+	if res.Gear[tbc.EquipFinger1].ID != 21709 || res.Gear[tbc.EquipFinger2].ID != 23031 || res.Gear[tbc.EquipFinger2].Enchant.Name != "Ring - Spellpower" {
+		t.Errorf("rings imported as %s and %s", res.Gear[tbc.EquipFinger1].Name, res.Gear[tbc.EquipFinger2].Name)
+	}
+	if len(res.Missing) != 1 || res.Missing[0].ID != 99999 || res.Missing[0].Kind != "item" {
+		t.Errorf("expected only the unknown legs to be missing, got %v", res.Missing)
+	}
+}
+
+func TestSeventyUpgrades(t *testing.T) {
+	export := `{"name": "Test", "items": [
+		{"name": "Spellstrike Hood", "id": 24266, "slot": "HEAD", "enchant": {"name": "Glyph of Power", "id": 35447, "itemId": 29191},
+		 "gems": [{"id": 24030}, null, {"id": 12345, "name": "Not A Gem"}]},
+		{"name": "Band of the Inevitable", "id": 23031, "slot": "FINGER_2"}
+	]}`
+	res, err := Import([]byte(export))
+	if err != nil {
+		t.Fatal(err)
+	}
+	head := res.Gear[tbc.EquipHead]
+	if head.Enchant.Name != "Glyph of Power" || head.Gems[0].Name != "Runed Living Ruby" || head.Gems[1].ID != 0 {
+		t.Errorf("head imported as %#v", head)
+	}
+	if res.Gear[tbc.EquipFinger2].ID != 23031 {
+		t.Errorf("ring should keep the slot from the export")
+	}
+	if len(res.Missing) != 1 || res.Missing[0].Kind != "gem" {
+		t.Errorf("expected the unknown gem to be missing, got %v", res.Missing)
+	}
+}
+
+func TestItemStrings(t *testing.T) {
+	links := "|cffa335ee|Hitem:24266:3002:24030:0:0:0:0:0|h[Spellstrike Hood]|h|r\nitem:21709::::"
+	res, err := Import([]byte(links))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Gear[tbc.EquipHead].Enchant.Name != "Glyph of Power" || res.Gear[tbc.EquipFinger1].ID != 21709 || len(res.Missing) != 0 {
+		t.Errorf("item strings imported as %s, %s, missing %v", res.Gear[tbc.EquipHead].Name, res.Gear[tbc.EquipFinger1].Name, res.Missing)
+	}
+}
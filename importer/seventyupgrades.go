@@ -0,0 +1,53 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// seventyUpgradesExport is the part of the Seventy Upgrades export we use.
+// The addon export has the same layout as the export from the site.
+type seventyUpgradesExport struct {
+	Items []struct {
+		ID      int32  `json:"id"`
+		Name    string `json:"name"`
+		Slot    string `json:"slot"`
+		Enchant *struct {
+			ID      int32  `json:"id"`
+			Name    string `json:"name"`
+			ItemID  int32  `json:"itemId"`
+			SpellID int32  `json:"spellId"`
+		} `json:"enchant"`
+		Gems []*struct { // empty sockets are null
+			ID   int32  `json:"id"`
+			Name string `json:"name"`
+		} `json:"gems"`
+	} `json:"items"`
+}
+
+// SeventyUpgrades converts a Seventy Upgrades JSON export.
+func SeventyUpgrades(data []byte) (Result, error) {
+	export := seventyUpgradesExport{}
+	if err := json.Unmarshal(data, &export); err != nil {
+		return Result{}, fmt.Errorf("invalid Seventy Upgrades export: %w", err)
+	}
+	if len(export.Items) == 0 {
+		return Result{}, fmt.Errorf("invalid Seventy Upgrades export: no items")
+	}
+	b := newBuilder("seventyupgrades")
+	for _, item := range export.Items {
+		gemIDs := make([]int32, len(item.Gems))
+		gemNames := make([]string, len(item.Gems))
+		for i, g := range item.Gems {
+			if g != nil {
+				gemIDs[i], gemNames[i] = g.ID, g.Name
+			}
+		}
+		enchant := enchantRef{}
+		if item.Enchant != nil {
+			enchant = enchantRef{Name: item.Enchant.Name, ID: item.Enchant.ID, ItemID: item.Enchant.ItemID, SpellID: item.Enchant.SpellID}
+		}
+		b.add(item.Slot, item.ID, item.Name, gemIDs, gemNames, enchant)
+	}
+	return b.result(), nil
+}
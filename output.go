@@ -0,0 +1,298 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lologarithm/wowsim/tbc"
+)
+
+// formats are the output formats of every command.
+var formats = []string{"text", "json", "csv", "markdown"}
+
+func (c *commonFlags) checkFormat() {
+	for _, f := range formats {
+		if c.format == f {
+			return
+		}
+	}
+	log.Fatalf("Unknown output format %s, expected one of %s", c.format, strings.Join(formats, ", "))
+}
+
+// output prints the result of a command in the --format asked for: the result itself as JSON,
+// its tables as CSV or markdown, or text with print. A sim cancelled with Ctrl-C has no result to print.
+func (c *commonFlags) output(res interface{}, print func(), tables []table) {
+	if c.progress {
+		fmt.Fprintln(os.Stderr)
+	}
+	if err := c.job.Err(); err != nil {
+		log.Fatalf("Stopped before the sim finished: %s", err)
+	}
+	switch c.format {
+	case "json":
+		out, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			log.Fatalf("Failed to format JSON output: %s", err)
+		}
+		fmt.Println(string(out))
+	case "csv":
+		writeCSV(os.Stdout, tables)
+	case "markdown":
+		writeMarkdown(os.Stdout, tables)
+	default:
+		print()
+	}
+}
+
+func printSimOutput(res simOutput, stats tbc.Stats) {
+	fmt.Printf("\nSim Duration: %d sec\n", res.Duration)
+	fmt.Printf("\nFinal Stats: %s\n", stats.Print())
+	for _, r := range res.Results {
+		n := float64(r.Iterations)
+		fmt.Printf("\nSpell Order: %v\n", r.Rotation)
+		fmt.Printf("Iterations: %d\n", r.Iterations)
+		fmt.Printf("DPS:\n")
+		fmt.Printf("\tMean: %0.1f +/- %0.1f\n", r.DPSAvg, r.DPSDev)
+		fmt.Printf("\tMin: %0.1f  Max: %0.1f\n", r.MinDPS, r.MaxDPS)
+		p := r.Percentiles
+		fmt.Printf("\tPercentiles: 5%%: %0.1f  25%%: %0.1f  50%%: %0.1f  75%%: %0.1f  95%%: %0.1f\n", p.P5, p.P25, p.P50, p.P75, p.P95)
+		fmt.Printf("Avg Casts:\n")
+		for _, id := range castIDs(r) {
+			cm := r.Casts[id]
+			fmt.Printf("\t%-14s %6.1f  (%0.0f avg dmg, %0.1f%% crit)\n", cm.Name+":", float64(cm.Count)/n, cm.Dmg/float64(cm.Count), float64(cm.Crits)/float64(cm.Count)*100)
+		}
+		if r.Consumed != (tbc.ConsumeMetric{}) {
+			fmt.Printf("Avg Consumes Used:\n")
+			fmt.Printf("\tDestruction Potion: %0.2f\n", r.Consumed.DestructionPotion)
+			fmt.Printf("\tSuper Mana Potion: %0.2f\n", r.Consumed.SuperManaPotion)
+			fmt.Printf("\tDark Rune: %0.2f\n", r.Consumed.DarkRune)
+		}
+		fmt.Printf("Went OOM: %d/%d sims\n", r.NumOOM, r.Iterations)
+		if r.NumOOM > 0 {
+			fmt.Printf("Avg OOM Time: %0.0f seconds\n", r.OOMAt)
+			fmt.Printf("Avg DPS At OOM: %0.0f\n", r.DPSAtOOM)
+		}
+	}
+}
+
+// castIDs are the spells cast in a result, in ID order.
+func castIDs(r tbc.SimResult) []int32 {
+	ids := make([]int32, 0, len(r.Casts))
+	for id, cm := range r.Casts {
+		if cm.Count > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func printComparison(res compareOutput) {
+	low, high := res.Conf90()
+	fmt.Printf("\n%s: %0.1f +/- %0.1f DPS\n", res.Base, res.BaseDPS, res.BaseStdev)
+	fmt.Printf("%s: %0.1f +/- %0.1f DPS\n", res.Other, res.DPS, res.Stdev)
+	fmt.Printf("Difference: %+0.1f DPS [%+0.1f, %+0.1f] (90%% confidence, %d paired iterations)\n", res.Delta, low, high, res.Iterations)
+	if len(res.Gear) > 0 {
+		fmt.Printf("Gear:\n")
+		for _, g := range res.Gear {
+			fmt.Printf("\t%-10s %s -> %s\n", g.Slot+":", orNone(g.Base), orNone(g.Other))
+		}
+	}
+	if len(res.Stats) > 0 {
+		names := make([]string, 0, len(res.Stats))
+		for name := range res.Stats {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Printf("Stats:\n")
+		for _, name := range names {
+			fmt.Printf("\t%-14s %+0.1f\n", name+":", res.Stats[name])
+		}
+	}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(empty)"
+	}
+	return s
+}
+
+func printGemResult(res tbc.GemOptimizerResult) {
+	fmt.Printf("\nOptimal Gems: %0.1f DPS (%+0.1f DPS vs current gems)\n", res.DPS, res.Delta)
+	for _, item := range res.Equip {
+		if len(item.Gems) == 0 {
+			continue
+		}
+		names := make([]string, len(item.Gems))
+		for i, g := range item.Gems {
+			names[i] = g.Name
+		}
+		fmt.Printf("\t%s: %s\n", item.Name, strings.Join(names, ", "))
+	}
+}
+
+func printGearResult(res tbc.GearOptimizerResult) {
+	fmt.Printf("\nOptimal Gear: %0.1f +/- %0.1f DPS (%+0.1f DPS vs current gear)\n", res.DPS, res.Stdev, res.Delta)
+	if len(res.Candidates) > 0 && len(res.Candidates[0].Sets) > 0 {
+		fmt.Printf("Set Bonuses: %s\n", strings.Join(res.Candidates[0].Sets, ", "))
+	}
+	for _, item := range res.Equip {
+		if item.Name == "" {
+			continue
+		}
+		names := make([]string, 0, len(item.Gems))
+		for _, g := range item.Gems {
+			if g.Name != "" {
+				names = append(names, g.Name)
+			}
+		}
+		if len(names) > 0 {
+			fmt.Printf("\t%s (%s)\n", item.Name, strings.Join(names, ", "))
+		} else {
+			fmt.Printf("\t%s\n", item.Name)
+		}
+	}
+	if len(res.Candidates) > 1 {
+		fmt.Printf("Runners Up:\n")
+		for i, c := range res.Candidates[1:] {
+			fmt.Printf("\t%d. %0.1f +/- %0.1f DPS %v\n", i+2, c.DPS, c.Stdev, c.Sets)
+		}
+	}
+}
+
+func printStatWeights(res tbc.StatWeightsResult) {
+	fmt.Printf("Stat Weights (base %0.1f DPS, +/- is one standard error):\n", res.BaseDPS)
+	for _, w := range res.Weights {
+		fmt.Printf("\t%-14s %0.2f +/- %0.2f\t(%0.2f DPS per point)\n", w.Stat.StatName()+":", w.Weight, w.WeightErr, w.DPS)
+	}
+}
+
+// parseSweep parses a stat:from:to:step sweep flag.
+func parseSweep(val string) tbc.StatSweep {
+	parts := strings.Split(val, ":")
+	if len(parts) < 3 || len(parts) > 4 {
+		log.Fatalf("Invalid sweep %s, expected stat:from:to:step", val)
+	}
+	stat, ok := tbc.ParseStat(parts[0])
+	if !ok {
+		log.Fatalf("Unknown stat to sweep: %s", parts[0])
+	}
+	nums := make([]float64, 3)
+	for i, p := range parts[1:] {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			log.Fatalf("Invalid number in sweep %s: %s", val, p)
+		}
+		nums[i] = v
+	}
+	return tbc.StatSweep{Stat: stat, From: nums[0], To: nums[1], Step: nums[2]}
+}
+
+// printScaling prints the points of a sweep with notes about the hit cap and where the stat stops being worth anything.
+func printScaling(res tbc.ScalingResult) {
+	writeText(os.Stdout, scalingTables(res))
+	printScalingNotes(os.Stdout, res)
+}
+
+func printScalingNotes(w io.Writer, res tbc.ScalingResult) {
+	if res.HitCapAt != nil {
+		fmt.Fprintf(w, "Hit cap reached at %+0.1f hit rating\n", *res.HitCapAt)
+	}
+	if res.OverloadHitCapAt != nil {
+		fmt.Fprintf(w, "Lightning Overload hit cap reached at %+0.1f hit rating\n", *res.OverloadHitCapAt)
+	}
+	for _, p := range res.Collapses() {
+		fmt.Fprintf(w, "Marginal value collapsed at %s +%0.1f (%s +%0.1f)\n", res.XStat.StatName(), p.X, res.YStat.StatName(), p.Y)
+	}
+}
+
+// parseItemFilter builds the item filter shared by the gear optimizers and the items command from the flags.
+func parseItemFilter(phase byte, armor string, professions string) tbc.ItemFilter {
+	filter := tbc.ItemFilter{MaxPhase: phase}
+	if armor != "" {
+		for _, name := range strings.Split(armor, ",") {
+			a, ok := tbc.ParseArmorType(strings.TrimSpace(name))
+			if !ok {
+				log.Fatalf("Unknown armor type in --armor: %s", name)
+			}
+			filter.ArmorTypes = append(filter.ArmorTypes, a)
+		}
+	}
+	if professions == "none" {
+		filter.Professions = []tbc.Profession{tbc.ProfessionNone}
+	} else if professions != "" {
+		for _, name := range strings.Split(professions, ",") {
+			p, ok := tbc.ParseProfession(strings.TrimSpace(name))
+			if !ok {
+				log.Fatalf("Unknown profession in --professions: %s", name)
+			}
+			filter.Professions = append(filter.Professions, p)
+		}
+	}
+	return filter
+}
+
+func printItems(items []tbc.Item) {
+	for _, item := range items {
+		extra := []string{}
+		for _, v := range []fmt.Stringer{item.ArmorType, item.Binding, item.Profession} {
+			if s := v.String(); s != "" {
+				extra = append(extra, s)
+			}
+		}
+		fmt.Printf("%-8s %6d  ilvl %3d  P%d  %-45s %-20s %s\n", tbc.SlotName(item.Slot), item.ID, item.ItemLevel, item.Phase, item.Name, strings.Join(extra, " "), item.SourceZone)
+	}
+}
+
+func printUpgrades(res tbc.UpgradeResult) {
+	fmt.Printf("\nUpgrades (current gear %0.1f DPS, 90%% confidence in brackets):\n", res.BaseDPS)
+	for _, slot := range res.Slots {
+		current := []string{}
+		for _, item := range slot.Current {
+			if item.Name != "" {
+				current = append(current, item.Name)
+			}
+		}
+		fmt.Printf("%s (%s):\n", tbc.SlotName(slot.Slot), strings.Join(current, ", "))
+		for _, up := range slot.Upgrades {
+			low, high := up.Conf90()
+			fmt.Printf("\t%+7.1f DPS [%+0.1f, %+0.1f]\t%s - %s %s (Phase %d)", up.Delta, low, high, up.Item.Name, up.Item.SourceZone, up.Item.SourceDrop, up.Item.Phase)
+			if len(up.SetsGained) > 0 {
+				fmt.Printf(" gains %s", strings.Join(up.SetsGained, ", "))
+			}
+			if len(up.SetsLost) > 0 {
+				fmt.Printf(" loses %s", strings.Join(up.SetsLost, ", "))
+			}
+			fmt.Printf("\n")
+		}
+	}
+}
+
+func printBuffValues(res tbc.BuffValueResult) {
+	fmt.Printf("\nBuff Values (current options %0.1f DPS, 90%% confidence in brackets, * is currently on):\n", res.BaseDPS)
+	for _, bv := range res.Values {
+		low, high := bv.Conf90()
+		on := " "
+		if bv.Enabled {
+			on = "*"
+		}
+		fmt.Printf("\t%+7.1f DPS [%+0.1f, %+0.1f]\t%s %s (%s)\n", bv.Delta, low, high, on, bv.Name, bv.Category)
+	}
+}
+
+func printCooldownPolicies(results []tbc.CooldownPolicyResult) {
+	fmt.Printf("\nCooldown Policies:\n")
+	for i, res := range results {
+		if i == 0 || results[i-1].Seconds != res.Seconds {
+			fmt.Printf("%d sec:\n", res.Seconds)
+		}
+		fmt.Printf("\t%-10s %0.1f +/- %0.1f\n", res.Policy, res.DPS, res.Stdev)
+	}
+}
@@ -0,0 +1,587 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/lologarithm/wowsim/cluster"
+	"github.com/lologarithm/wowsim/importer"
+	"github.com/lologarithm/wowsim/tbc"
+)
+
+// commonFlags are the flags every command has: where the profile comes from, how to run the sim
+// and how to print the result.
+type commonFlags struct {
+	config     string
+	importFile string
+	fromLink   string
+	dataFiles  string
+	cacheDir   string
+	workers    string
+	cdPolicy   string
+	lustAt     int
+	prePot     bool
+
+	duration   int
+	iterations int
+	stdErr     float64
+	maxIter    int
+	debug      bool
+	progress   bool
+	job        *tbc.Job // shared by every profile of the command, see simJob.
+
+	format string
+
+	printResolved bool
+	resolved      []resolvedProfile
+}
+
+func (c *commonFlags) register(fs *flag.FlagSet, format string) {
+	fs.StringVar(&c.config, "config", "", "Specify an input configuration.")
+	fs.StringVar(&c.importFile, "import", "", "Use the gear from a Seventy Upgrades export, SimulationCraft addon profile or in-game item links in this file.\n\tOptions still come from --config.")
+	fs.StringVar(&c.fromLink, "from-link", "", "Use the gear and options of a share link from the web UI, the whole URL or the text after the #.")
+	fs.StringVar(&c.dataFiles, "data", "", "Comma separated data files with extra items, gems, enchants and sets to load on top of the built in data.")
+	fs.StringVar(&c.cacheDir, "cache-dir", "", "Also keep sim results in this directory, so later runs with the same setup and seed reuse them.")
+	fs.StringVar(&c.workers, "workers", "", "Comma separated host:port of worker processes (see wowsim worker) to spread the sims of optimizers,\n\tstat weights and comparisons over. Workers must load the same --data files.")
+	fs.StringVar(&c.cdPolicy, "cdpolicy", "", "Cooldown policy to use: asap, bloodlust, execute, stagger or pull.")
+	fs.IntVar(&c.lustAt, "lustat", -1, "Seconds into the fight to use the first bloodlust.")
+	fs.BoolVar(&c.prePot, "prepot", false, "Drink a destruction potion before the pull (requires destruction potion in the config).")
+	fs.IntVar(&c.duration, "duration", 300, "Custom fight duration in seconds.")
+	fs.IntVar(&c.iterations, "iter", 10000, "Custom number of iterations for the sim to run.")
+	fs.Float64Var(&c.stdErr, "stderr", 0, "Run until the standard error of mean DPS (or of a DPS difference when comparing) is at most this, instead of a fixed --iter.\n\t--iter becomes the first batch of iterations.")
+	fs.IntVar(&c.maxIter, "maxiter", 100000, "Most iterations to run for a single setup with --stderr.")
+	fs.BoolVar(&c.debug, "debug", false, "Include --debug to spew the entire simulation log.")
+	fs.BoolVar(&c.progress, "progress", false, "Print the progress of the sim to stderr while it runs.")
+	fs.BoolVar(&c.printResolved, "print-resolved", false, "Print the profiles after resolving the configs they extend and the flags, in the config format, instead of simming.")
+	fs.StringVar(&c.format, "format", format, "Output format: text, json, csv or markdown.")
+}
+
+// hasProfile is true if a flag gives the profile to use instead of the default.
+func (c *commonFlags) hasProfile() bool {
+	return c.config != "" || c.importFile != "" || c.fromLink != ""
+}
+
+// loadData loads the --data files on top of the built in data, sets up the --cache-dir
+// and connects to the --workers.
+func (c *commonFlags) loadData() {
+	if err := tbc.SetCacheDir(c.cacheDir); err != nil {
+		log.Fatalf("Failed to use the cache directory: %s", err)
+	}
+	if c.dataFiles != "" {
+		for _, file := range strings.Split(c.dataFiles, ",") {
+			if err := tbc.LoadDataFile(strings.TrimSpace(file)); err != nil {
+				log.Fatalf("Failed to load data file: %s", err)
+			}
+		}
+	}
+	connectWorkers(c.workers)
+}
+
+// connectWorkers sends sims to the comma separated worker addresses, once the item data is loaded.
+func connectWorkers(addrs string) {
+	if addrs == "" {
+		return
+	}
+	list := strings.Split(addrs, ",")
+	for i := range list {
+		list[i] = strings.TrimSpace(list[i])
+	}
+	coord, err := cluster.Connect(list)
+	if err != nil {
+		log.Fatalf("Failed to connect to the workers: %s", err)
+	}
+	tbc.SetRemoteRunner(coord)
+}
+
+// profile loads the gear and options from --config and --import or --from-link, or the default profile,
+// and applies the flags that override the options. Invalid gear exits, as does --print-resolved
+// once the profile is printed.
+func (c *commonFlags) profile() (tbc.Equipment, tbc.Options) {
+	gear, opt := c.loadProfile()
+	c.resolvedDone()
+	return gear, opt
+}
+
+// loadProfile is profile for commands that load more profiles after it.
+func (c *commonFlags) loadProfile() (tbc.Equipment, tbc.Options) {
+	if c.fromLink != "" && (c.config != "" || c.importFile != "") {
+		log.Fatalf("--from-link has both gear and options, it can't be used with --config or --import")
+	}
+	gear, opt := defaultGear(), defaultOptions()
+	source, problems := "default gear", gear.Validate()
+	if c.config != "" {
+		gear, opt, problems = loadConfig(c.config)
+		source = c.config
+	}
+	if c.importFile != "" {
+		data, err := ioutil.ReadFile(c.importFile)
+		if err != nil {
+			log.Fatalf("Failed to open import file(%s): %s", c.importFile, err)
+		}
+		res, err := importer.Import(data)
+		if err != nil {
+			log.Fatalf("Failed to import gear: %s", err)
+		}
+		for _, m := range res.Missing {
+			fmt.Fprintf(os.Stderr, "WARNING: not imported: %s\n", m)
+		}
+		gear = res.Gear
+		source, problems = c.importFile, res.Problems
+	}
+	if c.fromLink != "" {
+		s, linkProblems, err := tbc.ParseLink(c.fromLink)
+		if err != nil {
+			log.Fatalf("Failed to read link: %s", err)
+		}
+		gear, opt = s.Equip, s.Options
+		source, problems = "link", append(linkProblems, gear.Validate()...)
+	}
+	checkGear(source, problems)
+	opt = c.apply(opt)
+	c.loaded(profileName(c), gear, opt)
+	return gear, opt
+}
+
+// apply overrides the options with the cooldown, precision and debug flags.
+func (c *commonFlags) apply(opt tbc.Options) tbc.Options {
+	if c.cdPolicy != "" {
+		policy, ok := tbc.ParseCooldownPolicy(c.cdPolicy)
+		if !ok {
+			log.Fatalf("Unknown cooldown policy: %s", c.cdPolicy)
+		}
+		opt.Cooldowns.Policy = policy
+	}
+	if c.prePot {
+		opt.Consumes.Policies.DestructionPotion.PrePot = true
+	}
+	if c.lustAt >= 0 {
+		opt.Cooldowns.BloodlustAt = c.lustAt
+	}
+	if c.stdErr > 0 {
+		opt.Precision = tbc.Precision{StdErr: c.stdErr, MaxIters: c.maxIter}
+	}
+	if c.debug {
+		c.iterations = 1
+		opt.Debug = true
+	}
+	opt.Job = c.simJob()
+	return opt
+}
+
+// simJob is the job every sim of the command runs in. Ctrl-C cancels it, stopping the sims,
+// and with --progress it prints how far along it is.
+func (c *commonFlags) simJob() *tbc.Job {
+	if c.job != nil {
+		return c.job
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	go func() {
+		<-ctx.Done()
+		stop() // a second Ctrl-C exits right away.
+	}()
+	var onProgress func(tbc.Progress)
+	if c.progress {
+		onProgress = func(p tbc.Progress) {
+			if p.Total > 0 {
+				fmt.Fprintf(os.Stderr, "\r%s: %d/%d iterations (%0.0f%%)   ", p.Phase, p.Iterations, p.Total, 100*float64(p.Iterations)/float64(p.Total))
+			} else {
+				fmt.Fprintf(os.Stderr, "\r%s: %d iterations   ", p.Phase, p.Iterations)
+			}
+		}
+	}
+	c.job = tbc.NewJob(ctx, onProgress)
+	return c.job
+}
+
+// resolvedProfile is a profile printed by --print-resolved, in the config format.
+type resolvedProfile struct {
+	Name    string `json:",omitempty"` // only when printing more than one profile.
+	Options tbc.Options
+	Gear    []configItem
+}
+
+// configItem is an item in the config format, with its gems and enchant by name.
+type configItem struct {
+	Name    string
+	Gems    []configRef `json:",omitempty"` // in socket order, empty sockets are {}.
+	Enchant *configRef  `json:",omitempty"`
+}
+
+type configRef struct {
+	Name string `json:",omitempty"`
+}
+
+// loaded keeps a loaded profile to print with --print-resolved.
+func (c *commonFlags) loaded(name string, gear tbc.Equipment, opt tbc.Options) {
+	if !c.printResolved {
+		return
+	}
+	rp := resolvedProfile{Name: name, Options: opt, Gear: []configItem{}}
+	for _, item := range gear {
+		if item.Name == "" {
+			continue
+		}
+		ci := configItem{Name: item.Name}
+		for _, g := range item.Gems {
+			ci.Gems = append(ci.Gems, configRef{Name: g.Name})
+		}
+		if item.Enchant.Name != "" {
+			ci.Enchant = &configRef{Name: item.Enchant.Name}
+		}
+		rp.Gear = append(rp.Gear, ci)
+	}
+	c.resolved = append(c.resolved, rp)
+}
+
+// resolvedDone prints the loaded profiles and exits if --print-resolved is set.
+// A single profile is printed as a config file, more than one as a list.
+func (c *commonFlags) resolvedDone() {
+	if !c.printResolved {
+		return
+	}
+	var res interface{} = c.resolved
+	if len(c.resolved) == 1 {
+		c.resolved[0].Name = ""
+		res = c.resolved[0]
+	}
+	out, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to format JSON output: %s", err)
+	}
+	fmt.Println(string(out))
+	os.Exit(0)
+}
+
+// defaultGear is some default gear, used if no config or import is given.
+func defaultGear() tbc.Equipment {
+	gear := tbc.NewEquipmentSet(
+		"Tidefury Helm",
+		"Charlotte's Ivy",
+		"Pauldrons of Wild Magic",
+		"Ogre Slayer's Cover",
+		"Tidefury Chestpiece",
+		"World's End Bracers",
+		"Earth Mantle Handwraps",
+		"Netherstrike Belt",
+		"Stormsong Kilt",
+		"Magma Plume Boots",
+		"Cobalt Band of Tyrigosa",
+		"Sparking Arcanite Ring",
+		"Mazthoril Honor Shield",
+		"Gavel of Unearthed Secrets",
+		"Natural Alignment Crystal",
+		"Icon of the Silver Crescent",
+		"Totem of the Void",
+	)
+
+	// Auto gem the default gear above. Purple gems in the blue sockets count as blue for the CSD.
+	ruby := tbc.GemLookup["Runed Living Ruby"]
+	nightseye := tbc.GemLookup["Glowing Nightseye"]
+	for i := range gear {
+		gear[i].Gems = make([]tbc.Gem, len(gear[i].GemSlots))
+		for gs, color := range gear[i].GemSlots {
+			switch color {
+			case tbc.GemColorMeta:
+				gear[i].Gems[gs] = tbc.Gems[0] // CSD
+			case tbc.GemColorBlue:
+				gear[i].Gems[gs] = nightseye
+			default:
+				gear[i].Gems[gs] = ruby
+			}
+		}
+	}
+	return gear
+}
+
+// defaultOptions are the options used if no config is given.
+func defaultOptions() tbc.Options {
+	return tbc.Options{
+		NumBloodlust: 0,
+		NumDrums:     0,
+		Buffs: tbc.Buffs{
+			ArcaneInt:                false,
+			GiftOftheWild:            false,
+			BlessingOfKings:          false,
+			ImprovedBlessingOfWisdom: false,
+			JudgementOfWisdom:        false,
+			Moonkin:                  false,
+			SpriestDPS:               0,
+			WaterShield:              true,
+			// Race:                     tbc.RaceBonusOrc,
+			Custom: tbc.Stats{
+				tbc.StatInt:       290,
+				tbc.StatSpellDmg:  598 + 55,
+				tbc.StatSpellHit:  24,
+				tbc.StatSpellCrit: 120,
+			},
+		},
+		Consumes: tbc.Consumes{
+			// FlaskOfBlindingLight: true,
+			// BrilliantWizardOil:   false,
+			// MajorMageblood:       false,
+			// BlackendBasilisk:     true,
+			SuperManaPotion: false,
+			// DarkRune:             false,
+		},
+		Talents: tbc.Talents{
+			LightninOverload:   5,
+			ElementalPrecision: 3,
+			NaturesGuidance:    3,
+			TidalMastery:       5,
+			ElementalMastery:   true,
+			UnrelentingStorm:   3,
+			CallOfThunder:      5,
+			Concussion:         5,
+			Convection:         5,
+		},
+		Totems: tbc.Totems{
+			TotemOfWrath: 1,
+			WrathOfAir:   false,
+			ManaStream:   true,
+		},
+	}
+}
+
+// input is the config file format.
+type input struct {
+	// Extends is a base config file, relative to this one. Options then only change the options given,
+	// Gear swaps items into the base gear and Remove takes items off, like a batch profile.
+	Extends string   `json:",omitempty"`
+	Remove  []string `json:",omitempty"`
+
+	Options json.RawMessage
+	Gear    []swapItem
+}
+
+// loadConfig reads the gear and options from a config file, and the base configs it extends.
+// Gear problems are returned rather than dropped, anything that can't be found is left out of the gear.
+func loadConfig(file string) (tbc.Equipment, tbc.Options, tbc.GearProblems) {
+	return loadConfigFrom(file, nil)
+}
+
+// loadConfigFrom loads a config file extended by the files in chain.
+func loadConfigFrom(file string, chain []string) (tbc.Equipment, tbc.Options, tbc.GearProblems) {
+	for _, f := range chain {
+		if f == file {
+			log.Fatalf("Config %s extends itself: %s -> %s", file, strings.Join(chain, " -> "), file)
+		}
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		log.Fatalf("Failed to open config file(%s): %s", file, err)
+	}
+	in := &input{}
+	if err := json.Unmarshal(data, in); err != nil {
+		log.Fatalf("Failed to unmarshal JSON in %s: %s", file, err)
+	}
+
+	if in.Extends == "" {
+		opt := tbc.Options{}
+		if len(in.Options) > 0 {
+			if err := json.Unmarshal(in.Options, &opt); err != nil {
+				log.Fatalf("Invalid options in %s: %s", file, err)
+			}
+		}
+		items := make([]tbc.Item, len(in.Gear))
+		for i, g := range in.Gear {
+			items[i] = g.Item
+		}
+		items, problems := resolveItems(items)
+		gear, placement := tbc.NewEquipment(items...)
+		problems = append(problems, placement...)
+		for _, g := range in.Gear {
+			if g.Replace != "" {
+				problems = append(problems, tbc.GearProblem{Item: g.Name, Message: "can't replace an item without a base config to extend"})
+			}
+		}
+		for _, name := range in.Remove {
+			problems = append(problems, tbc.GearProblem{Item: name, Message: "can't be removed without a base config to extend"})
+		}
+		return gear, opt, append(problems, gear.Validate()...)
+	}
+
+	gear, opt, problems := loadConfigFrom(relativeTo(filepath.Dir(file), in.Extends), append(chain, file))
+	if errs := problems.Errors(); len(errs) > 0 {
+		return gear, opt, errs
+	}
+	if len(in.Options) > 0 {
+		if opt, err = overlayOptions(opt, in.Options); err != nil {
+			log.Fatalf("Invalid options in %s: %s", file, err)
+		}
+	}
+	gear, problems = swapGear(gear, in.Gear, in.Remove)
+	return gear, opt, problems
+}
+
+// overlayOptions changes only the options given in data, in the config format.
+func overlayOptions(opt tbc.Options, data json.RawMessage) (tbc.Options, error) {
+	// Round trip through JSON so the options don't share slices with the base.
+	out := tbc.Options{}
+	base, _ := json.Marshal(opt)
+	json.Unmarshal(base, &out)
+	err := json.Unmarshal(data, &out)
+	return out, err
+}
+
+// swapItem is an item of the Gear of a config that extends another, or of a batch profile.
+type swapItem struct {
+	tbc.Item
+	// Replace is the name of the worn item to take off for this one, to pick which ring or trinket it replaces.
+	// Without it a ring or trinket replaces the second one.
+	Replace string `json:",omitempty"`
+}
+
+// swapGear wears the items of gear, in the config format, in place of the items they replace or the
+// items in the same slots, and takes off the items named in remove.
+func swapGear(equip tbc.Equipment, gear []swapItem, remove []string) (tbc.Equipment, tbc.GearProblems) {
+	swapped, problems := tbc.NewEquipment()
+	replaced := map[int]bool{}
+	others := []tbc.Item{}
+	for _, g := range gear {
+		items, missing := resolveItems([]tbc.Item{g.Item})
+		problems = append(problems, missing...)
+		if len(items) == 0 {
+			continue
+		}
+		item := items[0]
+		if g.Replace == "" {
+			others = append(others, item)
+			continue
+		}
+		slot := -1
+		for i, worn := range equip {
+			if worn.Name == g.Replace && !replaced[i] {
+				slot = i
+				break
+			}
+		}
+		switch {
+		case slot < 0:
+			problems = append(problems, tbc.GearProblem{Slot: item.Slot, Item: item.Name, Message: fmt.Sprintf("can't replace %s, it isn't worn", g.Replace)})
+		case !item.Fits(byte(slot)):
+			problems = append(problems, tbc.GearProblem{Slot: item.Slot, Item: item.Name, Message: fmt.Sprintf("can't replace %s, it's in the %s slot", g.Replace, tbc.SlotName(byte(slot)))})
+		default:
+			swapped[slot] = item
+			replaced[slot] = true
+		}
+	}
+	for _, item := range others {
+		if !swapped.Place(item) {
+			problems = append(problems, tbc.GearProblem{Slot: item.Slot, Item: item.Name, Message: "no free slot for this item"})
+		}
+	}
+
+	removed := map[string]bool{}
+	for _, name := range remove {
+		removed[name] = false
+	}
+	twoHand := swapped[tbc.EquipWeapon].SubSlot == tbc.SubslotTwoHand
+	for i, item := range equip {
+		if item.Name == "" || replaced[i] {
+			continue
+		}
+		if _, ok := removed[item.Name]; ok {
+			removed[item.Name] = true
+			continue
+		}
+		if twoHand && byte(i) == tbc.EquipOffhand {
+			continue
+		}
+		swapped.Place(item) // items in slots that were swapped have nowhere to go.
+	}
+	for _, name := range remove {
+		if !removed[name] {
+			problems = append(problems, tbc.GearProblem{Item: name, Message: "can't be removed, it isn't worn"})
+		}
+	}
+	return swapped, append(problems, swapped.Validate()...)
+}
+
+// relativeTo resolves a file named in a batch file relative to the batch file.
+func relativeTo(dir string, file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(dir, file)
+}
+
+// resolveItems looks up the items of a config, with their gems and enchants, in the item data.
+// Anything that can't be found is reported and left out.
+func resolveItems(gear []tbc.Item) ([]tbc.Item, tbc.GearProblems) {
+	problems := tbc.GearProblems{}
+	items := make([]tbc.Item, 0, len(gear))
+	for _, v := range gear {
+		if v.Name == "" && v.ID == 0 {
+			continue
+		}
+		ic, ok := tbc.ItemsByName[v.Name]
+		if v.Name == "" {
+			ic, ok = tbc.ItemsByID[v.ID]
+		}
+		if !ok {
+			problems = append(problems, tbc.GearProblem{Item: describe(v.Name, v.ID), Message: "not in the item data"})
+			continue
+		}
+		ic.Gems = make([]tbc.Gem, len(ic.GemSlots))
+		for i, gem := range v.Gems {
+			if gem.Name == "" && gem.ID == 0 {
+				continue // empty socket
+			}
+			gv, ok := tbc.GemLookup[gem.Name]
+			if gem.Name == "" {
+				gv, ok = tbc.GemsByID[gem.ID]
+			}
+			if !ok {
+				problems = append(problems, tbc.GearProblem{Slot: ic.Slot, Item: ic.Name, Message: fmt.Sprintf("gem %s is not in the gem data", describe(gem.Name, gem.ID))})
+				continue
+			}
+			if i >= len(ic.Gems) {
+				ic.Gems = append(ic.Gems, make([]tbc.Gem, i+1-len(ic.Gems))...) // left for Validate to report.
+			}
+			ic.Gems[i] = gv
+		}
+		if v.Enchant.Name != "" || v.Enchant.ID != 0 {
+			en, ok := tbc.EnchantLookup[v.Enchant.Name]
+			if v.Enchant.Name == "" {
+				en, ok = tbc.EnchantByID[v.Enchant.ID]
+			}
+			if !ok {
+				problems = append(problems, tbc.GearProblem{Slot: ic.Slot, Item: ic.Name, Message: fmt.Sprintf("enchant %s is not in the enchant data", describe(v.Enchant.Name, v.Enchant.ID))})
+			} else {
+				ic.Enchant = en
+			}
+		}
+		items = append(items, ic)
+	}
+	return items, problems
+}
+
+// describe names an item, gem or enchant from a config by its name, or its ID if it has no name.
+func describe(name string, id int32) string {
+	if name != "" {
+		return fmt.Sprintf("%q", name)
+	}
+	return fmt.Sprintf("ID %d", id)
+}
+
+// checkGear prints the problems found loading gear and exits if any of them aren't just warnings.
+func checkGear(source string, problems tbc.GearProblems) {
+	for _, p := range problems {
+		if p.Warning {
+			fmt.Fprintf(os.Stderr, "WARNING: %s\n", p)
+		} else {
+			fmt.Fprintf(os.Stderr, "ERROR: %s\n", p)
+		}
+	}
+	if errs := problems.Errors(); len(errs) > 0 {
+		log.Fatalf("Invalid gear in %s: %d problems", source, len(errs))
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/lologarithm/wowsim/tbc"
+)
+
+func TestSwapGear(t *testing.T) {
+	swap := func(name string, replace string) swapItem {
+		return swapItem{Item: tbc.Item{Name: name}, Replace: replace}
+	}
+	cases := []struct {
+		name  string
+		gear  []swapItem
+		slot  byte
+		want  string
+		other string // what is left in the other ring or trinket slot
+	}{
+		{"second ring by default", []swapItem{swap("Seer's Signet", "")}, tbc.EquipFinger1, "Seer's Signet", "Cobalt Band of Tyrigosa"},
+		{"first ring by name", []swapItem{swap("Seer's Signet", "Cobalt Band of Tyrigosa")}, tbc.EquipFinger1, "Seer's Signet", "Sparking Arcanite Ring"},
+		{"second ring by name", []swapItem{swap("Seer's Signet", "Sparking Arcanite Ring")}, tbc.EquipFinger2, "Seer's Signet", "Cobalt Band of Tyrigosa"},
+		{"first trinket by name", []swapItem{swap("Quagmirran's Eye", "Natural Alignment Crystal")}, tbc.EquipTrinket1, "Quagmirran's Eye", "Icon of the Silver Crescent"},
+		{"second trinket by name", []swapItem{swap("Quagmirran's Eye", "Icon of the Silver Crescent")}, tbc.EquipTrinket2, "Quagmirran's Eye", "Natural Alignment Crystal"},
+	}
+	for _, c := range cases {
+		equip, problems := swapGear(defaultGear(), c.gear, nil)
+		if errs := problems.Errors(); len(errs) > 0 {
+			t.Fatalf("%s: unexpected problems: %v", c.name, errs)
+		}
+		if got := equip[c.slot].Name; got != c.want {
+			t.Errorf("%s: expected %s in %s, got %s", c.name, c.want, tbc.SlotName(c.slot), got)
+		}
+		worn := map[string]bool{}
+		for _, item := range equip {
+			worn[item.Name] = true
+		}
+		if !worn[c.other] {
+			t.Errorf("%s: expected %s to still be worn", c.name, c.other)
+		}
+	}
+
+	_, problems := swapGear(defaultGear(), []swapItem{swap("Seer's Signet", "Natural Alignment Crystal")}, nil)
+	if len(problems.Errors()) == 0 {
+		t.Errorf("expected a ring replacing a trinket to be a problem")
+	}
+	_, problems = swapGear(defaultGear(), []swapItem{swap("Seer's Signet", "Band of the Guardian")}, nil)
+	if len(problems.Errors()) == 0 {
+		t.Errorf("expected replacing an item that isn't worn to be a problem")
+	}
+}
+
+func TestDefaultGear(t *testing.T) {
+	if problems := defaultGear().Validate(); len(problems) > 0 {
+		t.Fatalf("expected the default gear to have no problems or warnings, got %v", problems)
+	}
+}
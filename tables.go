@@ -0,0 +1,243 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/lologarithm/wowsim/tbc"
+)
+
+// table is part of a result laid out in rows, for the csv and markdown formats.
+// Columns are stable so spreadsheets can import them, new columns only go on the end.
+type table struct {
+	title  string
+	header []string
+	rows   [][]string
+}
+
+func (t *table) add(cells ...interface{}) {
+	row := make([]string, len(cells))
+	for i, c := range cells {
+		row[i] = cell(c)
+	}
+	t.rows = append(t.rows, row)
+}
+
+// cell formats a single value: floats to 2 decimal places, plain numbers without.
+func cell(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case []string:
+		return strings.Join(v, "; ")
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(v)
+}
+
+// writeCSV writes each table with its header row, with a blank line between tables.
+func writeCSV(w io.Writer, tables []table) {
+	cw := csv.NewWriter(w)
+	for i, t := range tables {
+		if i > 0 {
+			cw.Write(nil)
+		}
+		cw.Write(t.header)
+		cw.WriteAll(t.rows)
+	}
+	cw.Flush()
+}
+
+func writeMarkdown(w io.Writer, tables []table) {
+	escape := strings.NewReplacer("|", "\\|", "\n", " ")
+	line := func(cells []string) {
+		out := make([]string, len(cells))
+		for i, c := range cells {
+			out[i] = escape.Replace(c)
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(out, " | "))
+	}
+	for i, t := range tables {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "### %s\n\n", t.title)
+		line(t.header)
+		sep := make([]string, len(t.header))
+		for i := range sep {
+			sep[i] = "---"
+		}
+		line(sep)
+		for _, row := range t.rows {
+			line(row)
+		}
+	}
+}
+
+// writeText writes tables as aligned columns, for the text output of results that are just tables.
+func writeText(w io.Writer, tables []table) {
+	for i, t := range tables {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		tw.Flush()
+	}
+}
+
+func simTables(res simOutput) []table {
+	summary := table{title: "DPS", header: []string{
+		"Rotation", "Iterations", "DPS", "Stdev", "Min", "P5", "P25", "P50", "P75", "P95", "Max",
+		"OOM", "OOMAt", "DPSAtOOM", "DestructionPotion", "SuperManaPotion", "DarkRune",
+	}}
+	casts := table{title: "Casts", header: []string{"Rotation", "Spell", "ID", "Casts", "Damage", "Crits", "AvgCasts", "AvgDamage", "CritRate"}}
+	hist := table{title: "Histogram", header: []string{"Rotation", "DPS", "Iterations"}}
+	for _, r := range res.Results {
+		rot := strings.Join(r.Rotation, ",")
+		p := r.Percentiles
+		summary.add(rot, r.Iterations, r.DPSAvg, r.DPSDev, r.MinDPS, p.P5, p.P25, p.P50, p.P75, p.P95, r.MaxDPS,
+			r.NumOOM, r.OOMAt, r.DPSAtOOM, r.Consumed.DestructionPotion, r.Consumed.SuperManaPotion, r.Consumed.DarkRune)
+
+		n := float64(r.Iterations)
+		for _, id := range castIDs(r) {
+			cm := r.Casts[id]
+			casts.add(rot, cm.Name, id, cm.Count, cm.Dmg, cm.Crits, float64(cm.Count)/n, cm.Dmg/float64(cm.Count), float64(cm.Crits)/float64(cm.Count))
+		}
+
+		buckets := make([]int, 0, len(r.DPSHist))
+		for dps := range r.DPSHist {
+			buckets = append(buckets, dps)
+		}
+		sort.Ints(buckets)
+		for _, dps := range buckets {
+			hist.add(rot, dps, r.DPSHist[dps])
+		}
+	}
+	return []table{summary, casts, hist}
+}
+
+func weightTables(res tbc.StatWeightsResult) []table {
+	t := table{title: fmt.Sprintf("Stat Weights (base %0.1f DPS)", res.BaseDPS), header: []string{"Stat", "Weight", "WeightErr", "DPSPerPoint", "DPSPerPointErr"}}
+	for _, w := range res.Weights {
+		t.add(w.Stat.StatName(), w.Weight, w.WeightErr, w.DPS, w.DPSErr)
+	}
+	return []table{t}
+}
+
+func gemTables(res tbc.GemOptimizerResult) []table {
+	t := table{title: fmt.Sprintf("Optimal Gems (%0.1f DPS, %+0.1f DPS)", res.DPS, res.Delta), header: []string{"Slot", "Item", "Gems"}}
+	for i, item := range res.Equip {
+		if len(item.Gems) > 0 {
+			t.add(tbc.SlotName(byte(i)), item.Name, gemNames(item))
+		}
+	}
+	return []table{t}
+}
+
+func gearTables(res tbc.GearOptimizerResult) []table {
+	gear := table{title: fmt.Sprintf("Optimal Gear (%0.1f DPS, %+0.1f DPS)", res.DPS, res.Delta), header: []string{"Slot", "Item", "ID", "Gems"}}
+	for i, item := range res.Equip {
+		if item.Name != "" {
+			gear.add(tbc.SlotName(byte(i)), item.Name, item.ID, gemNames(item))
+		}
+	}
+	candidates := table{title: "Candidates", header: []string{"Rank", "DPS", "Stdev", "Score", "Sets"}}
+	for i, c := range res.Candidates {
+		candidates.add(i+1, c.DPS, c.Stdev, c.Score, c.Sets)
+	}
+	return []table{gear, candidates}
+}
+
+func upgradeTables(res tbc.UpgradeResult) []table {
+	t := table{title: fmt.Sprintf("Upgrades (current gear %0.1f DPS)", res.BaseDPS), header: []string{
+		"Slot", "Item", "ID", "DPS", "Delta", "DeltaErr", "Low90", "High90", "Zone", "Source", "Phase", "SetsGained", "SetsLost",
+	}}
+	for _, slot := range res.Slots {
+		for _, up := range slot.Upgrades {
+			low, high := up.Conf90()
+			t.add(tbc.SlotName(slot.Slot), up.Item.Name, up.Item.ID, up.DPS, up.Delta, up.DeltaErr, low, high,
+				up.Item.SourceZone, up.Item.SourceDrop, up.Item.Phase, up.SetsGained, up.SetsLost)
+		}
+	}
+	return []table{t}
+}
+
+func compareTables(res compareOutput) []table {
+	low, high := res.Conf90()
+	dps := table{title: "DPS", header: []string{"Setup", "DPS", "Stdev", "Delta", "DeltaErr", "Low90", "High90", "Iterations"}}
+	dps.add(res.Base, res.BaseDPS, res.BaseStdev, 0.0, 0.0, 0.0, 0.0, res.Iterations)
+	dps.add(res.Other, res.DPS, res.Stdev, res.Delta, res.DeltaErr, low, high, res.Iterations)
+	gear := table{title: "Gear", header: []string{"Slot", "Base", "Other"}}
+	for _, g := range res.Gear {
+		gear.add(g.Slot, g.Base, g.Other)
+	}
+	stats := table{title: "Stats", header: []string{"Stat", "Change"}}
+	for _, name := range sortedKeys(res.Stats) {
+		stats.add(name, res.Stats[name])
+	}
+	return []table{dps, gear, stats}
+}
+
+func buffTables(res tbc.BuffValueResult) []table {
+	t := table{title: fmt.Sprintf("Buff Values (current options %0.1f DPS)", res.BaseDPS), header: []string{"Name", "Category", "Enabled", "DPS", "Delta", "DeltaErr", "Low90", "High90"}}
+	for _, bv := range res.Values {
+		low, high := bv.Conf90()
+		t.add(bv.Name, bv.Category, bv.Enabled, bv.DPS, bv.Delta, bv.DeltaErr, low, high)
+	}
+	return []table{t}
+}
+
+func cooldownTables(results []tbc.CooldownPolicyResult) []table {
+	t := table{title: "Cooldown Policies", header: []string{"Seconds", "Policy", "DPS", "Stdev", "Iterations"}}
+	for _, res := range results {
+		t.add(res.Seconds, res.Policy, res.DPS, res.Stdev, res.Iterations)
+	}
+	return []table{t}
+}
+
+func scalingTables(res tbc.ScalingResult) []table {
+	t := table{title: "Stat Scaling", header: []string{res.XStat.StatName(), res.YStat.StatName(), "DPS", "Stdev", "Marginal", "Collapsed"}}
+	for _, p := range res.Points {
+		t.add(strconv.FormatFloat(p.X, 'f', -1, 64), strconv.FormatFloat(p.Y, 'f', -1, 64), p.DPS, p.Stdev, strconv.FormatFloat(p.Marginal, 'f', 4, 64), p.Collapsed)
+	}
+	return []table{t}
+}
+
+func itemTables(items []tbc.Item) []table {
+	t := table{title: "Items", header: []string{"Slot", "ID", "Name", "ItemLevel", "Phase", "Armor", "Binding", "Profession", "Zone", "Source"}}
+	for _, item := range items {
+		t.add(tbc.SlotName(item.Slot), item.ID, item.Name, item.ItemLevel, item.Phase, item.ArmorType, item.Binding, item.Profession, item.SourceZone, item.SourceDrop)
+	}
+	return []table{t}
+}
+
+func gemNames(item tbc.Item) []string {
+	names := []string{}
+	for _, g := range item.Gems {
+		if g.Name != "" {
+			names = append(names, g.Name)
+		}
+	}
+	return names
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
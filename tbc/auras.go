@@ -719,7 +719,7 @@ func ActivateSkyshatterImpLB(sim *Simulation) Aura {
 }
 
 func ActivateDestructionPotion(sim *Simulation) Aura {
-	sim.destructionPotion = true
+	sim.metrics.Consumed.DestructionPotion++
 	sim.Buffs[StatSpellDmg] += 120
 	sim.Buffs[StatSpellCrit] += 44.16
 	sim.CDs[MagicIDPotion] = 120 * TicksPerSecond
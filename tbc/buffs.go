@@ -1,10 +1,5 @@
 package tbc
 
-import (
-	"encoding/binary"
-	"math"
-)
-
 type Options struct {
 	SpellOrder []string
 	UseAI      bool // when set true, the AI will modulate the rotations to maximize DPS and mana.
@@ -13,12 +8,17 @@ type Options struct {
 
 	NumBloodlust int
 	NumDrums     int
+	Targets      int // enemies in range, Chain Lightning jumps to up to 2 more. 0 or 1 is a single target.
 
 	Buffs    Buffs
 	Consumes Consumes
 	Talents  Talents
 	Totems   Totems
 
+	Cooldowns CooldownOptions // when to use trinkets, bloodlust, potions, etc.
+	Precision Precision       // run until the DPS is this precise instead of a fixed number of iterations.
+	Job       *Job            `json:"-"` // reports progress and cancels the run, nil for neither.
+
 	DPSReportTime int // how many seconds to calculate DPS for.
 
 	Debug bool // enables debug printing.
@@ -26,21 +26,6 @@ type Options struct {
 	// make it easier to integrate into different output systems.
 }
 
-// Pack is how to convert all options/buffs/consumes/etc to reproduce the UI state
-// so that a simulation can be shared. I am using byte packing here because most options are bools
-// and this makes it easy to pack it all together.
-// I also have a JSON parser for these in the command line interface. This compressed format
-// is used to allow for shorter URLs
-func (o Options) Pack() []byte {
-	// first byte is version
-	bytes := []byte{0, byte(o.NumBloodlust), byte(o.NumDrums)}
-	bytes = append(bytes, o.Buffs.Pack()...)
-	bytes = append(bytes, o.Consumes.Pack()...)
-	bytes = append(bytes, o.Talents.Pack()...)
-	bytes = append(bytes, o.Totems.Pack()...)
-	return bytes
-}
-
 type Totems struct {
 	TotemOfWrath int
 	WrathOfAir   bool
@@ -48,22 +33,6 @@ type Totems struct {
 	Cyclone2PC   bool // Cyclone set 2pc bonus
 }
 
-func (tt Totems) Pack() []byte {
-	var opts byte
-	if tt.WrathOfAir {
-		opts = opts | 1
-	}
-	if tt.ManaStream {
-		opts = opts | 2
-	}
-	if tt.Cyclone2PC {
-		opts = opts | 4
-	}
-	bytes := []byte{byte(tt.TotemOfWrath), opts}
-
-	return bytes
-}
-
 func (tt Totems) AddStats(s Stats) Stats {
 	s[StatSpellCrit] += 66.24 * float64(tt.TotemOfWrath)
 	s[StatSpellHit] += 37.8 * float64(tt.TotemOfWrath)
@@ -91,26 +60,6 @@ type Talents struct {
 	Concussion         float64 // temp hack to speed up not converting this to a int on every spell cast
 }
 
-func (t Talents) Pack() []byte {
-	var elemast byte
-	if t.ElementalMastery {
-		elemast = 1
-	}
-	bytes := []byte{
-		byte(t.LightninOverload),
-		byte(t.ElementalPrecision),
-		byte(t.NaturesGuidance),
-		byte(t.TidalMastery),
-		elemast,
-		byte(t.UnrelentingStorm),
-		byte(t.CallOfThunder),
-		byte(t.Convection),
-		byte(t.Concussion),
-	}
-
-	return bytes
-}
-
 func (t Talents) AddStats(s Stats) Stats {
 	s[StatSpellHit] += 25.2 * float64(t.ElementalPrecision)
 	s[StatSpellHit] += 12.6 * float64(t.NaturesGuidance)
@@ -149,75 +98,6 @@ type Buffs struct {
 	Custom Stats
 }
 
-func (b Buffs) Pack() []byte {
-	var opt1 byte
-	var opt2 byte
-	if b.ArcaneInt {
-		opt1 = opt1 | 1
-	}
-	if b.GiftOftheWild {
-		opt1 = opt1 | 1<<1
-	}
-	if b.BlessingOfKings {
-		opt1 = opt1 | 1<<2
-	}
-	if b.ImprovedBlessingOfWisdom {
-		opt1 = opt1 | 1<<3
-	}
-	if b.ImprovedDivineSpirit {
-		opt1 = opt1 | 1<<4
-	}
-	if b.Moonkin {
-		opt1 = opt1 | 1<<5
-	}
-	if b.MoonkinRavenGoddess {
-		opt1 = opt1 | 1<<6
-	}
-	if b.EyeOfNight {
-		opt1 = opt1 | 1<<7
-	}
-	if b.TwilightOwl {
-		opt2 = opt2 | 1
-	}
-	if b.WaterShield {
-		opt2 = opt2 | 1<<1
-	}
-	if b.JudgementOfWisdom {
-		opt2 = opt2 | 1<<2
-	}
-	if b.ImpSealofCrusader {
-		opt2 = opt2 | 1<<3
-	}
-	if b.Misery {
-		opt2 = opt2 | 1<<4
-	}
-
-	bytes := []byte{
-		opt1, opt2, b.WaterShieldPPM,
-		0, 0, // spriest dps
-		byte(b.Race),
-		0,
-	}
-
-	binary.LittleEndian.PutUint16(bytes[3:], b.SpriestDPS)
-
-	var customBytes []byte
-	for _, v := range b.Custom {
-		if v != 0 {
-			bytes[6] = byte(len(b.Custom))
-			customBytes = make([]byte, len(b.Custom)*8)
-			for i, rv := range b.Custom {
-				binary.LittleEndian.PutUint64(customBytes[i*8:], math.Float64bits(rv))
-			}
-			break
-		}
-	}
-	if len(customBytes) > 0 {
-		bytes = append(bytes, customBytes...)
-	}
-	return bytes
-}
-
 type RaceBonusType byte
 
 // These values are used directly in the dropdown in index.html
@@ -281,35 +161,8 @@ type Consumes struct {
 	DestructionPotion bool
 	SuperManaPotion   bool
 	DarkRune          bool
-}
 
-func (c Consumes) Pack() []byte {
-	var opt1 byte
-	if c.BrilliantWizardOil {
-		opt1 = opt1 | 1
-	}
-	if c.MajorMageblood {
-		opt1 = opt1 | 1<<1
-	}
-	if c.FlaskOfBlindingLight {
-		opt1 = opt1 | 1<<2
-	}
-	if c.FlaskOfMightyRestoration {
-		opt1 = opt1 | 1<<3
-	}
-	if c.BlackendBasilisk {
-		opt1 = opt1 | 1<<4
-	}
-	if c.DestructionPotion {
-		opt1 = opt1 | 1<<5
-	}
-	if c.SuperManaPotion {
-		opt1 = opt1 | 1<<6
-	}
-	if c.DarkRune {
-		opt1 = opt1 | 1<<7
-	}
-	return []byte{opt1}
+	Policies ConsumePolicies // when to use the consumables above.
 }
 
 func (c Consumes) AddStats(s Stats) Stats {
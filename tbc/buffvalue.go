@@ -0,0 +1,206 @@
+package tbc
+
+import (
+	"sort"
+)
+
+// buffToggle is a single buff, debuff, totem or consumable that can be switched on or off in the options.
+type buffToggle struct {
+	name     string
+	category string
+	enabled  func(o Options) bool
+	set      func(o *Options, on bool)
+	requires func(o Options) bool // only worth anything when this is true, for example raven goddess needs moonkin aura.
+}
+
+func boolToggle(name, category string, field func(o *Options) *bool) buffToggle {
+	return buffToggle{
+		name:     name,
+		category: category,
+		enabled:  func(o Options) bool { return *field(&o) },
+		set:      func(o *Options, on bool) { *field(o) = on },
+	}
+}
+
+func (t buffToggle) needs(requires func(o Options) bool) buffToggle {
+	t.requires = requires
+	return t
+}
+
+var buffToggles = []buffToggle{
+	boolToggle("Arcane Intellect", "raid", func(o *Options) *bool { return &o.Buffs.ArcaneInt }),
+	boolToggle("Gift of the Wild", "raid", func(o *Options) *bool { return &o.Buffs.GiftOftheWild }),
+	boolToggle("Blessing of Kings", "raid", func(o *Options) *bool { return &o.Buffs.BlessingOfKings }),
+	boolToggle("Improved Blessing of Wisdom", "raid", func(o *Options) *bool { return &o.Buffs.ImprovedBlessingOfWisdom }),
+	boolToggle("Improved Divine Spirit", "raid", func(o *Options) *bool { return &o.Buffs.ImprovedDivineSpirit }),
+	{
+		name:     "Bloodlust",
+		category: "raid",
+		enabled:  func(o Options) bool { return o.NumBloodlust > 0 },
+		set: func(o *Options, on bool) {
+			if !on {
+				o.NumBloodlust = 0
+			} else if o.NumBloodlust == 0 {
+				o.NumBloodlust = 1
+			}
+		},
+	},
+
+	boolToggle("Moonkin Aura", "party", func(o *Options) *bool { return &o.Buffs.Moonkin }),
+	boolToggle("Idol of the Raven Goddess", "party", func(o *Options) *bool { return &o.Buffs.MoonkinRavenGoddess }).
+		needs(func(o Options) bool { return o.Buffs.Moonkin }),
+	{
+		name:     "Shadow Priest",
+		category: "party",
+		enabled:  func(o Options) bool { return o.Buffs.SpriestDPS > 0 },
+		set: func(o *Options, on bool) {
+			if !on {
+				o.Buffs.SpriestDPS = 0
+			} else if o.Buffs.SpriestDPS == 0 {
+				o.Buffs.SpriestDPS = 1000
+			}
+		},
+	},
+	boolToggle("Eye of the Night", "party", func(o *Options) *bool { return &o.Buffs.EyeOfNight }),
+	boolToggle("Chain of the Twilight Owl", "party", func(o *Options) *bool { return &o.Buffs.TwilightOwl }),
+	{
+		name:     "Drums of Battle",
+		category: "party",
+		enabled:  func(o Options) bool { return o.NumDrums > 0 },
+		set: func(o *Options, on bool) {
+			if !on {
+				o.NumDrums = 0
+			} else if o.NumDrums == 0 {
+				o.NumDrums = 1
+			}
+		},
+	},
+
+	boolToggle("Water Shield", "self", func(o *Options) *bool { return &o.Buffs.WaterShield }),
+
+	boolToggle("Judgement of Wisdom", "debuff", func(o *Options) *bool { return &o.Buffs.JudgementOfWisdom }),
+	boolToggle("Improved Seal of the Crusader", "debuff", func(o *Options) *bool { return &o.Buffs.ImpSealofCrusader }),
+	boolToggle("Misery", "debuff", func(o *Options) *bool { return &o.Buffs.Misery }),
+
+	{
+		name:     "Totem of Wrath",
+		category: "totem",
+		enabled:  func(o Options) bool { return o.Totems.TotemOfWrath > 0 },
+		set: func(o *Options, on bool) {
+			if !on {
+				o.Totems.TotemOfWrath = 0
+			} else if o.Totems.TotemOfWrath == 0 {
+				o.Totems.TotemOfWrath = 1
+			}
+		},
+	},
+	boolToggle("Wrath of Air Totem", "totem", func(o *Options) *bool { return &o.Totems.WrathOfAir }),
+	boolToggle("Cyclone 2pc (Wrath of Air)", "totem", func(o *Options) *bool { return &o.Totems.Cyclone2PC }).
+		needs(func(o Options) bool { return o.Totems.WrathOfAir }),
+	boolToggle("Mana Spring Totem", "totem", func(o *Options) *bool { return &o.Totems.ManaStream }),
+
+	boolToggle("Brilliant Wizard Oil", "consumable", func(o *Options) *bool { return &o.Consumes.BrilliantWizardOil }),
+	boolToggle("Elixir of Major Mageblood", "consumable", func(o *Options) *bool { return &o.Consumes.MajorMageblood }),
+	// Only one flask can be active, so switching one on replaces the other.
+	{
+		name:     "Flask of Blinding Light",
+		category: "consumable",
+		enabled:  func(o Options) bool { return o.Consumes.FlaskOfBlindingLight },
+		set: func(o *Options, on bool) {
+			o.Consumes.FlaskOfBlindingLight = on
+			if on {
+				o.Consumes.FlaskOfMightyRestoration = false
+			}
+		},
+	},
+	{
+		name:     "Flask of Mighty Restoration",
+		category: "consumable",
+		enabled:  func(o Options) bool { return o.Consumes.FlaskOfMightyRestoration },
+		set: func(o *Options, on bool) {
+			o.Consumes.FlaskOfMightyRestoration = on
+			if on {
+				o.Consumes.FlaskOfBlindingLight = false
+			}
+		},
+	},
+	boolToggle("Blackened Basilisk", "consumable", func(o *Options) *bool { return &o.Consumes.BlackendBasilisk }),
+	boolToggle("Destruction Potion", "consumable", func(o *Options) *bool { return &o.Consumes.DestructionPotion }),
+	boolToggle("Super Mana Potion", "consumable", func(o *Options) *bool { return &o.Consumes.SuperManaPotion }),
+	boolToggle("Dark Rune", "consumable", func(o *Options) *bool { return &o.Consumes.DarkRune }),
+}
+
+// BuffValue is how much DPS a single buff, debuff, totem or consumable is worth.
+type BuffValue struct {
+	Name     string
+	Category string // raid, party, self, debuff, totem or consumable.
+	Enabled  bool   // if it is on in the current options.
+
+	DPS      float64 // DPS with it on.
+	Delta    float64 // DPS with it on minus DPS with it off.
+	DeltaErr float64 // standard error of Delta, from paired iterations.
+
+	Iterations int // iterations simulated, can vary with a precision target.
+}
+
+// Conf90 is the 90% confidence interval of the DPS contribution.
+func (bv BuffValue) Conf90() (float64, float64) {
+	return bv.Delta - 1.645*bv.DeltaErr, bv.Delta + 1.645*bv.DeltaErr
+}
+
+// BuffValueResult is the output of BuffValues.
+type BuffValueResult struct {
+	BaseDPS float64     // DPS with the current options.
+	Values  []BuffValue // best first
+}
+
+// BuffValues ranks every buff, debuff, totem and consumable in the options by how much DPS it adds.
+//
+// Each one is flipped from its current state while everything else is kept, so a buff already
+// on is simulated off and one that is off is simulated on. All variants share the seed of the
+// current options, so Delta is from paired iterations.
+func BuffValues(opts Options, equip Equipment, seconds int, numSims int) BuffValueResult {
+	opts.UseAI = true
+	toggles := []int{}
+	for i, t := range buffToggles {
+		if t.requires == nil || t.requires(opts) {
+			toggles = append(toggles, i)
+		}
+	}
+	opts.Job.Phase("buffs", phaseTotal(opts, len(toggles)+1, numSims))
+	base := newSimRunner(CalculateTotalStats(opts, equip), equip, opts, seconds)
+	output := BuffValueResult{}
+	output.BaseDPS, _ = meanStdev(base.first(base.untilPrecise(numSims, nil)))
+
+	type res struct {
+		idx   int
+		value BuffValue
+	}
+	found := make([]res, len(toggles))
+	parallel(len(toggles), func(i int) {
+		t := buffToggles[toggles[i]]
+		on := t.enabled(opts)
+		vopts := opts
+		t.set(&vopts, !on)
+		dps, baseDPS := runPairedDPS(base, CalculateTotalStats(vopts, equip), equip, vopts, seconds, numSims)
+		bv := BuffValue{Name: t.name, Category: t.category, Enabled: on, Iterations: len(dps)}
+		_, bv.Delta, bv.DeltaErr = pairedDiffs(dps, baseDPS)
+		bv.DPS, _ = meanStdev(dps)
+		if on {
+			bv.Delta = -bv.Delta
+			bv.DPS, _ = meanStdev(baseDPS)
+		}
+		found[i] = res{idx: toggles[i], value: bv}
+	})
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].value.Delta == found[j].value.Delta {
+			return found[i].idx < found[j].idx
+		}
+		return found[i].value.Delta > found[j].value.Delta
+	})
+	output.Values = make([]BuffValue, len(found))
+	for i, r := range found {
+		output.Values[i] = r.value
+	}
+	return output
+}
@@ -0,0 +1,28 @@
+package tbc
+
+import "testing"
+
+func TestBuffTogglesRoundTrip(t *testing.T) {
+	for _, bt := range buffToggles {
+		opts := Options{}
+		bt.set(&opts, true)
+		if !bt.enabled(opts) {
+			t.Errorf("%s: not enabled after switching on", bt.name)
+		}
+		bt.set(&opts, false)
+		if bt.enabled(opts) {
+			t.Errorf("%s: still enabled after switching off", bt.name)
+		}
+	}
+
+	opts := Options{}
+	opts.Consumes.FlaskOfMightyRestoration = true
+	for _, bt := range buffToggles {
+		if bt.name == "Flask of Blinding Light" {
+			bt.set(&opts, true)
+		}
+	}
+	if opts.Consumes.FlaskOfMightyRestoration {
+		t.Errorf("two flasks active at once")
+	}
+}
@@ -0,0 +1,254 @@
+package tbc
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// cacheVersion is part of every cache key, bump it when a change to the sim changes its results
+// so results from before aren't reused.
+const cacheVersion = 2
+
+// resultCache keeps sim results by a hash of everything that decides them: stats, gear, options,
+// rotation, duration, seed and (for summaries) iterations. The sim is deterministic for a seed, so
+// a cached result is exactly what simming again would give.
+//
+// Results are kept in memory, least recently used dropped first, and optionally in a directory
+// so later runs can reuse them. Everything cached is for the item data it was simmed with:
+// loading data clears the memory, and stored results from other item data are deleted when found.
+type resultCache struct {
+	mu      sync.Mutex
+	max     int // most values kept in memory, every iteration of a DPS list counts as one.
+	size    int
+	entries map[string]*list.Element
+	lru     *list.List // front is the most recently used.
+	dir     string     // on-disk store, "" for memory only.
+	data    string     // hash of the item data loaded.
+}
+
+type cacheEntry struct {
+	key   string
+	value interface{} // []float64 or SimResult.
+	size  int
+}
+
+// storedEntry is a cached result in the on-disk store.
+type storedEntry struct {
+	Data    string     // hash of the item data it was simmed with.
+	DPS     []float64  `json:",omitempty"`
+	Summary *SimResult `json:",omitempty"`
+}
+
+var cache = &resultCache{max: 4 << 20, entries: map[string]*list.Element{}, lru: list.New()}
+
+// SetCacheSize sets how many results are kept in memory, counting each iteration of a result as one.
+// Defaults to about four million, 0 turns the cache off, on disk too.
+func SetCacheSize(n int) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.max = n
+	cache.evict()
+}
+
+// SetCacheDir also keeps results in dir, so later runs can reuse them. "" keeps them only in memory.
+func SetCacheDir(dir string) error {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	cache.mu.Lock()
+	cache.dir = dir
+	cache.mu.Unlock()
+	return nil
+}
+
+// dataLoaded is called with every data file loaded, clearing results simmed with the data before it.
+func (c *resultCache) dataLoaded(data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h := sha256.Sum256(append([]byte(c.data), data...))
+	c.data = hex.EncodeToString(h[:])
+	c.entries = map[string]*list.Element{}
+	c.lru.Init()
+	c.size = 0
+}
+
+// get returns a cached value, from memory or the store.
+func (c *resultCache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.max <= 0 {
+		return nil, false
+	}
+	if el, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(el)
+		return el.Value.(*cacheEntry).value, true
+	}
+	if c.dir == "" {
+		return nil, false
+	}
+	file := filepath.Join(c.dir, key+".json")
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, false
+	}
+	stored := storedEntry{}
+	if json.Unmarshal(data, &stored) != nil || stored.Data != c.data {
+		os.Remove(file) // from other item data, or damaged.
+		return nil, false
+	}
+	var value interface{} = stored.DPS
+	if stored.Summary != nil {
+		value = *stored.Summary
+	}
+	c.add(key, value)
+	return value, true
+}
+
+// put caches a value, in memory and the store.
+func (c *resultCache) put(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.max <= 0 {
+		return
+	}
+	c.add(key, value)
+	if c.dir == "" {
+		return
+	}
+	stored := storedEntry{Data: c.data}
+	switch v := value.(type) {
+	case []float64:
+		stored.DPS = v
+	case SimResult:
+		stored.Summary = &v
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return
+	}
+	// Write then rename, so another run never reads half a file.
+	tmp, err := ioutil.TempFile(c.dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	os.Rename(tmp.Name(), filepath.Join(c.dir, key+".json"))
+}
+
+// add must be called with the cache locked.
+func (c *resultCache) add(key string, value interface{}) {
+	size := 1
+	if dps, ok := value.([]float64); ok {
+		size = len(dps)
+	}
+	if el, ok := c.entries[key]; ok {
+		c.size -= el.Value.(*cacheEntry).size
+		c.lru.Remove(el)
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, value: value, size: size})
+	c.size += size
+	c.evict()
+}
+
+// evict must be called with the cache locked.
+func (c *resultCache) evict() {
+	for c.size > c.max && c.lru.Len() > 0 {
+		e := c.lru.Remove(c.lru.Back()).(*cacheEntry)
+		delete(c.entries, e.key)
+		c.size -= e.size
+	}
+}
+
+// dps returns a copy of the cached DPS of each iteration for key, nil if none are cached.
+func (c *resultCache) dps(key string) []float64 {
+	v, ok := c.get(key)
+	if !ok {
+		return nil
+	}
+	return append([]float64(nil), v.([]float64)...)
+}
+
+// putDPS caches the DPS of each iteration, unless more iterations are cached already.
+func (c *resultCache) putDPS(key string, dps []float64) {
+	c.mu.Lock()
+	el, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && len(el.Value.(*cacheEntry).value.([]float64)) >= len(dps) {
+		return
+	}
+	c.put(key, append([]float64(nil), dps...))
+}
+
+// simKey hashes everything that decides the result of simming with the options: kind is what the
+// result is ("dps" or "summary") and extra anything else, like iterations. The job and debug
+// options aren't part of it, sims with debug logs shouldn't be cached at all.
+func simKey(kind string, stats Stats, equip Equipment, opts Options, seconds int, extra ...float64) string {
+	h := sha256.New()
+	var buf [8]byte
+	integer := func(v int64) {
+		binary.LittleEndian.PutUint64(buf[:], uint64(v))
+		h.Write(buf[:])
+	}
+	num := func(v float64) {
+		integer(int64(math.Float64bits(v)))
+	}
+	flag := func(b bool) {
+		if b {
+			integer(1)
+		} else {
+			integer(0)
+		}
+	}
+	h.Write([]byte(kind))
+	integer(cacheVersion)
+	integer(int64(len(stats)))
+	for _, v := range stats {
+		num(v)
+	}
+	integer(int64(len(equip)))
+	for _, item := range equip {
+		integer(int64(item.ID))
+		integer(int64(item.Slot))
+		integer(int64(item.SubSlot))
+		flag(item.Activate != nil)
+		integer(int64(item.ActivateCD))
+		integer(int64(item.CoolID))
+		integer(int64(item.Enchant.ID))
+		integer(int64(len(item.Gems)))
+		for _, g := range item.Gems {
+			integer(int64(g.ID))
+			integer(int64(g.Color))
+			flag(g.Activate != nil)
+		}
+	}
+	h.Write(opts.Pack())
+	integer(int64(len(opts.SpellOrder)))
+	for _, s := range opts.SpellOrder {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	flag(opts.UseAI)
+	integer(opts.RSeed)
+	integer(int64(seconds))
+	for _, v := range extra {
+		num(v)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
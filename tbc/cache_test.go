@@ -0,0 +1,90 @@
+package tbc
+
+import (
+	"container/list"
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestResultCache(t *testing.T) {
+	defer SetCacheDir("")
+	dir := t.TempDir()
+	if err := SetCacheDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	equip := NewEquipmentSet("Tidefury Helm", "Tidefury Chestpiece", "Gavel of Unearthed Secrets", "Quagmirran's Eye")
+	opts := Options{UseAI: true, RSeed: 42, Talents: Talents{Concussion: 5, CallOfThunder: 5}}
+	stats := CalculateTotalStats(opts, equip)
+
+	first := newSimRunner(stats, equip, opts, 60)
+	want := first.first(50)
+	if first.simmed != 50 {
+		t.Fatalf("expected all 50 iterations to be simmed, got %d", first.simmed)
+	}
+
+	// Another runner of the same setup gets them from memory, and extends them with the same iterations.
+	again := newSimRunner(stats, equip, opts, 60)
+	if got := again.first(50); again.simmed != 0 || !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected the cached iterations, simmed %d", again.simmed)
+	}
+	more := again.first(60)
+	SetCacheSize(0)
+	uncached := newSimRunner(stats, equip, opts, 60).first(60)
+	SetCacheSize(4 << 20)
+	if !reflect.DeepEqual(more, uncached) {
+		t.Fatalf("iterations after the cached ones changed:\ngot  %v\nwant %v", more[50:], uncached[50:])
+	}
+
+	// A different seed isn't the same result.
+	opts.RSeed = 43
+	if other := newSimRunner(stats, equip, opts, 60); other.cached != 0 {
+		t.Fatalf("a different seed got %d cached iterations", other.cached)
+	}
+	opts.RSeed = 42
+
+	// With memory cleared they come from the store.
+	cache.mu.Lock()
+	cache.entries = map[string]*list.Element{}
+	cache.lru.Init()
+	cache.size = 0
+	cache.mu.Unlock()
+	if stored := newSimRunner(stats, equip, opts, 60); stored.cached != 60 {
+		t.Fatalf("expected 60 iterations from the store, got %d", stored.cached)
+	}
+
+	// New item data invalidates everything cached.
+	saved := cache.data
+	cache.dataLoaded([]byte("new items"))
+	defer func() { cache.data = saved }()
+	if stale := newSimRunner(stats, equip, opts, 60); stale.cached != 0 {
+		t.Fatalf("expected nothing cached after new item data, got %d iterations", stale.cached)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	key := simKey("dps", stats, equip, opts, 60)
+	for _, f := range files {
+		if filepath.Base(f) == key+".json" {
+			t.Fatalf("stale result was left in the store")
+		}
+	}
+}
+
+func TestSummaryCache(t *testing.T) {
+	equip := NewEquipmentSet("Tidefury Helm", "Tidefury Chestpiece", "Gavel of Unearthed Secrets")
+	opts := Options{SpellOrder: []string{"pri", "CL6", "LB12"}, RSeed: 7}
+	stats := CalculateTotalStats(opts, equip)
+
+	want := SummarizeSim(NewSim(stats, equip, opts), 60, 30)
+	job := NewJob(context.Background(), nil)
+	opts.Job = job
+	if got := SummarizeSim(NewSim(stats, equip, opts), 60, 30); !reflect.DeepEqual(got, want) || job.Progress().Iterations != 30 {
+		t.Fatalf("expected the cached summary counted to the job, got %d iterations", job.Progress().Iterations)
+	}
+
+	// Without "pri" it's a different rotation.
+	opts.SpellOrder = opts.SpellOrder[1:]
+	if got := SummarizeSim(NewSim(stats, equip, opts), 60, 30); reflect.DeepEqual(got, want) {
+		t.Fatalf("a different rotation got the cached summary")
+	}
+}
@@ -0,0 +1,56 @@
+package tbc
+
+// Setup is a full character to simulate: options and the gear they are worn with.
+type Setup struct {
+	Options Options
+	Equip   Equipment
+}
+
+// Comparison is the output of Compare.
+type Comparison struct {
+	BaseDPS   float64
+	BaseStdev float64
+	DPS       float64
+	Stdev     float64
+
+	Delta    float64 // DPS of the other setup minus DPS of the base.
+	DeltaErr float64 // standard error of Delta, from paired iterations.
+
+	Iterations int // iterations simulated, can vary with a precision target.
+}
+
+// Conf90 is the 90% confidence interval of the DPS difference.
+func (c Comparison) Conf90() (float64, float64) {
+	return c.Delta - 1.645*c.DeltaErr, c.Delta + 1.645*c.DeltaErr
+}
+
+// Compare simulates two setups with the AI rotation and returns the DPS difference between them.
+// Both use the seed and precision target of base, so iterations are paired and the difference is
+// much more precise than either DPS on its own.
+func Compare(base Setup, other Setup, seconds int, numSims int) Comparison {
+	return CompareSetups(base, []Setup{other}, seconds, numSims)[0]
+}
+
+// CompareSetups compares each of setups against base, like Compare.
+// Every setup uses the seed of base, so they are also paired with each other.
+func CompareSetups(base Setup, setups []Setup, seconds int, numSims int) []Comparison {
+	base.Options.UseAI = true
+	base.Options.Job.Phase("comparing", phaseTotal(base.Options, len(setups)+1, numSims))
+	baseRunner := newSimRunner(CalculateTotalStats(base.Options, base.Equip), base.Equip, base.Options, seconds)
+
+	results := make([]Comparison, len(setups))
+	parallel(len(setups), func(i int) {
+		other := setups[i]
+		other.Options.UseAI = true
+		other.Options.RSeed = base.Options.RSeed
+		other.Options.Precision = base.Options.Precision
+		other.Options.Job = base.Options.Job
+		dps, baseDPS := runPairedDPS(baseRunner, CalculateTotalStats(other.Options, other.Equip), other.Equip, other.Options, seconds, numSims)
+		c := Comparison{Iterations: len(dps)}
+		c.BaseDPS, c.BaseStdev = meanStdev(baseDPS)
+		c.DPS, c.Stdev = meanStdev(dps)
+		_, c.Delta, c.DeltaErr = pairedDiffs(dps, baseDPS)
+		results[i] = c
+	})
+	return results
+}
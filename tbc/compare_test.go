@@ -0,0 +1,20 @@
+package tbc
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	equip := NewEquipmentSet("Tidefury Helm", "Tidefury Chestpiece", "Gavel of Unearthed Secrets")
+	opts := Options{Talents: Talents{Concussion: 5, CallOfThunder: 5}}
+
+	same := Compare(Setup{opts, equip}, Setup{opts, equip}, 60, 50)
+	if same.Delta != 0 || same.DeltaErr != 0 || same.Iterations != 50 {
+		t.Fatalf("identical setups should have no difference, got %+v", same)
+	}
+
+	better := opts
+	better.Buffs.Custom = Stats{StatSpellDmg: 200}
+	c := Compare(Setup{opts, equip}, Setup{better, equip}, 60, 50)
+	if c.Delta <= 0 || c.DPS <= c.BaseDPS {
+		t.Fatalf("more spell power should be a DPS gain, got %+v", c)
+	}
+}
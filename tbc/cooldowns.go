@@ -0,0 +1,166 @@
+package tbc
+
+import (
+	"strings"
+)
+
+// CooldownPolicy decides when on-use effects (trinkets, bloodlust, drums,
+// elemental mastery, racials and destruction potion) are activated.
+type CooldownPolicy byte
+
+const (
+	CooldownPolicyASAP            CooldownPolicy = iota // use everything as soon as it comes off CD
+	CooldownPolicyAlignBloodlust                        // hold cooldowns until bloodlust is active
+	CooldownPolicyHoldForExecute                        // only use a cooldown if it will be back up for the execute phase
+	CooldownPolicyStaggerTrinkets                       // offset trinkets by half their cooldown so they never overlap
+	CooldownPolicyPullOnly                              // use each cooldown exactly once, on the pull
+
+	CooldownPolicyLen
+)
+
+// These names are used for the --cdpolicy flag and the config file.
+func (p CooldownPolicy) String() string {
+	switch p {
+	case CooldownPolicyASAP:
+		return "asap"
+	case CooldownPolicyAlignBloodlust:
+		return "bloodlust"
+	case CooldownPolicyHoldForExecute:
+		return "execute"
+	case CooldownPolicyStaggerTrinkets:
+		return "stagger"
+	case CooldownPolicyPullOnly:
+		return "pull"
+	}
+	return "unknown"
+}
+
+// ParseCooldownPolicy converts the name of a policy back to the policy.
+// Returns false if the name doesn't match any policy.
+func ParseCooldownPolicy(name string) (CooldownPolicy, bool) {
+	for p := CooldownPolicyASAP; p < CooldownPolicyLen; p++ {
+		if strings.EqualFold(p.String(), name) {
+			return p, true
+		}
+	}
+	return CooldownPolicyASAP, false
+}
+
+// CooldownOptions configures how the sim spends cooldowns.
+type CooldownOptions struct {
+	Policy CooldownPolicy
+
+	BloodlustAt    int     // seconds into the fight to use the first bloodlust.
+	ExecutePercent float64 // fraction of the fight at the end treated as execute. Defaults to 0.2
+	TrinketFirst   int32   // item ID of the on-use trinket to pop first, 0 lets gear order decide.
+}
+
+func (co CooldownOptions) executePercent() float64 {
+	if co.ExecutePercent <= 0 || co.ExecutePercent > 1 {
+		return 0.2
+	}
+	return co.ExecutePercent
+}
+
+// executeStart is the tick the execute phase begins.
+func (sim *Simulation) executeStart() int {
+	return sim.endTick - int(float64(sim.endTick)*sim.Options.Cooldowns.executePercent())
+}
+
+func (sim *Simulation) hasAura(id int32) bool {
+	for i := range sim.Auras {
+		if sim.Auras[i].ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// useBloodlust reports if a bloodlust should be used this tick.
+// Multiple bloodlusts are assumed to come from different shaman and are chained back to back.
+func (sim *Simulation) useBloodlust() bool {
+	if sim.Options.NumBloodlust <= sim.bloodlustCasts || sim.CDs[MagicIDBloodlust] > 0 {
+		return false
+	}
+	if sim.bloodlustCasts > 0 {
+		return true // chain the rest after the first.
+	}
+	switch sim.Options.Cooldowns.Policy {
+	case CooldownPolicyHoldForExecute:
+		return sim.CurrentTick >= sim.executeStart()
+	case CooldownPolicyPullOnly:
+		return true
+	}
+	return sim.CurrentTick >= sim.Options.Cooldowns.BloodlustAt*TicksPerSecond
+}
+
+// useCooldown reports if the cooldown with the given ID should be used right now.
+// cdSeconds is how long until the cooldown could be used again.
+// Callers are expected to have already checked the cooldown is ready.
+func (sim *Simulation) useCooldown(id int32, cdSeconds int) bool {
+	ok := true
+	switch sim.Options.Cooldowns.Policy {
+	case CooldownPolicyAlignBloodlust:
+		// Once all bloodlusts are gone there is nothing left to align with.
+		if sim.Options.NumBloodlust > sim.bloodlustCasts && !sim.hasAura(MagicIDBloodlust) {
+			ok = false
+		}
+	case CooldownPolicyHoldForExecute:
+		execute := sim.executeStart()
+		ok = sim.CurrentTick >= execute || sim.CurrentTick+cdSeconds*TicksPerSecond <= execute
+	case CooldownPolicyPullOnly:
+		ok = sim.cdUses[id] == 0
+	}
+	if ok {
+		sim.cdUses[id]++
+	}
+	return ok
+}
+
+// useTrinket is useCooldown with the extra trinket rules for staggering.
+func (sim *Simulation) useTrinket(item Item) bool {
+	if sim.Options.Cooldowns.Policy == CooldownPolicyStaggerTrinkets && sim.CurrentTick < sim.trinketStaggerAt {
+		return false
+	}
+	if !sim.useCooldown(item.CoolID, item.ActivateCD) {
+		return false
+	}
+	sim.trinketStaggerAt = sim.CurrentTick + item.ActivateCD*TicksPerSecond/2
+	return true
+}
+
+// CooldownPolicyResult is the DPS of a single cooldown policy for a single fight length.
+type CooldownPolicyResult struct {
+	Policy  CooldownPolicy
+	Seconds int
+	DPS     float64
+	Stdev   float64
+
+	Iterations int // iterations simulated, can vary with a precision target.
+}
+
+// EvaluateCooldownPolicies sims each policy at each fight duration.
+// All policies for a duration share a random seed so the differences are from the policy and not the dice.
+// Results are grouped by duration in the order given.
+func EvaluateCooldownPolicies(opts Options, equip Equipment, policies []CooldownPolicy, durations []int, numSims int) []CooldownPolicyResult {
+	if len(policies) == 0 {
+		for p := CooldownPolicyASAP; p < CooldownPolicyLen; p++ {
+			policies = append(policies, p)
+		}
+	}
+	opts.UseAI = true
+	stats := CalculateTotalStats(opts, equip)
+
+	opts.Job.Phase("cooldown policies", phaseTotal(opts, len(policies)*len(durations), numSims))
+	results := make([]CooldownPolicyResult, 0, len(policies)*len(durations))
+	for _, dur := range durations {
+		for _, policy := range policies {
+			popts := opts
+			popts.Cooldowns.Policy = policy
+			dps := runDPS(stats, equip, popts, dur, numSims)
+			mean, stdev := meanStdev(dps)
+			results = append(results, CooldownPolicyResult{Policy: policy, Seconds: dur, DPS: mean, Stdev: stdev, Iterations: len(dps)})
+		}
+	}
+	return results
+}
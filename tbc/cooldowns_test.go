@@ -0,0 +1,183 @@
+package tbc
+
+import "testing"
+
+// cooldownSim is a sim of a 300 second fight that hasn't started, for calling the cooldown rules directly.
+func cooldownSim(co CooldownOptions, numBloodlust int) *Simulation {
+	equip := NewEquipmentSet("Tidefury Helm", "Icon of the Silver Crescent", "Figurine - Living Ruby Serpent")
+	opts := Options{UseAI: true, NumBloodlust: numBloodlust, Cooldowns: co}
+	sim := NewSim(CalculateTotalStats(opts, equip), equip, opts)
+	sim.reset()
+	sim.endTick = 300 * TicksPerSecond
+	return sim
+}
+
+func TestPolicyNames(t *testing.T) {
+	for p := CooldownPolicyASAP; p < CooldownPolicyLen; p++ {
+		if got, ok := ParseCooldownPolicy(p.String()); !ok || got != p {
+			t.Errorf("%s didn't parse back to itself, got %s", p, got)
+		}
+	}
+	if _, ok := ParseCooldownPolicy("never"); ok {
+		t.Errorf("expected an unknown policy name to fail")
+	}
+}
+
+func TestExecuteStart(t *testing.T) {
+	cases := []struct {
+		percent float64
+		want    int // seconds
+	}{
+		{0, 240}, // defaults to the last 20%
+		{0.5, 150},
+		{2, 240}, // out of range falls back to the default
+	}
+	for _, c := range cases {
+		sim := cooldownSim(CooldownOptions{ExecutePercent: c.percent}, 0)
+		if got := sim.executeStart(); got != c.want*TicksPerSecond {
+			t.Errorf("execute percent %0.1f: expected execute at %ds, got %ds", c.percent, c.want, got/TicksPerSecond)
+		}
+	}
+}
+
+func TestUseCooldown(t *testing.T) {
+	cases := []struct {
+		name      string
+		policy    CooldownPolicy
+		bloodlust bool // a bloodlust is still to come
+		active    bool // bloodlust aura is up
+		at        int  // seconds into the fight
+		cd        int
+		uses      int
+		want      bool
+	}{
+		{"asap", CooldownPolicyASAP, true, false, 10, 180, 3, true},
+		{"bloodlust to come", CooldownPolicyAlignBloodlust, true, false, 10, 180, 0, false},
+		{"bloodlust up", CooldownPolicyAlignBloodlust, true, true, 10, 180, 0, true},
+		{"no bloodlust left", CooldownPolicyAlignBloodlust, false, false, 10, 180, 0, true},
+		{"back before execute", CooldownPolicyHoldForExecute, true, false, 60, 180, 0, true},
+		{"not back before execute", CooldownPolicyHoldForExecute, true, false, 61, 180, 0, false},
+		{"in execute", CooldownPolicyHoldForExecute, true, false, 250, 180, 0, true},
+		{"stagger", CooldownPolicyStaggerTrinkets, true, false, 10, 180, 1, true},
+		{"pull", CooldownPolicyPullOnly, true, false, 0, 180, 0, true},
+		{"after pull", CooldownPolicyPullOnly, true, false, 200, 180, 1, false},
+	}
+	for _, c := range cases {
+		numBloodlust := 0
+		if c.bloodlust {
+			numBloodlust = 1
+		}
+		sim := cooldownSim(CooldownOptions{Policy: c.policy}, numBloodlust)
+		if c.active {
+			sim.Auras = append(sim.Auras, Aura{ID: MagicIDBloodlust})
+		}
+		sim.CurrentTick = c.at * TicksPerSecond
+		sim.cdUses[MagicIDEleMastery] = c.uses
+		if got := sim.useCooldown(MagicIDEleMastery, c.cd); got != c.want {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, got)
+		}
+		want := c.uses
+		if c.want {
+			want++ // only uses count
+		}
+		if sim.cdUses[MagicIDEleMastery] != want {
+			t.Errorf("%s: expected %d uses, got %d", c.name, want, sim.cdUses[MagicIDEleMastery])
+		}
+	}
+}
+
+func TestUseBloodlust(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy CooldownPolicy
+		casts  int // bloodlusts already used of 2
+		at     int
+		want   bool
+	}{
+		{"before bloodlust at", CooldownPolicyASAP, 0, 29, false},
+		{"at bloodlust at", CooldownPolicyASAP, 0, 30, true},
+		{"pull ignores bloodlust at", CooldownPolicyPullOnly, 0, 0, true},
+		{"before execute", CooldownPolicyHoldForExecute, 0, 239, false},
+		{"execute", CooldownPolicyHoldForExecute, 0, 240, true},
+		{"chained", CooldownPolicyHoldForExecute, 1, 10, true},
+		{"all used", CooldownPolicyASAP, 2, 100, false},
+	}
+	for _, c := range cases {
+		sim := cooldownSim(CooldownOptions{Policy: c.policy, BloodlustAt: 30}, 2)
+		sim.bloodlustCasts = c.casts
+		sim.CurrentTick = c.at * TicksPerSecond
+		if got := sim.useBloodlust(); got != c.want {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, got)
+		}
+	}
+}
+
+func TestStaggerTrinkets(t *testing.T) {
+	sim := cooldownSim(CooldownOptions{Policy: CooldownPolicyStaggerTrinkets}, 0)
+	icon, serpent := sim.activeEquip[0], sim.activeEquip[1]
+	if !sim.useTrinket(icon) {
+		t.Fatalf("expected the first trinket to be used on the pull")
+	}
+	sim.CurrentTick = 59 * TicksPerSecond
+	if sim.useTrinket(serpent) {
+		t.Fatalf("expected the second trinket to wait for half the first one's cooldown")
+	}
+	sim.CurrentTick = 60 * TicksPerSecond
+	if !sim.useTrinket(serpent) {
+		t.Fatalf("expected the second trinket to be used half way through the first one's cooldown")
+	}
+}
+
+// TestCooldownTiming sims whole fights and checks what the policies used.
+func TestCooldownTiming(t *testing.T) {
+	run := func(co CooldownOptions, seconds int) *Simulation {
+		sim := cooldownSim(co, 1)
+		sim.Run(seconds)
+		return sim
+	}
+
+	// Gear order uses the icon first, the shared trinket cooldown keeps the serpent waiting.
+	if sim := run(CooldownOptions{}, 10); sim.cdUses[MagicIDISCTrink] != 1 || sim.cdUses[MagicIDRubySerpentTrink] != 0 {
+		t.Errorf("expected only the icon to be used, got %v", sim.cdUses)
+	}
+	serpent := ItemsByName["Figurine - Living Ruby Serpent"].ID
+	if sim := run(CooldownOptions{TrinketFirst: serpent}, 10); sim.cdUses[MagicIDISCTrink] != 0 || sim.cdUses[MagicIDRubySerpentTrink] != 1 {
+		t.Errorf("expected only the serpent to be used, got %v", sim.cdUses)
+	}
+
+	if sim := run(CooldownOptions{}, 300); sim.cdUses[MagicIDISCTrink] < 2 {
+		t.Errorf("expected the icon to be used again off cooldown, got %d uses", sim.cdUses[MagicIDISCTrink])
+	}
+	if sim := run(CooldownOptions{Policy: CooldownPolicyPullOnly}, 300); sim.cdUses[MagicIDISCTrink] != 1 {
+		t.Errorf("expected the icon once with pull only, got %d uses", sim.cdUses[MagicIDISCTrink])
+	}
+
+	// Bloodlust waits for its time unless the policy says otherwise, and aligned trinkets wait for it.
+	if sim := run(CooldownOptions{BloodlustAt: 100}, 60); sim.bloodlustCasts != 0 || sim.cdUses[MagicIDISCTrink] != 1 {
+		t.Errorf("expected no bloodlust and the icon on the pull, got %d bloodlusts and %v", sim.bloodlustCasts, sim.cdUses)
+	}
+	if sim := run(CooldownOptions{Policy: CooldownPolicyPullOnly, BloodlustAt: 100}, 60); sim.bloodlustCasts != 1 {
+		t.Errorf("expected bloodlust on the pull with pull only, got %d", sim.bloodlustCasts)
+	}
+	if sim := run(CooldownOptions{Policy: CooldownPolicyAlignBloodlust, BloodlustAt: 100}, 60); sim.cdUses[MagicIDISCTrink] != 0 {
+		t.Errorf("expected the icon to wait for bloodlust, got %d uses", sim.cdUses[MagicIDISCTrink])
+	}
+	if sim := run(CooldownOptions{Policy: CooldownPolicyAlignBloodlust, BloodlustAt: 30}, 60); sim.bloodlustCasts != 1 || sim.cdUses[MagicIDISCTrink] != 1 {
+		t.Errorf("expected the icon with bloodlust, got %d bloodlusts and %v", sim.bloodlustCasts, sim.cdUses)
+	}
+}
+
+func TestEvaluateCooldownPolicies(t *testing.T) {
+	equip := NewEquipmentSet("Tidefury Helm", "Icon of the Silver Crescent")
+	durations := []int{60, 30}
+	results := EvaluateCooldownPolicies(Options{RSeed: 1}, equip, nil, durations, 10)
+	if len(results) != len(durations)*int(CooldownPolicyLen) {
+		t.Fatalf("expected every policy at every duration, got %d results", len(results))
+	}
+	for i, r := range results {
+		want := CooldownPolicy(i % int(CooldownPolicyLen))
+		if r.Seconds != durations[i/int(CooldownPolicyLen)] || r.Policy != want || r.Iterations != 10 || r.DPS <= 0 {
+			t.Errorf("result %d: expected %s at %ds, got %+v", i, want, durations[i/int(CooldownPolicyLen)], r)
+		}
+	}
+}
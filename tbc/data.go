@@ -0,0 +1,504 @@
+package tbc
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DataVersion is the version of the data file format this build reads.
+const DataVersion = 1
+
+//go:embed data/*.json
+var embeddedData embed.FS
+
+// embeddedFiles is the load order of the built in data, sets need their items to be loaded first.
+var embeddedFiles = []string{"enchants.json", "gems.json", "items.json", "sets.json"}
+
+func init() {
+	for _, name := range embeddedFiles {
+		data, err := embeddedData.ReadFile(path.Join("data", name))
+		if err == nil {
+			err = LoadData(name, data)
+		}
+		if err != nil {
+			panic(err) // the built in data is checked by TestEmbeddedData, so this only happens while editing it.
+		}
+	}
+}
+
+// dataFile is the format of the files in tbc/data and of extra files loaded with LoadDataFile.
+// A file can have any of the sections.
+type dataFile struct {
+	Version int
+
+	// Override allows entries to replace already loaded entries with the same ID (or set name).
+	// Without it, reusing an ID is reported as a duplicate.
+	Override bool `json:",omitempty"`
+
+	Items    []itemRecord    `json:",omitempty"`
+	Gems     []gemRecord     `json:",omitempty"`
+	Enchants []enchantRecord `json:",omitempty"`
+	Sets     []setRecord     `json:",omitempty"`
+}
+
+// statMap is stats by the short names accepted by ParseStat, for example {"sp": 23, "crit": 14}.
+type statMap map[string]float64
+
+type itemRecord struct {
+	ID         int32
+	Name       string
+	Slot       string
+	SubSlot    string `json:",omitempty"`
+	Phase      byte
+	Quality    string
+	Armor      string `json:",omitempty"`
+	ItemLevel  int
+	Binding    string   `json:",omitempty"`
+	Profession string   `json:",omitempty"`
+	SourceZone string   `json:",omitempty"`
+	SourceDrop string   `json:",omitempty"`
+	Stats      statMap  `json:",omitempty"`
+	GemSlots   []string `json:",omitempty"`
+
+	SocketBonus statMap `json:",omitempty"`
+	Effect      string  `json:",omitempty"` // key of an on-use or proc effect in itemEffects.
+	Unique      bool    `json:",omitempty"`
+}
+
+type gemRecord struct {
+	ID       int32
+	Name     string
+	Color    string
+	Phase    byte
+	Quality  string
+	Stats    statMap     `json:",omitempty"`
+	Unique   bool        `json:",omitempty"`
+	Requires *metaRecord `json:",omitempty"`
+	Effect   string      `json:",omitempty"`
+}
+
+type metaRecord struct {
+	Red    int    `json:",omitempty"`
+	Yellow int    `json:",omitempty"`
+	Blue   int    `json:",omitempty"`
+	More   string `json:",omitempty"`
+	Than   string `json:",omitempty"`
+}
+
+type enchantRecord struct {
+	ID       int32
+	EffectID int32 `json:",omitempty"`
+	Name     string
+	Slot     string
+	Bonus    statMap
+}
+
+type setRecord struct {
+	Name    string
+	Items   []setPiece     // item IDs, one entry per piece of the set.
+	Bonuses map[int]string // number of items to the key of the effect in itemEffects.
+}
+
+// setPiece is the item IDs of one piece of a set. It is written as a single ID,
+// or a list of IDs when the same piece has alternate IDs.
+type setPiece []int32
+
+func (sp *setPiece) UnmarshalJSON(data []byte) error {
+	var id int32
+	if err := json.Unmarshal(data, &id); err == nil {
+		*sp = setPiece{id}
+		return nil
+	}
+	ids := []int32{}
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return fmt.Errorf("set piece must be an item ID or a list of item IDs: %s", data)
+	}
+	*sp = ids
+	return nil
+}
+
+var slotKeys = map[string]byte{
+	"head":     EquipHead,
+	"neck":     EquipNeck,
+	"shoulder": EquipShoulder,
+	"back":     EquipBack,
+	"chest":    EquipChest,
+	"wrist":    EquipWrist,
+	"hands":    EquipHands,
+	"waist":    EquipWaist,
+	"legs":     EquipLegs,
+	"feet":     EquipFeet,
+	"finger":   EquipFinger,
+	"trinket":  EquipTrinket,
+	"weapon":   EquipWeapon,
+	"offhand":  EquipOffhand,
+	"totem":    EquipTotem,
+}
+
+var subslotKeys = map[string]byte{
+	"":        SubslotUnknown,
+	"shield":  SubslotShield,
+	"twohand": SubslotTwoHand,
+}
+
+var qualityKeys = map[string]ItemQuality{
+	"junk":      ItemQualityJunk,
+	"uncommon":  ItemQualityUncommon,
+	"rare":      ItemQualityRare,
+	"epic":      ItemQualityEpic,
+	"legendary": ItemQualityLegendary,
+}
+
+// statKeys is the name of each stat in the data files, indexed by stat.
+var statKeys = []string{"int", "stm", "crit", "hit", "sp", "haste", "mp5", "mana", "pen", "spirit"}
+
+// DataError lists every problem found loading a data file.
+type DataError struct {
+	File     string
+	Problems []string
+}
+
+func (de *DataError) Error() string {
+	return fmt.Sprintf("%s: %d problem(s) loading data:\n\t%s", de.File, len(de.Problems), strings.Join(de.Problems, "\n\t"))
+}
+
+func (de *DataError) addf(format string, args ...interface{}) {
+	de.Problems = append(de.Problems, fmt.Sprintf(format, args...))
+}
+
+// LoadDataFile loads extra items, gems, enchants and sets from a data file on top of the built in data.
+// It must be called before any simulations are started.
+func LoadDataFile(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return LoadData(filename, data)
+}
+
+// LoadData loads a data file already read into memory, name is only used for errors.
+// Nothing is loaded if any entry in the file has a problem.
+func LoadData(name string, data []byte) error {
+	de := &DataError{File: name}
+	f := dataFile{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		de.addf("invalid JSON: %s", err)
+		return de
+	}
+	if f.Version != DataVersion {
+		de.addf("unsupported version %d, expected %d", f.Version, DataVersion)
+		return de
+	}
+
+	enchants := make([]Enchant, 0, len(f.Enchants))
+	enchantIDs, enchantNames := map[int32]bool{}, map[string]bool{}
+	for i, r := range f.Enchants {
+		where := fmt.Sprintf("enchant %d (%d %q)", i, r.ID, r.Name)
+		en := Enchant{ID: r.ID, EffectID: r.EffectID, Name: r.Name, Bonus: r.Bonus.stats(de, where)}
+		en.Slot = slotKey(de, where, r.Slot)
+		if old, ok := EnchantByID[r.ID]; ok {
+			dupCheck(de, where, f.Override, old.Name)
+		} else if _, ok := EnchantLookup[r.Name]; ok {
+			de.addf("%s: name is already used by enchant %d", where, EnchantLookup[r.Name].ID)
+		}
+		checkEntry(de, where, r.ID, r.Name, enchantIDs, enchantNames)
+		enchants = append(enchants, en)
+	}
+
+	gems := make([]Gem, 0, len(f.Gems))
+	gemIDs, gemNames := map[int32]bool{}, map[string]bool{}
+	for i, r := range f.Gems {
+		where := fmt.Sprintf("gem %d (%d %q)", i, r.ID, r.Name)
+		g := Gem{ID: r.ID, Name: r.Name, Phase: r.Phase, Unique: r.Unique, Stats: r.Stats.stats(de, where)}
+		g.Color = colorKey(de, where, r.Color)
+		g.Quality = quality(de, where, r.Quality)
+		g.Activate = effectKey(de, where, r.Effect).Activate
+		if r.Requires != nil {
+			if g.Color != GemColorMeta {
+				de.addf("%s: only meta gems can have requirements", where)
+			}
+			g.Requires = MetaRequirement{Red: r.Requires.Red, Yellow: r.Requires.Yellow, Blue: r.Requires.Blue}
+			if r.Requires.More != "" || r.Requires.Than != "" {
+				g.Requires.More = colorKey(de, where, r.Requires.More)
+				g.Requires.Than = colorKey(de, where, r.Requires.Than)
+			}
+		}
+		if old, ok := GemsByID[r.ID]; ok {
+			dupCheck(de, where, f.Override, old.Name)
+		} else if _, ok := GemLookup[r.Name]; ok {
+			de.addf("%s: name is already used by gem %d", where, GemLookup[r.Name].ID)
+		}
+		checkEntry(de, where, r.ID, r.Name, gemIDs, gemNames)
+		gems = append(gems, g)
+	}
+
+	items := make([]Item, 0, len(f.Items))
+	itemIDs, itemNames := map[int32]bool{}, map[string]bool{}
+	for i, r := range f.Items {
+		where := fmt.Sprintf("item %d (%d %q)", i, r.ID, r.Name)
+		item := Item{
+			ID:          r.ID,
+			Name:        r.Name,
+			Phase:       r.Phase,
+			Unique:      r.Unique,
+			SourceZone:  r.SourceZone,
+			SourceDrop:  r.SourceDrop,
+			Stats:       r.Stats.stats(de, where),
+			SocketBonus: r.SocketBonus.stats(de, where),
+		}
+		item.Slot = slotKey(de, where, r.Slot)
+		if ss, ok := subslotKeys[r.SubSlot]; ok {
+			item.SubSlot = ss
+		} else {
+			de.addf("%s: unknown sub slot %q", where, r.SubSlot)
+		}
+		item.Quality = quality(de, where, r.Quality)
+		item.ItemLevel = r.ItemLevel
+		item.ArmorType = armorKey(de, where, r.Armor)
+		if item.ArmorType != ArmorTypeNone && !isArmorSlot(item.Slot) {
+			de.addf("%s: only armor slots can have an armor type", where)
+		}
+		item.Binding = bindingKey(de, where, r.Binding)
+		item.Profession = professionKey(de, where, r.Profession)
+		for _, c := range r.GemSlots {
+			item.GemSlots = append(item.GemSlots, colorKey(de, where, c))
+		}
+		effect := effectKey(de, where, r.Effect)
+		item.Activate, item.ActivateCD, item.CoolID = effect.Activate, effect.CD, effect.CoolID
+
+		if old, ok := ItemsByID[r.ID]; ok {
+			dupCheck(de, where, f.Override, old.Name)
+		} else if _, ok := ItemsByName[r.Name]; ok {
+			de.addf("%s: name is already used by item %d", where, ItemsByName[r.Name].ID)
+		}
+		checkEntry(de, where, r.ID, r.Name, itemIDs, itemNames)
+		items = append(items, item)
+	}
+
+	newSets := make([]ItemSet, 0, len(f.Sets))
+	setNames := map[string]bool{}
+	for i, r := range f.Sets {
+		where := fmt.Sprintf("set %d (%q)", i, r.Name)
+		set := ItemSet{Name: r.Name, Items: map[int32]int{}, Bonuses: map[int]ItemActivation{}}
+		if r.Name == "" {
+			de.addf("%s: missing name", where)
+		}
+		if setNames[r.Name] {
+			de.addf("%s: duplicate set in this file", where)
+		}
+		setNames[r.Name] = true
+		for _, s := range sets {
+			if s.Name == r.Name && !f.Override {
+				de.addf("%s: set is already loaded, set Override to replace it", where)
+			}
+		}
+		for piece, ids := range r.Items {
+			if len(ids) == 0 {
+				de.addf("%s: piece %d has no item IDs", where, piece)
+			}
+			for _, id := range ids {
+				if _, ok := ItemsByID[id]; !ok && !itemIDs[id] {
+					de.addf("%s: unknown item %d", where, id)
+				}
+				if _, ok := set.Items[id]; ok {
+					de.addf("%s: item %d is in the set more than once", where, id)
+				}
+				set.Items[id] = piece
+			}
+		}
+		if len(r.Bonuses) == 0 {
+			de.addf("%s: no bonuses", where)
+		}
+		for count, key := range r.Bonuses {
+			if count < 1 || count > len(r.Items) {
+				de.addf("%s: bonus for %d pieces but the set has %d items", where, count, len(r.Items))
+			}
+			if key == "" {
+				de.addf("%s: missing effect for %d pieces", where, count)
+			}
+			set.Bonuses[count] = effectKey(de, where, key).Activate
+		}
+		newSets = append(newSets, set)
+	}
+
+	if len(de.Problems) > 0 {
+		return de
+	}
+
+	for _, en := range enchants {
+		if old, ok := EnchantByID[en.ID]; ok {
+			delete(EnchantLookup, old.Name)
+			delete(EnchantByEffectID, old.EffectID)
+			for i := range Enchants {
+				if Enchants[i].ID == en.ID {
+					Enchants[i] = en
+				}
+			}
+		} else {
+			Enchants = append(Enchants, en)
+		}
+		EnchantLookup[en.Name] = en
+		EnchantByID[en.ID] = en
+		if en.EffectID > 0 {
+			EnchantByEffectID[en.EffectID] = en
+		}
+	}
+	for _, g := range gems {
+		if old, ok := GemsByID[g.ID]; ok {
+			delete(GemLookup, old.Name)
+			for i := range Gems {
+				if Gems[i].ID == g.ID {
+					Gems[i] = g
+				}
+			}
+		} else {
+			Gems = append(Gems, g)
+		}
+		GemLookup[g.Name] = g
+		GemsByID[g.ID] = g
+	}
+	for _, item := range items {
+		if old, ok := ItemsByID[item.ID]; ok {
+			delete(ItemsByName, old.Name)
+		}
+		ItemsByName[item.Name] = item
+		ItemsByID[item.ID] = item
+	}
+	for _, set := range newSets {
+		replaced := false
+		for i := range sets {
+			if sets[i].Name == set.Name {
+				sets[i] = set
+				replaced = true
+			}
+		}
+		if !replaced {
+			sets = append(sets, set)
+		}
+	}
+	cache.dataLoaded(data)
+	return nil
+}
+
+// checkEntry reports a missing ID or name, and IDs or names used twice in the same file.
+func checkEntry(de *DataError, where string, id int32, name string, ids map[int32]bool, names map[string]bool) {
+	if id <= 0 {
+		de.addf("%s: missing ID", where)
+	}
+	if name == "" {
+		de.addf("%s: missing name", where)
+	}
+	if ids[id] {
+		de.addf("%s: duplicate ID in this file", where)
+	}
+	if names[name] {
+		de.addf("%s: duplicate name in this file", where)
+	}
+	ids[id] = true
+	names[name] = true
+}
+
+// dupCheck reports an entry reusing an already loaded ID, unless the file overrides existing entries.
+func dupCheck(de *DataError, where string, override bool, oldName string) {
+	if !override {
+		de.addf("%s: duplicate ID, already loaded as %q. Set Override to replace it", where, oldName)
+	}
+}
+
+func (sm statMap) stats(de *DataError, where string) Stats {
+	if len(sm) == 0 {
+		return Stats{}
+	}
+	s := Stats{StatLen: 0}
+	keys := make([]string, 0, len(sm))
+	for k := range sm {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys) // keep the problems in a stable order
+	for _, k := range keys {
+		stat, ok := ParseStat(k)
+		if !ok {
+			de.addf("%s: unknown stat %q", where, k)
+			continue
+		}
+		s[stat] += sm[k]
+	}
+	return s
+}
+
+func slotKey(de *DataError, where string, key string) byte {
+	slot, ok := slotKeys[key]
+	if !ok {
+		de.addf("%s: unknown slot %q", where, key)
+	}
+	return slot
+}
+
+func colorKey(de *DataError, where string, key string) GemColor {
+	for c := GemColorMeta; c <= GemColorPrismatic; c++ {
+		if c.String() == key {
+			return c
+		}
+	}
+	de.addf("%s: unknown gem colour %q", where, key)
+	return GemColorUnknown
+}
+
+func quality(de *DataError, where string, key string) ItemQuality {
+	q, ok := qualityKeys[key]
+	if !ok {
+		de.addf("%s: unknown quality %q", where, key)
+	}
+	return q
+}
+
+func armorKey(de *DataError, where string, key string) ArmorType {
+	if key == "" {
+		return ArmorTypeNone
+	}
+	a, ok := ParseArmorType(key)
+	if !ok {
+		de.addf("%s: unknown armor type %q", where, key)
+	}
+	return a
+}
+
+func bindingKey(de *DataError, where string, key string) Binding {
+	for b := BindingNone; b < BindingLen; b++ {
+		if b.String() == key {
+			return b
+		}
+	}
+	de.addf("%s: unknown binding %q", where, key)
+	return BindingNone
+}
+
+func professionKey(de *DataError, where string, key string) Profession {
+	if key == "" {
+		return ProfessionNone
+	}
+	p, ok := ParseProfession(key)
+	if !ok {
+		de.addf("%s: unknown profession %q", where, key)
+	}
+	return p
+}
+
+func effectKey(de *DataError, where string, key string) itemEffect {
+	if key == "" {
+		return itemEffect{}
+	}
+	effect, ok := itemEffects[key]
+	if !ok {
+		de.addf("%s: unknown effect %q", where, key)
+	}
+	return effect
+}
@@ -0,0 +1,56 @@
+package tbc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedData(t *testing.T) {
+	if len(ItemsByID) == 0 || len(Gems) == 0 || len(Enchants) == 0 || len(sets) == 0 {
+		t.Fatalf("built in data missing: %d items, %d gems, %d enchants, %d sets", len(ItemsByID), len(Gems), len(Enchants), len(sets))
+	}
+	if len(ItemsByName) != len(ItemsByID) {
+		t.Errorf("%d item names for %d item IDs", len(ItemsByName), len(ItemsByID))
+	}
+	for _, item := range ItemsByID {
+		if item.Activate != nil && item.ActivateCD == 0 {
+			t.Errorf("%s has an effect without a cooldown", item.Name)
+		}
+	}
+}
+
+func TestLoadDataErrors(t *testing.T) {
+	items := len(ItemsByID)
+	err := LoadData("test.json", []byte(`{"Version": 1, "Items": [
+		{"ID": 24266, "Name": "Spellstrike Hood", "Slot": "head", "Quality": "epic"},
+		{"ID": 900001, "Name": "Spellstrike Hood", "Slot": "head", "Quality": "epic"},
+		{"ID": 900002, "Name": "Bad Item", "Slot": "hat", "Quality": "epic", "Stats": {"luck": 5}, "Effect": "nope"},
+		{"ID": 900003, "Name": "Good Item", "Slot": "head", "Quality": "epic"},
+		{"ID": 900004, "Name": "Bad Ring", "Slot": "finger", "Quality": "epic", "Armor": "mail", "Binding": "never"}
+	], "Sets": [
+		{"Name": "Bad Set", "Items": [[900003, 24266], 1, 24266], "Bonuses": {"2": "spellstrike-2pc"}}
+	]}`))
+	de, ok := err.(*DataError)
+	if !ok {
+		t.Fatalf("expected a DataError, got %v", err)
+	}
+	for _, want := range []string{"duplicate ID", "already used by item 24266", `unknown slot "hat"`, `unknown stat "luck"`, `unknown effect "nope"`, "only armor slots", `unknown binding "never"`, "unknown item 1", "item 24266 is in the set more than once"} {
+		found := false
+		for _, p := range de.Problems {
+			found = found || strings.Contains(p, want)
+		}
+		if !found {
+			t.Errorf("expected a problem containing %q in:\n%s", want, de)
+		}
+	}
+	if len(ItemsByID) != items {
+		t.Errorf("items were loaded from a file with problems")
+	}
+
+	if err := LoadData("test.json", []byte(`{"Version": 1, "Items": [{"ID": 1, "Extra": true}]}`)); err == nil {
+		t.Errorf("unknown fields should be an error")
+	}
+	if err := LoadData("test.json", []byte(`{"Version": 2}`)); err == nil {
+		t.Errorf("unknown version should be an error")
+	}
+}
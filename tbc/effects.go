@@ -0,0 +1,94 @@
+package tbc
+
+// itemEffect is an on-use or proc effect that items, gems and set bonuses in the data files refer to by name.
+type itemEffect struct {
+	Activate ItemActivation
+	CD       int   // cooldown on activation, -1 means perm effect. Not used for gems and set bonuses.
+	CoolID   int32 // ID used for cooldown
+}
+
+// itemEffects are the effects the data files can use, by the key used in the data files.
+var itemEffects = map[string]itemEffect{
+	// Meta gems
+	"chaotic-skyfire-diamond":       {Activate: ActivateCSD},
+	"ember-skyfire-diamond":         {Activate: ActivateESD},
+	"mystical-skyfire-diamond":      {Activate: ActivateMSD},
+	"insightful-earthstorm-diamond": {Activate: ActivateIED},
+
+	// Trinkets
+	"quagmirrans-eye":                 {Activate: ActivateQuagsEye, CD: -1},
+	"icon-of-the-silver-crescent":     {Activate: createSpellDmgActivate(MagicIDBlessingSilverCrescent, 155, 20), CD: 120, CoolID: MagicIDISCTrink},
+	"natural-alignment-crystal":       {Activate: ActivateNAC, CD: 300, CoolID: MagicIDNACTrink},
+	"restrained-essence-of-sapphiron": {Activate: createSpellDmgActivate(MagicIDSpellPower, 130, 20), CD: 120, CoolID: MagicIDEssSappTrink},
+	"scryers-bloodgem":                {Activate: createSpellDmgActivate(MagicIDSpellPower, 150, 15), CD: 90, CoolID: MagicIDScryerTrink},
+	"living-ruby-serpent":             {Activate: createSpellDmgActivate(MagicIDRubySerpent, 150, 20), CD: 300, CoolID: MagicIDRubySerpentTrink},
+	"xiris-gift":                      {Activate: createSpellDmgActivate(MagicIDSpellPower, 150, 15), CD: 90, CoolID: MagicIDXiriTrink},
+	"shiffars-nexus-horn":             {Activate: ActivateNexusHorn, CD: -1},
+	"darkmoon-card-crusade":           {Activate: ActivateDCC, CD: -1},
+	"lightning-capacitor":             {Activate: ActivateTLC, CD: -1},
+	"eye-of-magtheridon":              {Activate: ActivateEyeOfMag, CD: -1},
+	"sextant-of-unstable-currents":    {Activate: ActivateSextant, CD: -1},
+	"shifting-naaru-sliver":           {Activate: createSpellDmgActivate(MagicIDShiftingNaaru, 320, 15), CD: 90, CoolID: MagicIDShiftingNaaruTrink},
+	"skull-of-guldan":                 {Activate: createHasteActivate(MagicIDSkullGuldan, 175, 20), CD: 120, CoolID: MagicIDSkullGuldanTrink},
+	"hex-shrunken-head":               {Activate: createSpellDmgActivate(MagicIDHexShunkHead, 211, 20), CD: 120, CoolID: MagicIDHexTrink},
+	"essence-of-the-martyr":           {Activate: createSpellDmgActivate(MagicIDSpellPower, 99, 20), CD: 120, CoolID: MagicIDEssMartyrTrink},
+
+	// Other slots
+	"eye-of-the-night": {Activate: func(sim *Simulation) Aura {
+		if sim.Options.Buffs.EyeOfNight {
+			return Aura{} // if we already have buff from party member, no need to activate this
+		}
+		activate := createSpellDmgActivate(MagicIDEyeOfTheNight, 34, 30*60)
+		return activate(sim)
+	}, CD: 3600, CoolID: MagicIDEyeOfTheNightTrink},
+	"chain-of-the-twilight-owl": {Activate: ActivateChainTO, CD: 3600, CoolID: MagicIDChainTOTrink},
+	"skycall-totem":             {Activate: ActivateSkycall, CD: -1}, // -1 will trigger an activation only once
+
+	// Set bonuses
+	"netherstrike-3pc": {Activate: func(sim *Simulation) Aura {
+		sim.Buffs[StatSpellDmg] += 23
+		return Aura{ID: MagicIDNetherstrike, Expires: 0}
+	}},
+	"twin-stars-2pc": {Activate: func(sim *Simulation) Aura {
+		sim.Buffs[StatSpellDmg] += 15
+		return Aura{ID: MagicIDTwinStars, Expires: 0}
+	}},
+	"tidefury-2pc": {Activate: func(sim *Simulation) Aura {
+		// Chain Lightning loses 17% of its damage per jump instead of 30%, which only matters with more than one target.
+		sim.clJump = 0.83
+		return Aura{ID: MagicIDTidefury, Expires: 0}
+	}},
+	"tidefury-4pc": {Activate: func(sim *Simulation) Aura {
+		if sim.Options.Buffs.WaterShield {
+			sim.Buffs[StatMP5] += 3
+		}
+		return Aura{ID: MagicIDTidefury, Expires: 0}
+	}},
+	"spellstrike-2pc": {Activate: ActivateSpellstrike},
+	"mana-etched-2pc": {Activate: func(sim *Simulation) Aura {
+		sim.Buffs[StatSpellHit] += 35
+		return Aura{ID: MagicIDManaEtchedHit, Expires: 0}
+	}},
+	"mana-etched-4pc": {Activate: ActivateManaEtched},
+	"cyclone-2pc": {Activate: func(sim *Simulation) Aura {
+		if !sim.Options.Totems.Cyclone2PC && sim.Options.Totems.WrathOfAir {
+			sim.Buffs[StatSpellDmg] += 20 // only activate if we don't already have it from party/
+		}
+		return Aura{ID: MagicIDCyclone2pc, Expires: 0}
+	}},
+	"cyclone-4pc": {Activate: ActivateCycloneManaReduce},
+	"windhawk-3pc": {Activate: func(sim *Simulation) Aura {
+		if sim.Options.Buffs.WaterShield {
+			sim.Buffs[StatMP5] += 8
+		}
+		return Aura{ID: MagicIDWindhawk, Expires: 0}
+	}},
+	"cataclysm-4pc": {Activate: ActivateCataclysmLBDiscount},
+	"skyshatter-2pc": {Activate: func(sim *Simulation) Aura {
+		sim.Buffs[StatMP5] += 15
+		sim.Buffs[StatSpellCrit] += 35
+		sim.Buffs[StatSpellDmg] += 45
+		return Aura{ID: MagicIDSkyshatter2pc, Expires: 0}
+	}},
+	"skyshatter-4pc": {Activate: ActivateSkyshatterImpLB},
+}
@@ -0,0 +1,443 @@
+package tbc
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GearOptimizerOptions configures which items OptimalGear may use and how hard it searches.
+type GearOptimizerOptions struct {
+	ItemFilter
+
+	// Stat weights used to rank items, normalized to spell power.
+	// If not set, they are calculated with StatWeights before optimizing.
+	Weights []float64
+
+	PerSlot   int // number of items kept for each slot after ranking by weights. Defaults to 5.
+	Finalists int // number of full sets to confirm by simulation. Defaults to 5.
+}
+
+// GearCandidate is a single full set of gear.
+type GearCandidate struct {
+	Equip Equipment
+	Score float64 // stat weight score, including gems and set bonuses that give stats.
+	Sets  []string
+	DPS   float64
+	Stdev float64
+
+	Iterations int // iterations simulated, can vary with a precision target.
+}
+
+// GearOptimizerResult is the best found set of gear.
+type GearOptimizerResult struct {
+	Equip   Equipment // best gemmed set of gear
+	DPS     float64
+	Stdev   float64
+	BaseDPS float64 // DPS of the equipment as it was passed in
+	Delta   float64 // DPS - BaseDPS
+
+	Candidates []GearCandidate // every finalist simulated, best first. Everything after the first is a runner-up.
+}
+
+// OptimalGear searches every item allowed by the filter for the best full set of gear.
+//
+// Items are ranked by stat weights plus the value of any on-use or proc effect,
+// which is measured by simulating the item in place of the current gear.
+// Set bonuses, unique items in the ring and trinket slots, and two handed weapons are
+// handled while combining slots. The best combinations are gemmed with the gem optimizer and
+// then confirmed by simulation. Enchants are kept from the equipment passed in when they fit.
+func OptimalGear(opts Options, equip Equipment, seconds int, numSims int, gopts GearOptimizerOptions) GearOptimizerResult {
+	opts.UseAI = true
+	if gopts.PerSlot <= 0 {
+		gopts.PerSlot = 5
+	}
+	if gopts.Finalists <= 0 {
+		gopts.Finalists = 5
+	}
+	if len(equip) <= int(EquipTotem) {
+		full := make(Equipment, EquipTotem+1)
+		copy(full, equip)
+		equip = full
+	}
+	weights := gopts.Weights
+	if len(weights) == 0 {
+		weights = StatWeights(opts, equip, seconds, numSims)
+	}
+
+	pool := QueryItems(gopts.ItemFilter)
+
+	effects := effectScores(opts, equip, pool, seconds, numSims)
+	gemScore := newGemScorer(gopts, weights)
+	scores := map[int32]float64{}
+	for _, item := range pool {
+		scores[item.ID] = statScore(item.Stats, weights) + gemScore.item(item) + effects[item.ID]
+	}
+
+	groups := gearGroups(pool, scores, gopts.PerSlot)
+	candidates := searchGear(opts, groups, weights, gopts.Finalists)
+
+	opts.Job.Phase("simulating finalists", phaseTotal(opts, len(candidates)+1, numSims))
+	gemOpts := GemOptimizerOptions{MaxPhase: gopts.MaxPhase, MinQuality: gopts.MinQuality, Confirm: 1}
+	base := newSimRunner(CalculateTotalStats(opts, equip), equip, opts, seconds)
+	baseDPS, _ := meanStdev(base.first(base.untilPrecise(numSims, nil)))
+	output := GearOptimizerResult{Equip: equip, BaseDPS: baseDPS, DPS: baseDPS}
+	for _, c := range candidates {
+		for i := range c.Equip {
+			if equip[i].Enchant.Fits(c.Equip[i]) {
+				c.Equip[i].Enchant = equip[i].Enchant
+			}
+		}
+		if gemmed := searchGems(c.Equip, gemOpts, weights); len(gemmed) > 0 {
+			c.Equip = gemmed[0].Equip
+		}
+		dps, _ := runPairedDPS(base, CalculateTotalStats(opts, c.Equip), c.Equip, opts, seconds, numSims)
+		c.DPS, c.Stdev = meanStdev(dps)
+		c.Iterations = len(dps)
+		output.Candidates = append(output.Candidates, c)
+	}
+	sort.SliceStable(output.Candidates, func(i, j int) bool {
+		return output.Candidates[i].DPS > output.Candidates[j].DPS
+	})
+	if len(output.Candidates) > 0 {
+		output.Equip = output.Candidates[0].Equip
+		output.DPS = output.Candidates[0].DPS
+		output.Stdev = output.Candidates[0].Stdev
+	}
+	output.Delta = output.DPS - output.BaseDPS
+	return output
+}
+
+// gemScorer estimates how much the sockets of an item are worth, without worrying about meta requirements.
+type gemScorer struct {
+	best     float64              // best non-unique gem of any colour
+	byColor  map[GemColor]float64 // best non-unique gem that matches a socket colour
+	bestMeta float64
+	weights  []float64
+}
+
+func newGemScorer(gopts GearOptimizerOptions, weights []float64) gemScorer {
+	gs := gemScorer{byColor: map[GemColor]float64{}, weights: weights}
+	for _, g := range Gems {
+		if g.Unique || (gopts.MaxPhase != 0 && g.Phase > gopts.MaxPhase) || g.Quality < gopts.MinQuality {
+			continue
+		}
+		score := statScore(g.Stats, weights)
+		if g.Color == GemColorMeta {
+			if score > gs.bestMeta {
+				gs.bestMeta = score
+			}
+			continue
+		}
+		if score > gs.best {
+			gs.best = score
+		}
+		for _, socket := range []GemColor{GemColorRed, GemColorYellow, GemColorBlue} {
+			if g.Color.Intersects(socket) && score > gs.byColor[socket] {
+				gs.byColor[socket] = score
+			}
+		}
+	}
+	return gs
+}
+
+// item is the value of the best way to gem the item, either matching the socket bonus or ignoring it.
+func (gs gemScorer) item(item Item) float64 {
+	if len(item.GemSlots) == 0 {
+		return 0
+	}
+	matched := statScore(item.SocketBonus, gs.weights)
+	unmatched := 0.0
+	for _, color := range item.GemSlots {
+		if color == GemColorMeta {
+			matched += gs.bestMeta
+			unmatched += gs.bestMeta
+			continue
+		}
+		matched += gs.byColor[color]
+		unmatched += gs.best
+	}
+	if matched > unmatched {
+		return matched
+	}
+	return unmatched
+}
+
+// effectScores measures the on-use and proc effects of items, in spell power.
+// Each item is simulated in place of the current gear with and without its effect.
+func effectScores(opts Options, equip Equipment, pool []Item, seconds int, numSims int) map[int32]float64 {
+	type variant struct {
+		id    int32
+		opts  Options
+		equip Equipment
+	}
+
+	// Spell power to convert DPS into score.
+	spOpts := opts
+	spOpts.Buffs.Custom = Stats{StatLen: 0}
+	copy(spOpts.Buffs.Custom, opts.Buffs.Custom)
+	spOpts.Buffs.Custom[StatSpellDmg] += 50
+	variants := []variant{{id: -1, opts: spOpts, equip: equip}}
+	for _, item := range pool {
+		if item.Activate == nil {
+			continue
+		}
+		slot := item.Slot
+		switch slot {
+		case EquipFinger:
+			slot = EquipFinger2
+		case EquipTrinket:
+			slot = EquipTrinket2
+		}
+		without := item
+		without.Activate = nil
+		with, withOut := equip.Clone(), equip.Clone()
+		with[slot], withOut[slot] = item, without
+		variants = append(variants, variant{id: item.ID, opts: opts, equip: with}, variant{id: -item.ID - 1, opts: opts, equip: withOut})
+	}
+	opts.Job.Phase("item effects", phaseTotal(opts, len(variants)+1, numSims))
+
+	results := make([]float64, len(variants))
+	parallel(len(variants), func(i int) {
+		v := variants[i]
+		results[i], _ = meanStdev(runDPS(CalculateTotalStats(v.opts, v.equip), v.equip, v.opts, seconds, numSims))
+	})
+	dps := map[int32]float64{}
+	for i, v := range variants {
+		dps[v.id] = results[i]
+	}
+	base, _ := meanStdev(runDPS(CalculateTotalStats(opts, equip), equip, opts, seconds, numSims))
+	perSP := (dps[-1] - base) / 50
+	scores := map[int32]float64{}
+	if perSP <= 0 {
+		return scores // not enough iterations to tell anything apart.
+	}
+	for _, item := range pool {
+		if item.Activate == nil {
+			continue
+		}
+		if v := (dps[item.ID] - dps[-item.ID-1]) / perSP; v > 0 {
+			scores[item.ID] = v
+		}
+	}
+	return scores
+}
+
+// gearOption is one choice for a group of slots, like a pair of rings.
+type gearOption struct {
+	slots []byte
+	items []Item
+	score float64
+}
+
+// gearGroups builds the choices for each group of slots that must be picked together.
+// Each slot keeps the top perSlot items by score plus every item that is part of a set.
+func gearGroups(pool []Item, scores map[int32]float64, perSlot int) [][]gearOption {
+	bySlot := map[byte][]Item{}
+	for _, item := range pool {
+		bySlot[item.Slot] = append(bySlot[item.Slot], item)
+	}
+	top := func(slot byte, n int) []Item {
+		items := bySlot[slot]
+		sort.SliceStable(items, func(i, j int) bool { return scores[items[i].ID] > scores[items[j].ID] })
+		kept := []Item{}
+		for i, item := range items {
+			if i < n || itemSet(item) >= 0 {
+				kept = append(kept, item)
+			}
+		}
+		return kept
+	}
+	single := func(slot byte, item Item) gearOption {
+		return gearOption{slots: []byte{slot}, items: []Item{item}, score: scores[item.ID]}
+	}
+
+	groups := [][]gearOption{}
+	for _, slot := range []byte{EquipHead, EquipNeck, EquipShoulder, EquipBack, EquipChest, EquipWrist, EquipHands, EquipWaist, EquipLegs, EquipFeet, EquipTotem} {
+		options := []gearOption{}
+		for _, item := range top(slot, perSlot) {
+			options = append(options, single(slot, item))
+		}
+		if len(options) > 0 {
+			groups = append(groups, options)
+		}
+	}
+
+	// Rings and trinkets can be worn twice unless they are unique-equipped.
+	pairs := func(slot, first, second byte) []gearOption {
+		items := top(slot, perSlot*2)
+		options := []gearOption{}
+		for i := range items {
+			for j := i; j < len(items); j++ {
+				if j == i && items[i].Unique {
+					continue
+				}
+				options = append(options, gearOption{
+					slots: []byte{first, second},
+					items: []Item{items[i], items[j]},
+					score: scores[items[i].ID] + scores[items[j].ID],
+				})
+			}
+		}
+		if len(options) == 0 && len(items) == 1 {
+			options = append(options, single(first, items[0]))
+		}
+		return options
+	}
+	for _, options := range [][]gearOption{pairs(EquipFinger, EquipFinger1, EquipFinger2), pairs(EquipTrinket, EquipTrinket1, EquipTrinket2)} {
+		if len(options) > 0 {
+			groups = append(groups, options)
+		}
+	}
+
+	// Either a two hander or a one hander with a shield or held in offhand item.
+	hands := []gearOption{}
+	offhands := top(EquipOffhand, perSlot)
+	for _, weapon := range top(EquipWeapon, perSlot) {
+		if weapon.SubSlot == SubslotTwoHand || len(offhands) == 0 {
+			hands = append(hands, single(EquipWeapon, weapon))
+			continue
+		}
+		for _, oh := range offhands {
+			hands = append(hands, gearOption{
+				slots: []byte{EquipWeapon, EquipOffhand},
+				items: []Item{weapon, oh},
+				score: scores[weapon.ID] + scores[oh.ID],
+			})
+		}
+	}
+	if len(hands) > 0 {
+		groups = append(groups, hands)
+	}
+	return groups
+}
+
+// itemSet returns the index into sets the item belongs to, or -1.
+func itemSet(item Item) int {
+	for i, set := range sets {
+		if _, ok := set.Items[item.ID]; ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// setBonusScores is the stat weight value of every set bonus, indexed by set then piece count.
+// Bonuses that are procs instead of stats are worth 0 here, the simulation will catch them.
+func setBonusScores(opts Options, weights []float64) []map[int]float64 {
+	scores := make([]map[int]float64, len(sets))
+	for i, set := range sets {
+		scores[i] = map[int]float64{}
+		for count, bonus := range set.Bonuses {
+			sim := NewSim(Stats{StatLen: 0}, Equipment{}, opts)
+			bonus(sim)
+			scores[i][count] = statScore(sim.Buffs, weights)
+		}
+	}
+	return scores
+}
+
+// gearPath is a linked list of chosen options, newest first.
+type gearPath struct {
+	option int // index into the group's options
+	prev   *gearPath
+}
+
+type gearEntry struct {
+	score float64
+	path  *gearPath
+}
+
+// searchGear picks an option from every group, tracking how many pieces of each set are used
+// so that set bonuses can be added once the whole set of gear is known.
+func searchGear(opts Options, groups [][]gearOption, weights []float64, finalists int) []GearCandidate {
+	bonusScores := setBonusScores(opts, weights)
+
+	// State is the number of pieces of each set, packed into a string so it can be a map key.
+	start := string(make([]byte, len(sets)))
+	states := map[string][]gearEntry{start: {{}}}
+	for _, group := range groups {
+		next := map[string][]gearEntry{}
+		for st, entries := range states {
+			for oi, option := range group {
+				counts := []byte(st)
+				for _, item := range option.items {
+					if si := itemSet(item); si >= 0 {
+						counts[si]++
+					}
+				}
+				ns := string(counts)
+				for _, e := range entries {
+					next[ns] = keepBestGear(next[ns], gearEntry{score: e.score + option.score, path: &gearPath{option: oi, prev: e.path}}, finalists)
+				}
+			}
+		}
+		states = next
+	}
+
+	type final struct {
+		entry gearEntry
+		sets  []string // active bonuses, used to make sure every combination of bonuses gets simulated.
+	}
+	finals := []final{}
+	for st, entries := range states {
+		bonus := 0.0
+		active := []string{}
+		for si, count := range []byte(st) {
+			for pieces, v := range bonusScores[si] {
+				if int(count) >= pieces {
+					bonus += v
+					active = append(active, sets[si].Name+" ("+strconv.Itoa(pieces)+"pc)")
+				}
+			}
+		}
+		sort.Strings(active)
+		for _, e := range entries {
+			finals = append(finals, final{entry: gearEntry{score: e.score + bonus, path: e.path}, sets: active})
+		}
+	}
+	sort.SliceStable(finals, func(i, j int) bool {
+		return finals[i].entry.score > finals[j].entry.score
+	})
+
+	// The top finalists, plus the best for each combination of set bonuses since proc bonuses aren't scored.
+	chosen := []final{}
+	seenSets := map[string]bool{}
+	for i, f := range finals {
+		key := strings.Join(f.sets, ",")
+		if i < finalists || (!seenSets[key] && len(chosen) < finalists*2) {
+			chosen = append(chosen, f)
+		}
+		seenSets[key] = true
+	}
+
+	candidates := make([]GearCandidate, 0, len(chosen))
+	for _, f := range chosen {
+		equip := make(Equipment, EquipTotem+1)
+		path := f.entry.path
+		for gi := len(groups) - 1; gi >= 0; gi-- {
+			option := groups[gi][path.option]
+			for i, slot := range option.slots {
+				equip[slot] = option.items[i]
+			}
+			path = path.prev
+		}
+		candidates = append(candidates, GearCandidate{Equip: equip, Score: f.entry.score, Sets: f.sets})
+	}
+	return candidates
+}
+
+// keepBestGear inserts e into entries, keeping only the top n by score.
+func keepBestGear(entries []gearEntry, e gearEntry, n int) []gearEntry {
+	i := sort.Search(len(entries), func(i int) bool { return entries[i].score < e.score })
+	if i >= n {
+		return entries
+	}
+	entries = append(entries, gearEntry{})
+	copy(entries[i+1:], entries[i:])
+	entries[i] = e
+	if len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
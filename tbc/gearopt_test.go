@@ -0,0 +1,31 @@
+package tbc
+
+import "testing"
+
+func TestGearGroupsSlots(t *testing.T) {
+	pool := []Item{}
+	scores := map[int32]float64{}
+	for _, item := range ItemsByID {
+		pool = append(pool, item)
+		scores[item.ID] = statScore(item.Stats, []float64{StatSpellDmg: 1})
+	}
+	doubled := false
+	for _, group := range gearGroups(pool, scores, 3) {
+		for _, option := range group {
+			if len(option.items) == 2 && option.items[0].ID == option.items[1].ID {
+				if option.items[0].Unique {
+					t.Fatalf("unique-equipped %s picked twice", option.items[0].Name)
+				}
+				doubled = true
+			}
+			for i, item := range option.items {
+				if item.SubSlot == SubslotTwoHand && len(option.items) > 1 {
+					t.Fatalf("two hander %s paired with %s", item.Name, option.items[1-i].Name)
+				}
+			}
+		}
+	}
+	if !doubled {
+		t.Fatalf("expected a ring that isn't unique to be offered twice")
+	}
+}
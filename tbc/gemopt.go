@@ -0,0 +1,327 @@
+package tbc
+
+import (
+	"sort"
+)
+
+// GemOptimizerOptions configures which gems OptimalGems may use and how hard it searches.
+type GemOptimizerOptions struct {
+	MaxPhase   byte        // only use gems available in this phase or earlier, 0 allows every phase.
+	MinQuality ItemQuality // only use gems of at least this quality.
+
+	// Stat weights used to prune the search, normalized to spell power.
+	// If not set, they are calculated with StatWeights before optimizing.
+	Weights []float64
+
+	Confirm int // number of top candidates to confirm by simulation. Defaults to 5.
+}
+
+// GemCandidate is a single way to gem the equipment.
+type GemCandidate struct {
+	Equip Equipment
+	Score float64 // stat weight score of the gems, meta effects are not scored.
+	DPS   float64
+	Stdev float64
+
+	Iterations int // iterations simulated, can vary with a precision target.
+}
+
+// GemOptimizerResult is the best found gemming of the equipment.
+type GemOptimizerResult struct {
+	Equip   Equipment // gemmed equipment with the highest simulated DPS
+	DPS     float64
+	BaseDPS float64 // DPS of the equipment as it was passed in
+	Delta   float64 // DPS - BaseDPS
+
+	Candidates []GemCandidate // every candidate simulated, best first
+}
+
+// OptimalGems searches every gem for every socket and returns the best gemmed equipment.
+//
+// Candidates are ranked by stat weights, taking socket bonuses, meta gem requirements and
+// unique-equipped gems into account. The top candidates (including the best candidate for every meta gem,
+// since meta effects can't be weighted) are then confirmed by simulation.
+func OptimalGems(opts Options, equip Equipment, seconds int, numSims int, gopts GemOptimizerOptions) GemOptimizerResult {
+	opts.UseAI = true
+	if gopts.Confirm <= 0 {
+		gopts.Confirm = 5
+	}
+	weights := gopts.Weights
+	if len(weights) == 0 {
+		weights = StatWeights(opts, equip, seconds, numSims)
+	}
+
+	candidates := searchGems(equip, gopts, weights)
+
+	opts.Job.Phase("confirming gems", phaseTotal(opts, len(candidates)+1, numSims))
+	base := newSimRunner(CalculateTotalStats(opts, equip), equip, opts, seconds)
+	baseDPS, _ := meanStdev(base.first(base.untilPrecise(numSims, nil)))
+	output := GemOptimizerResult{BaseDPS: baseDPS, Equip: equip, DPS: baseDPS}
+	for _, c := range candidates {
+		dps, _ := runPairedDPS(base, CalculateTotalStats(opts, c.Equip), c.Equip, opts, seconds, numSims)
+		c.DPS, c.Stdev = meanStdev(dps)
+		c.Iterations = len(dps)
+		output.Candidates = append(output.Candidates, c)
+	}
+	sort.SliceStable(output.Candidates, func(i, j int) bool {
+		return output.Candidates[i].DPS > output.Candidates[j].DPS
+	})
+	if len(output.Candidates) > 0 && output.Candidates[0].DPS > baseDPS {
+		output.Equip = output.Candidates[0].Equip
+		output.DPS = output.Candidates[0].DPS
+	}
+	output.Delta = output.DPS - output.BaseDPS
+	return output
+}
+
+// statScore is the weighted value of a set of stats.
+func statScore(s Stats, weights []float64) float64 {
+	score := 0.0
+	for k, v := range s {
+		if k < len(weights) {
+			score += v * weights[k]
+		}
+	}
+	return score
+}
+
+// gemAllowed checks a gem against the optimizer filters.
+func (gopts GemOptimizerOptions) gemAllowed(g Gem) bool {
+	if gopts.MaxPhase != 0 && g.Phase > gopts.MaxPhase {
+		return false
+	}
+	return g.Quality >= gopts.MinQuality
+}
+
+// socketRef points at one non-meta socket in the equipment.
+type socketRef struct {
+	item   int
+	socket int
+	last   bool // last non-meta socket of the item, time to check the socket bonus.
+}
+
+// gemState is the part of a partial solution that affects what can come next.
+type gemState struct {
+	counts  GemColorCounts
+	unique  uint32 // bitmask of unique gems used
+	matched bool   // every socket of the current item so far matches its colour.
+}
+
+// gemPath is a linked list of chosen gems, newest first.
+type gemPath struct {
+	gem  int // index into the candidate gems
+	prev *gemPath
+}
+
+type gemEntry struct {
+	score float64
+	path  *gemPath
+}
+
+// gemsPerState is how many partial solutions are kept for each state.
+const gemsPerState = 3
+
+// searchGems does a dynamic programming search over every non-meta socket,
+// returning the best scoring candidates.
+func searchGems(equip Equipment, gopts GemOptimizerOptions, weights []float64) []GemCandidate {
+	return searchItemGems(equip, -1, gopts, weights)
+}
+
+// searchItemGems is searchGems for only the item at index only, keeping the gems in every other item.
+// The gems already in the rest of the equipment count towards the meta requirement and unique gems.
+// An index of -1 searches every item.
+func searchItemGems(equip Equipment, only int, gopts GemOptimizerOptions, weights []float64) []GemCandidate {
+	// Any gem fits any socket so the only things that matter about a gem are its colour
+	// and if its unique. Keep the best gem for each colour and every unique gem.
+	bestByColor := map[GemColor]Gem{}
+	uniques := []Gem{}
+	metas := []Gem{}
+	for _, g := range Gems {
+		if !gopts.gemAllowed(g) {
+			continue
+		}
+		if g.Color == GemColorMeta {
+			metas = append(metas, g)
+			continue
+		}
+		if g.Unique {
+			uniques = append(uniques, g)
+			continue
+		}
+		if best, ok := bestByColor[g.Color]; !ok || statScore(g.Stats, weights) > statScore(best.Stats, weights) {
+			bestByColor[g.Color] = g
+		}
+	}
+	gems := []Gem{}
+	for color := GemColorRed; color <= GemColorPrismatic; color++ {
+		if g, ok := bestByColor[color]; ok {
+			gems = append(gems, g)
+		}
+	}
+	uniqueBit := map[int]uint32{}
+	for i, g := range uniques {
+		if i >= 32 {
+			break // more unique gems than fit in the mask, who knew.
+		}
+		uniqueBit[len(gems)] = 1 << uint(i)
+		gems = append(gems, g)
+	}
+	scores := make([]float64, len(gems))
+	for i, g := range gems {
+		scores[i] = statScore(g.Stats, weights)
+	}
+
+	sockets := []socketRef{}
+	metaItem, metaSocket := -1, -1
+	start := gemState{matched: true}
+	for i, item := range equip {
+		if only != -1 && i != only {
+			// Fixed gems, they only matter for the meta gem and unique gems.
+			for _, g := range item.Gems {
+				if g.Color == GemColorMeta {
+					metas = []Gem{g}
+					metaItem, metaSocket = i, -1
+					continue
+				}
+				start.counts.Add(g.Color)
+				for gi, bit := range uniqueBit {
+					if gems[gi].ID == g.ID {
+						start.unique |= bit
+					}
+				}
+			}
+			continue
+		}
+		for si, color := range item.GemSlots {
+			if color == GemColorMeta {
+				metaItem, metaSocket = i, si
+				continue
+			}
+			sockets = append(sockets, socketRef{item: i, socket: si})
+		}
+		if n := len(sockets); n > 0 && sockets[n-1].item == i {
+			sockets[n-1].last = true
+		}
+	}
+
+	states := map[gemState][]gemEntry{start: {{}}}
+	for _, sock := range sockets {
+		item := equip[sock.item]
+		next := map[gemState][]gemEntry{}
+		for st, entries := range states {
+			for gi, g := range gems {
+				bit := uniqueBit[gi]
+				if st.unique&bit != 0 {
+					continue
+				}
+				ns := st
+				ns.counts.Add(g.Color)
+				ns.unique |= bit
+				ns.matched = st.matched && g.Color.Intersects(item.GemSlots[sock.socket])
+				bonus := 0.0
+				if sock.last {
+					if ns.matched {
+						bonus = statScore(item.SocketBonus, weights)
+					}
+					ns.matched = true // next item starts fresh.
+				}
+				for _, e := range entries {
+					next[ns] = keepBest(next[ns], gemEntry{score: e.score + scores[gi] + bonus, path: &gemPath{gem: gi, prev: e.path}})
+				}
+			}
+		}
+		states = next
+	}
+
+	// Pick the meta gem for each final state, dropping any combination that doesn't activate the meta.
+	type final struct {
+		entry gemEntry
+		meta  int // index into metas, -1 for no meta gem.
+	}
+	finals := []final{}
+	for st, entries := range states {
+		for _, e := range entries {
+			if metaItem == -1 || len(metas) == 0 {
+				finals = append(finals, final{entry: e, meta: -1})
+				continue
+			}
+			for mi, m := range metas {
+				if !m.Requires.Met(st.counts) {
+					continue
+				}
+				finals = append(finals, final{entry: gemEntry{score: e.score + statScore(m.Stats, weights), path: e.path}, meta: mi})
+			}
+		}
+	}
+	if len(finals) == 0 {
+		// Nothing can activate the meta gem, still gem the rest of the gear.
+		for _, entries := range states {
+			for _, e := range entries {
+				finals = append(finals, final{entry: e, meta: -1})
+			}
+		}
+	}
+	sort.SliceStable(finals, func(i, j int) bool {
+		return finals[i].entry.score > finals[j].entry.score
+	})
+
+	// Always confirm the best option for each meta gem, then fill in with the next best.
+	chosen := []final{}
+	seenMeta := map[int]bool{}
+	for _, f := range finals {
+		if !seenMeta[f.meta] {
+			seenMeta[f.meta] = true
+			chosen = append(chosen, f)
+		}
+	}
+	for _, f := range finals {
+		if len(chosen) >= gopts.Confirm {
+			break
+		}
+		dupe := false
+		for _, c := range chosen {
+			if c.entry.path == f.entry.path && c.meta == f.meta {
+				dupe = true
+				break
+			}
+		}
+		if !dupe {
+			chosen = append(chosen, f)
+		}
+	}
+
+	candidates := make([]GemCandidate, 0, len(chosen))
+	for _, f := range chosen {
+		gemmed := equip.Clone()
+		for i := range gemmed {
+			if only == -1 || i == only {
+				gemmed[i].Gems = make([]Gem, len(gemmed[i].GemSlots))
+			}
+		}
+		path := f.entry.path
+		for i := len(sockets) - 1; i >= 0; i-- {
+			gemmed[sockets[i].item].Gems[sockets[i].socket] = gems[path.gem]
+			path = path.prev
+		}
+		if f.meta >= 0 && metaSocket >= 0 {
+			gemmed[metaItem].Gems[metaSocket] = metas[f.meta]
+		}
+		candidates = append(candidates, GemCandidate{Equip: gemmed, Score: f.entry.score})
+	}
+	return candidates
+}
+
+// keepBest inserts e into entries, keeping only the top gemsPerState by score.
+func keepBest(entries []gemEntry, e gemEntry) []gemEntry {
+	i := sort.Search(len(entries), func(i int) bool { return entries[i].score < e.score })
+	if i >= gemsPerState {
+		return entries
+	}
+	entries = append(entries, gemEntry{})
+	copy(entries[i+1:], entries[i:])
+	entries[i] = e
+	if len(entries) > gemsPerState {
+		entries = entries[:gemsPerState]
+	}
+	return entries
+}
@@ -0,0 +1,34 @@
+package tbc
+
+import "testing"
+
+func TestSearchGemsMetaAndUnique(t *testing.T) {
+	equip := NewEquipmentSet("Tidefury Helm", "Tidefury Chestpiece", "Stormsong Kilt")
+	weights := make([]float64, StatLen)
+	weights[StatSpellDmg] = 1
+
+	candidates := searchGems(equip, GemOptimizerOptions{Confirm: 5}, weights)
+	if len(candidates) == 0 {
+		t.Fatalf("Expected gem candidates.")
+	}
+	for _, c := range candidates {
+		meta, ok := c.Equip.MetaGem()
+		if !ok {
+			t.Fatalf("Expected a meta gem in the helm.")
+		}
+		if !c.Equip.MetaActive() {
+			t.Fatalf("%s socketed without meeting its requirements.", meta.Name)
+		}
+		julios := 0
+		for _, item := range c.Equip {
+			for _, g := range item.Gems {
+				if g.Unique {
+					julios++
+				}
+			}
+		}
+		if julios > 1 {
+			t.Fatalf("Unique gem socketed %d times.", julios)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package tbc
 
-import "testing"
+import (
+	"math"
+	"strings"
+	"testing"
+)
 
 func TestColorIntersection(t *testing.T) {
 	chryo := GemLookup["Rune Covered Chrysoprase"]
@@ -12,3 +16,89 @@ func TestColorIntersection(t *testing.T) {
 		t.Fatalf("Chryo intersects blue...")
 	}
 }
+
+func TestMetaRequirement(t *testing.T) {
+	csd := GemLookup["Chaotic Skyfire Diamond"]
+	ruby := GemLookup["Runed Living Ruby"]
+	talasite := GemLookup["Dazzling Talasite"]
+
+	equip := Equipment{
+		{Name: "Head", GemSlots: []GemColor{GemColorMeta, GemColorRed}, Gems: []Gem{csd, ruby}},
+		{Name: "Chest", GemSlots: []GemColor{GemColorRed}, Gems: []Gem{talasite}},
+	}
+	if equip.MetaActive() {
+		t.Fatalf("CSD should be inactive with only 1 blue gem")
+	}
+	if equip.MetaWarning() == "" {
+		t.Fatalf("expected a warning for inactive meta gem")
+	}
+	if crit := equip.Stats()[StatSpellCrit]; crit != 0 {
+		t.Fatalf("inactive meta gem should not add stats, got %0.0f crit", crit)
+	}
+
+	equip[0].Gems[1] = talasite
+	if !equip.MetaActive() {
+		t.Fatalf("CSD should be active with 2 blue gems")
+	}
+	if equip.MetaWarning() != "" {
+		t.Fatalf("unexpected warning: %s", equip.MetaWarning())
+	}
+	if crit := equip.Stats()[StatSpellCrit]; crit != 12 {
+		t.Fatalf("active meta gem should add 12 crit, got %0.0f", crit)
+	}
+}
+
+func TestSetCount(t *testing.T) {
+	set := ItemSet{Name: "Test", Items: map[int32]int{1: 0, 2: 0, 3: 1}}
+	if n := set.Count(Equipment{{ID: 1}, {ID: 2}, {ID: 4}}); n != 1 {
+		t.Fatalf("alternate IDs of a piece should count once, got %d", n)
+	}
+	if n := set.Count(Equipment{{ID: 2}, {ID: 3}}); n != 2 {
+		t.Fatalf("expected 2 pieces, got %d", n)
+	}
+}
+
+func TestActivateSets(t *testing.T) {
+	equip := NewEquipmentSet("Tidefury Helm", "Tidefury Chestpiece", "Tidefury Kilt", "Tidefury Shoulderguards", "Tidefury Gauntlets", "Spellstrike Hood")
+	equip = append(equip, equip[EquipHead]) // a duplicate piece doesn't count twice
+	opts := Options{UseAI: true, Buffs: Buffs{WaterShield: true}}
+	sim := NewSim(Stats{StatLen: 0}, equip, opts)
+	active := sim.ActivateSets()
+	if got := strings.Join(active, ", "); got != "Tidefury (2pc), Tidefury (4pc)" {
+		t.Fatalf("unexpected active sets: %s", got)
+	}
+	if mp5 := sim.Buffs[StatMP5]; mp5 != 3 {
+		t.Fatalf("tidefury 4pc should add 3 mp5 once, got %0.0f", mp5)
+	}
+}
+
+func TestChainLightningJumps(t *testing.T) {
+	tidefury := NewEquipmentSet("Tidefury Helm", "Tidefury Chestpiece")
+	cases := []struct {
+		name    string
+		targets int
+		equip   Equipment
+		want    float64 // times the single target damage
+	}{
+		{"single target", 0, nil, 1},
+		{"three targets", 3, nil, 1 + 0.7 + 0.49},
+		{"jumps cap at two", 5, nil, 1 + 0.7 + 0.49},
+		{"tidefury 2pc", 3, tidefury, 1 + 0.83 + 0.83*0.83},
+	}
+	for _, c := range cases {
+		// Same seed, so the same rolls, and a preset hit so stats don't matter.
+		opts := Options{UseAI: true, RSeed: 1, Targets: c.targets}
+		sim := NewSim(Stats{StatSpellHit: 1260, StatLen: 0}, c.equip, opts)
+		sim.reset()
+		single := NewSim(Stats{StatSpellHit: 1260, StatLen: 0}, nil, Options{UseAI: true, RSeed: 1})
+		single.reset()
+
+		cast := &Cast{Spell: spellmap[MagicIDCL6], DidDmg: 1000}
+		sim.Cast(cast)
+		base := &Cast{Spell: spellmap[MagicIDCL6], DidDmg: 1000}
+		single.Cast(base)
+		if got := cast.DidDmg / base.DidDmg; math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("%s: expected %0.4fx the single target damage, got %0.4fx", c.name, c.want, got)
+		}
+	}
+}
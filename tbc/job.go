@@ -0,0 +1,123 @@
+package tbc
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Progress is how far along a Job is.
+type Progress struct {
+	Phase      string `json:"phase"`      // what is being simulated, like "stat weights" or "upgrades".
+	Iterations int    `json:"iterations"` // iterations run in this phase so far.
+	Total      int    `json:"total"`      // iterations the phase will run, 0 if unknown (with a precision target).
+}
+
+// progressInterval is how often a Job calls its progress callback.
+const progressInterval = 250 * time.Millisecond
+
+// Job follows a long run like stat weights or the gear optimizer, reporting its progress and stopping it
+// once its context is cancelled. Set it in Options.Job, every sim made from those options then counts
+// its iterations to the job and stops as soon as the job is cancelled. Results of a cancelled run
+// are meaningless, check Err first. A nil Job does nothing.
+type Job struct {
+	ctx        context.Context
+	done       <-chan struct{}
+	onProgress func(Progress)
+
+	iterations int64 // all iterations run, updated atomically.
+	lastReport int64 // unix nanoseconds of the last progress callback.
+	reporting  int32 // set while the progress callback runs.
+
+	mu         sync.Mutex
+	phase      string
+	phaseStart int64
+	phaseTotal int
+}
+
+// NewJob makes a job that is cancelled with ctx. If onProgress is set it is called with the progress
+// every so often from whichever goroutine is simulating, but never concurrently.
+func NewJob(ctx context.Context, onProgress func(Progress)) *Job {
+	return &Job{ctx: ctx, done: ctx.Done(), onProgress: onProgress}
+}
+
+// Phase starts the next phase of the job, expected to run total iterations (0 if unknown).
+func (j *Job) Phase(name string, total int) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	j.phase, j.phaseStart, j.phaseTotal = name, atomic.LoadInt64(&j.iterations), total
+	j.mu.Unlock()
+}
+
+// phaseTotal is the total for a phase of runs iterations of numSims each, unknown with a precision target.
+func phaseTotal(opts Options, runs int, numSims int) int {
+	if opts.Precision.enabled() {
+		return 0
+	}
+	return runs * numSims
+}
+
+// Progress returns how far along the current phase is.
+func (j *Job) Progress() Progress {
+	if j == nil {
+		return Progress{}
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	p := Progress{Phase: j.phase, Iterations: int(atomic.LoadInt64(&j.iterations) - j.phaseStart), Total: j.phaseTotal}
+	if p.Total > 0 && p.Iterations > p.Total {
+		p.Iterations = p.Total
+	}
+	return p
+}
+
+// Err is the error of the job's context once it is cancelled, nil until then.
+func (j *Job) Err() error {
+	if j == nil {
+		return nil
+	}
+	return j.ctx.Err()
+}
+
+// cancelled is a cheap check for the sim loops, a nil or never cancelled job is never cancelled.
+func (j *Job) cancelled() bool {
+	if j == nil {
+		return false
+	}
+	select {
+	case <-j.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// countIterations counts iterations that weren't simmed in this process, from the result cache or a worker.
+func (j *Job) countIterations(n int) {
+	if j == nil {
+		return
+	}
+	atomic.AddInt64(&j.iterations, int64(n))
+}
+
+// iterationDone counts an iteration and calls the progress callback if it is time to.
+func (j *Job) iterationDone() {
+	if j == nil {
+		return
+	}
+	atomic.AddInt64(&j.iterations, 1)
+	if j.onProgress == nil {
+		return
+	}
+	now := time.Now().UnixNano()
+	last := atomic.LoadInt64(&j.lastReport)
+	if now-last < int64(progressInterval) || !atomic.CompareAndSwapInt32(&j.reporting, 0, 1) {
+		return
+	}
+	atomic.StoreInt64(&j.lastReport, now)
+	j.onProgress(j.Progress())
+	atomic.StoreInt32(&j.reporting, 0)
+}
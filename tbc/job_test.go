@@ -0,0 +1,44 @@
+package tbc
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestJobProgress(t *testing.T) {
+	equip := NewEquipmentSet("Tidefury Helm", "Tidefury Chestpiece", "Gavel of Unearthed Secrets")
+	reports := 0
+	job := NewJob(context.Background(), func(Progress) { reports++ })
+	job.lastReport = time.Now().Add(-time.Hour).UnixNano() // report on the first iteration.
+	opts := Options{Talents: Talents{Concussion: 5, CallOfThunder: 5}, Job: job}
+
+	CalculateStatWeights(opts, equip, 60, 20, StatWeightOptions{Stats: []Stat{StatSpellDmg, StatSpellCrit}})
+	p := job.Progress()
+	if p.Phase != "stat weights" || p.Total != 60 || p.Iterations != 60 {
+		t.Fatalf("expected all 60 stat weight iterations to be done, got %+v", p)
+	}
+	if reports == 0 {
+		t.Fatalf("progress was never reported")
+	}
+	if job.Err() != nil {
+		t.Fatalf("job wasn't cancelled but has error %v", job.Err())
+	}
+}
+
+func TestJobCancel(t *testing.T) {
+	equip := NewEquipmentSet("Tidefury Helm", "Tidefury Chestpiece", "Gavel of Unearthed Secrets")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	job := NewJob(ctx, nil)
+	opts := Options{Talents: Talents{Concussion: 5, CallOfThunder: 5}, Precision: Precision{StdErr: 0.01}, Job: job}
+
+	start := time.Now()
+	CalculateStatWeights(opts, equip, 300, 100000, StatWeightOptions{})
+	if took := time.Since(start); took > 5*time.Second {
+		t.Fatalf("cancelled stat weights still took %s", took)
+	}
+	if job.Err() != context.Canceled || job.Progress().Iterations != 0 {
+		t.Fatalf("expected a cancelled job with no iterations, got %v %+v", job.Err(), job.Progress())
+	}
+}
@@ -0,0 +1,580 @@
+package tbc
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/url"
+	"strings"
+)
+
+// Versions of the packed format. Pack always writes PackVersion, Unpack reads every version.
+// A new version is needed whenever the layout changes, the old reader stays so old links still work.
+const (
+	packV0      = 0 // options only, from before gear was packed. Old UI links put the gear JSON in front.
+	packV1      = 1
+	PackVersion = packV1
+)
+
+var errPackTruncated = errors.New("packed data ended early")
+
+// Pack converts the options to a compact binary format with a version byte in front, so a simulation can be shared.
+// Run settings (rotation, random seed, precision and debug) aren't packed, those are up to whoever runs the sim.
+// UnpackOptions reverses it.
+func (o Options) Pack() []byte {
+	w := &packWriter{buf: []byte{PackVersion}}
+	w.options(o)
+	return w.buf
+}
+
+// UnpackOptions reads options written by Pack, of any version.
+// Options added after the version they were packed with are left at their zero value.
+func UnpackOptions(data []byte) (Options, error) {
+	r := &packReader{buf: data}
+	opt := r.options()
+	if r.err == nil && len(r.buf) > 0 {
+		r.err = fmt.Errorf("%d unexpected bytes after the options", len(r.buf))
+	}
+	return opt, r.err
+}
+
+// Pack converts the setup to the compact binary format of Options.Pack, followed by the gear
+// as item, gem and enchant IDs. UnpackSetup reverses it.
+func (s Setup) Pack() []byte {
+	w := &packWriter{buf: []byte{PackVersion}}
+	w.options(s.Options)
+	w.gear(s.Equip)
+	return w.buf
+}
+
+// UnpackSetup reads a setup written by Pack.
+// IDs missing from the item data are reported as problems and the rest of the gear is still loaded,
+// the returned error is only for data that can't be read at all.
+func UnpackSetup(data []byte) (Setup, GearProblems, error) {
+	r := &packReader{buf: data}
+	s := Setup{Options: r.options()}
+	if r.err == nil && r.version == packV0 {
+		return s, nil, errors.New("version 0 only holds options, not gear")
+	}
+	var problems GearProblems
+	s.Equip, problems = r.gear()
+	if r.err == nil && len(r.buf) > 0 {
+		r.err = fmt.Errorf("%d unexpected bytes after the gear", len(r.buf))
+	}
+	return s, problems, r.err
+}
+
+// Link is the setup as text to put in a URL or paste in chat: the version byte of Pack
+// followed by the rest of it deflated, in unpadded URL-safe base64.
+func (s Setup) Link() string {
+	packed := s.Pack()
+	var buf bytes.Buffer
+	buf.WriteByte(packed[0])
+	fw, _ := flate.NewWriter(&buf, flate.BestCompression)
+	fw.Write(packed[1:])
+	fw.Close()
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes())
+}
+
+// ParseLink reads a setup from Link, or from the links the web UI made before gear was packed.
+// The link can be just the text or a whole URL with the text after the #.
+func ParseLink(link string) (Setup, GearProblems, error) {
+	link = strings.TrimSpace(link)
+	if i := strings.LastIndex(link, "#"); i >= 0 {
+		link = link[i+1:]
+	}
+	if strings.Contains(link, "%") {
+		if unescaped, err := url.PathUnescape(link); err == nil {
+			link = unescaped
+		}
+	}
+	// Old links are standard base64 with padding, new ones are URL-safe without, this reads both.
+	link = strings.TrimRight(strings.NewReplacer("-", "+", "_", "/").Replace(link), "=")
+	data, err := base64.RawStdEncoding.DecodeString(link)
+	if err != nil {
+		return Setup{}, nil, fmt.Errorf("link isn't valid base64: %w", err)
+	}
+	if len(data) == 0 {
+		return Setup{}, nil, errors.New("link is empty")
+	}
+	if data[0] == packV0 {
+		// Old links start with the length of the gear JSON as a big endian int32, and its top byte is always 0.
+		return parseLinkV0(data)
+	}
+	body, err := ioutil.ReadAll(flate.NewReader(bytes.NewReader(data[1:])))
+	if err != nil {
+		return Setup{}, nil, fmt.Errorf("link data is corrupt: %w", err)
+	}
+	return UnpackSetup(append([]byte{data[0]}, body...))
+}
+
+// parseLinkV0 reads the links the web UI made before gear was packed:
+// the length of the gear JSON, the zlib compressed gear JSON, then version 0 options.
+func parseLinkV0(data []byte) (Setup, GearProblems, error) {
+	if len(data) < 4 {
+		return Setup{}, nil, errPackTruncated
+	}
+	n := int(binary.BigEndian.Uint32(data))
+	if n > len(data)-4 {
+		return Setup{}, nil, errPackTruncated
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(data[4 : 4+n]))
+	if err != nil {
+		return Setup{}, nil, fmt.Errorf("link gear is corrupt: %w", err)
+	}
+	gearJSON, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return Setup{}, nil, fmt.Errorf("link gear is corrupt: %w", err)
+	}
+	var gear []struct {
+		ID int32
+		G  []int32
+		E  int32
+	}
+	if err := json.Unmarshal(gearJSON, &gear); err != nil {
+		return Setup{}, nil, fmt.Errorf("link gear is corrupt: %w", err)
+	}
+
+	s := Setup{}
+	s.Options, err = UnpackOptions(data[4+n:])
+	if err != nil {
+		return s, nil, err
+	}
+	problems := GearProblems{}
+	items := make([]Item, 0, len(gear))
+	for _, g := range gear {
+		item, ok := ItemsByID[g.ID]
+		if !ok {
+			problems = append(problems, GearProblem{Item: fmt.Sprintf("ID %d", g.ID), Message: "not in the item data"})
+			continue
+		}
+		if len(g.G) > 0 {
+			item.Gems = make([]Gem, len(item.GemSlots))
+		}
+		for i, id := range g.G {
+			if i < len(item.Gems) {
+				problems = append(problems, item.setGem(i, id)...)
+			}
+		}
+		problems = append(problems, item.setEnchant(g.E)...)
+		items = append(items, item)
+	}
+	var placement GearProblems
+	s.Equip, placement = NewEquipment(items...)
+	return s, append(problems, placement...), nil
+}
+
+func (item *Item) setGem(i int, id int32) GearProblems {
+	if id == 0 {
+		return nil
+	}
+	gem, ok := GemsByID[id]
+	if !ok {
+		return GearProblems{{Slot: item.Slot, Item: item.Name, Message: fmt.Sprintf("gem ID %d is not in the gem data", id)}}
+	}
+	item.Gems[i] = gem
+	return nil
+}
+
+func (item *Item) setEnchant(id int32) GearProblems {
+	if id == 0 {
+		return nil
+	}
+	en, ok := EnchantByID[id]
+	if !ok {
+		return GearProblems{{Slot: item.Slot, Item: item.Name, Message: fmt.Sprintf("enchant ID %d is not in the enchant data", id)}}
+	}
+	item.Enchant = en
+	return nil
+}
+
+// packWriter appends values to buf: numbers as varints and bools as bit flags.
+type packWriter struct {
+	buf []byte
+}
+
+func (w *packWriter) uint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	w.buf = append(w.buf, b[:binary.PutUvarint(b[:], v)]...)
+}
+
+func (w *packWriter) int(v int64) {
+	var b [binary.MaxVarintLen64]byte
+	w.buf = append(w.buf, b[:binary.PutVarint(b[:], v)]...)
+}
+
+// float writes whole numbers as an even varint and anything else as 1 followed by all 8 bytes.
+func (w *packWriter) float(v float64) {
+	if v == math.Trunc(v) && math.Abs(v) < 1<<50 {
+		w.int(int64(v) * 2)
+		return
+	}
+	w.int(1)
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], math.Float64bits(v))
+	w.buf = append(w.buf, b[:]...)
+}
+
+// flags writes up to 64 bools as the bits of a single varint, the first bool in the lowest bit.
+func (w *packWriter) flags(bits ...bool) {
+	var v uint64
+	for i, b := range bits {
+		if b {
+			v |= 1 << i
+		}
+	}
+	w.uint(v)
+}
+
+func (w *packWriter) options(o Options) {
+	w.int(int64(o.NumBloodlust))
+	w.int(int64(o.NumDrums))
+
+	b := o.Buffs
+	w.flags(b.ArcaneInt, b.GiftOftheWild, b.BlessingOfKings, b.ImprovedBlessingOfWisdom, b.ImprovedDivineSpirit,
+		b.Moonkin, b.MoonkinRavenGoddess, b.EyeOfNight, b.TwilightOwl, b.WaterShield,
+		b.JudgementOfWisdom, b.ImpSealofCrusader, b.Misery)
+	w.uint(uint64(b.WaterShieldPPM))
+	w.uint(uint64(b.SpriestDPS))
+	w.uint(uint64(b.Race))
+	// custom stats are usually all zero, so only the ones that aren't are written.
+	w.uint(uint64(len(b.Custom)))
+	set := 0
+	for _, v := range b.Custom {
+		if v != 0 {
+			set++
+		}
+	}
+	w.uint(uint64(set))
+	for i, v := range b.Custom {
+		if v != 0 {
+			w.uint(uint64(i))
+			w.float(v)
+		}
+	}
+
+	c := o.Consumes
+	w.flags(c.BrilliantWizardOil, c.MajorMageblood, c.FlaskOfBlindingLight, c.FlaskOfMightyRestoration,
+		c.BlackendBasilisk, c.DestructionPotion, c.SuperManaPotion, c.DarkRune)
+	for _, p := range []ConsumePolicy{c.Policies.DestructionPotion, c.Policies.SuperManaPotion, c.Policies.DarkRune} {
+		w.float(p.ManaDeficit)
+		w.int(int64(p.After))
+		w.int(int64(p.MaxUses))
+		w.flags(p.PrePot)
+	}
+
+	t := o.Talents
+	for _, v := range []int{t.LightninOverload, t.ElementalPrecision, t.NaturesGuidance, t.TidalMastery,
+		t.UnrelentingStorm, t.CallOfThunder, t.Convection} {
+		w.int(int64(v))
+	}
+	w.flags(t.ElementalMastery)
+	w.float(t.Concussion)
+
+	w.int(int64(o.Totems.TotemOfWrath))
+	w.flags(o.Totems.WrathOfAir, o.Totems.ManaStream, o.Totems.Cyclone2PC)
+
+	cd := o.Cooldowns
+	w.uint(uint64(cd.Policy))
+	w.int(int64(cd.BloodlustAt))
+	w.float(cd.ExecutePercent)
+	w.int(int64(cd.TrinketFirst))
+
+	w.flags(o.ExitOnOOM)
+	w.int(int64(o.DPSReportTime))
+}
+
+// gear writes each item that is worn with its slot, then the IDs of the item, its gems and its enchant.
+func (w *packWriter) gear(e Equipment) {
+	worn := 0
+	for _, item := range e {
+		if item.ID != 0 {
+			worn++
+		}
+	}
+	w.uint(uint64(worn))
+	for slot, item := range e {
+		if item.ID == 0 {
+			continue
+		}
+		w.uint(uint64(slot))
+		w.uint(uint64(item.ID))
+		w.uint(uint64(len(item.Gems)))
+		for _, g := range item.Gems {
+			w.uint(uint64(g.ID))
+		}
+		w.uint(uint64(item.Enchant.ID))
+	}
+}
+
+// packReader reads what packWriter wrote. The first error stops all further reads,
+// so callers can read everything and check err once at the end.
+type packReader struct {
+	buf     []byte
+	version byte
+	err     error
+}
+
+func (r *packReader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.buf) == 0 {
+		r.err = errPackTruncated
+		return 0
+	}
+	b := r.buf[0]
+	r.buf = r.buf[1:]
+	return b
+}
+
+func (r *packReader) bytes(n int) []byte {
+	if r.err != nil {
+		return make([]byte, n)
+	}
+	if len(r.buf) < n {
+		r.err = errPackTruncated
+		return make([]byte, n)
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *packReader) uint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.err = errPackTruncated
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *packReader) int() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.err = errPackTruncated
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *packReader) float() float64 {
+	v := r.int()
+	if v%2 == 0 {
+		return float64(v / 2)
+	}
+	if v != 1 && r.err == nil {
+		r.err = fmt.Errorf("invalid packed number %d", v)
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(r.bytes(8)))
+}
+
+// flags reads a varint written by packWriter.flags into bits, in the same order.
+func (r *packReader) flags(bits ...*bool) {
+	v := r.uint()
+	for i, b := range bits {
+		*b = v&(1<<i) != 0
+	}
+}
+
+// options reads the version byte and then the options in the layout of that version.
+func (r *packReader) options() Options {
+	r.version = r.byte()
+	if r.err != nil {
+		return Options{}
+	}
+	switch r.version {
+	case packV0:
+		return r.optionsV0()
+	case packV1:
+		return r.optionsV1()
+	}
+	r.err = fmt.Errorf("unknown packed version %d, it might be from a newer version of the sim", r.version)
+	return Options{}
+}
+
+func (r *packReader) optionsV1() Options {
+	o := Options{}
+	o.NumBloodlust = int(r.int())
+	o.NumDrums = int(r.int())
+
+	b := &o.Buffs
+	r.flags(&b.ArcaneInt, &b.GiftOftheWild, &b.BlessingOfKings, &b.ImprovedBlessingOfWisdom, &b.ImprovedDivineSpirit,
+		&b.Moonkin, &b.MoonkinRavenGoddess, &b.EyeOfNight, &b.TwilightOwl, &b.WaterShield,
+		&b.JudgementOfWisdom, &b.ImpSealofCrusader, &b.Misery)
+	b.WaterShieldPPM = byte(r.uint())
+	b.SpriestDPS = uint16(r.uint())
+	b.Race = RaceBonusType(r.uint())
+	if n := r.uint(); n > 0 && r.err == nil {
+		if n > uint64(StatLen)*8 {
+			r.err = fmt.Errorf("too many custom stats: %d", n)
+			return o
+		}
+		b.Custom = make(Stats, n)
+	}
+	for set := r.uint(); set > 0 && r.err == nil; set-- {
+		i := r.uint()
+		v := r.float()
+		if i >= uint64(len(b.Custom)) {
+			r.err = fmt.Errorf("custom stat %d out of range", i)
+			return o
+		}
+		b.Custom[i] = v
+	}
+
+	c := &o.Consumes
+	r.flags(&c.BrilliantWizardOil, &c.MajorMageblood, &c.FlaskOfBlindingLight, &c.FlaskOfMightyRestoration,
+		&c.BlackendBasilisk, &c.DestructionPotion, &c.SuperManaPotion, &c.DarkRune)
+	for _, p := range []*ConsumePolicy{&c.Policies.DestructionPotion, &c.Policies.SuperManaPotion, &c.Policies.DarkRune} {
+		p.ManaDeficit = r.float()
+		p.After = int(r.int())
+		p.MaxUses = int(r.int())
+		r.flags(&p.PrePot)
+	}
+
+	t := &o.Talents
+	for _, v := range []*int{&t.LightninOverload, &t.ElementalPrecision, &t.NaturesGuidance, &t.TidalMastery,
+		&t.UnrelentingStorm, &t.CallOfThunder, &t.Convection} {
+		*v = int(r.int())
+	}
+	r.flags(&t.ElementalMastery)
+	t.Concussion = r.float()
+
+	o.Totems.TotemOfWrath = int(r.int())
+	r.flags(&o.Totems.WrathOfAir, &o.Totems.ManaStream, &o.Totems.Cyclone2PC)
+
+	cd := &o.Cooldowns
+	cd.Policy = CooldownPolicy(r.uint())
+	cd.BloodlustAt = int(r.int())
+	cd.ExecutePercent = r.float()
+	cd.TrinketFirst = int32(r.int())
+
+	r.flags(&o.ExitOnOOM)
+	o.DPSReportTime = int(r.int())
+	return o
+}
+
+// optionsV0 reads the fixed layout the web UI packed options with before there were versions:
+// single bytes and bit flags, with the custom stats as little endian float64s if any were set.
+func (r *packReader) optionsV0() Options {
+	o := Options{}
+	o.NumBloodlust = int(r.byte())
+	o.NumDrums = int(r.byte())
+
+	b := &o.Buffs
+	opt1, opt2 := r.byte(), r.byte()
+	b.ArcaneInt = opt1&1 != 0
+	b.GiftOftheWild = opt1&(1<<1) != 0
+	b.BlessingOfKings = opt1&(1<<2) != 0
+	b.ImprovedBlessingOfWisdom = opt1&(1<<3) != 0
+	b.ImprovedDivineSpirit = opt1&(1<<4) != 0
+	b.Moonkin = opt1&(1<<5) != 0
+	b.MoonkinRavenGoddess = opt1&(1<<6) != 0
+	b.EyeOfNight = opt1&(1<<7) != 0
+	b.TwilightOwl = opt2&1 != 0
+	b.WaterShield = opt2&(1<<1) != 0
+	b.JudgementOfWisdom = opt2&(1<<2) != 0
+	b.ImpSealofCrusader = opt2&(1<<3) != 0
+	b.Misery = opt2&(1<<4) != 0
+	b.WaterShieldPPM = r.byte()
+	b.SpriestDPS = binary.LittleEndian.Uint16(r.bytes(2))
+	b.Race = RaceBonusType(r.byte())
+	if n := int(r.byte()); n > 0 {
+		b.Custom = make(Stats, n)
+		for i := range b.Custom {
+			b.Custom[i] = math.Float64frombits(binary.LittleEndian.Uint64(r.bytes(8)))
+		}
+	}
+
+	c := &o.Consumes
+	opt := r.byte()
+	c.BrilliantWizardOil = opt&1 != 0
+	c.MajorMageblood = opt&(1<<1) != 0
+	c.FlaskOfBlindingLight = opt&(1<<2) != 0
+	c.FlaskOfMightyRestoration = opt&(1<<3) != 0
+	c.BlackendBasilisk = opt&(1<<4) != 0
+	c.DestructionPotion = opt&(1<<5) != 0
+	c.SuperManaPotion = opt&(1<<6) != 0
+	c.DarkRune = opt&(1<<7) != 0
+
+	t := r.bytes(9)
+	o.Talents = Talents{
+		LightninOverload:   int(t[0]),
+		ElementalPrecision: int(t[1]),
+		NaturesGuidance:    int(t[2]),
+		TidalMastery:       int(t[3]),
+		ElementalMastery:   t[4] != 0,
+		UnrelentingStorm:   int(t[5]),
+		CallOfThunder:      int(t[6]),
+		Convection:         int(t[7]),
+		Concussion:         float64(t[8]),
+	}
+
+	o.Totems.TotemOfWrath = int(r.byte())
+	opt = r.byte()
+	o.Totems.WrathOfAir = opt&1 != 0
+	o.Totems.ManaStream = opt&(1<<1) != 0
+	o.Totems.Cyclone2PC = opt&(1<<2) != 0
+	return o
+}
+
+func (r *packReader) gear() (Equipment, GearProblems) {
+	e := make(Equipment, EquipTotem+1)
+	problems := GearProblems{}
+	n := r.uint()
+	if n > uint64(len(e)) && r.err == nil {
+		r.err = fmt.Errorf("%d items is more than there are slots", n)
+	}
+	for ; n > 0 && r.err == nil; n-- {
+		slot := r.uint()
+		id := int32(r.uint())
+		numGems := r.uint()
+		if numGems > 8 && r.err == nil {
+			r.err = fmt.Errorf("item %d has %d gems", id, numGems)
+			break
+		}
+		gems := make([]int32, numGems)
+		for i := range gems {
+			gems[i] = int32(r.uint())
+		}
+		enchant := int32(r.uint())
+		if r.err != nil {
+			break
+		}
+		if slot >= uint64(len(e)) || slot == uint64(EquipUnknown) || e[slot].ID != 0 {
+			r.err = fmt.Errorf("item %d is in invalid slot %d", id, slot)
+			break
+		}
+
+		item, ok := ItemsByID[id]
+		if !ok {
+			problems = append(problems, GearProblem{Slot: byte(slot), Item: fmt.Sprintf("ID %d", id), Message: "not in the item data"})
+			continue
+		}
+		if len(gems) > 0 {
+			item.Gems = make([]Gem, len(gems))
+		}
+		for i, g := range gems {
+			problems = append(problems, item.setGem(i, g)...)
+		}
+		problems = append(problems, item.setEnchant(enchant)...)
+		e[slot] = item
+	}
+	return e, problems
+}
@@ -0,0 +1,148 @@
+package tbc
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func packTestSetup() Setup {
+	opts := Options{
+		NumBloodlust: 2,
+		NumDrums:     1,
+		Buffs: Buffs{
+			ArcaneInt: true, Moonkin: true, Misery: true, WaterShield: true,
+			WaterShieldPPM: 2, SpriestDPS: 1100, Race: RaceBonusTroll30,
+			Custom: Stats{StatSpellDmg: 120, StatMP5: -12.5},
+		},
+		Consumes: Consumes{
+			BrilliantWizardOil: true, DestructionPotion: true, SuperManaPotion: true,
+			Policies: ConsumePolicies{SuperManaPotion: ConsumePolicy{ManaDeficit: 3000, After: 30}, DestructionPotion: ConsumePolicy{MaxUses: 1, PrePot: true}},
+		},
+		Talents:       Talents{LightninOverload: 5, ElementalPrecision: 3, ElementalMastery: true, CallOfThunder: 5, Convection: 5, Concussion: 5},
+		Totems:        Totems{TotemOfWrath: 1, WrathOfAir: true, Cyclone2PC: true},
+		Cooldowns:     CooldownOptions{Policy: CooldownPolicyHoldForExecute, BloodlustAt: 20, ExecutePercent: 0.25, TrinketFirst: 29370},
+		ExitOnOOM:     true,
+		DPSReportTime: 120,
+	}
+	helm := ItemsByName["Tidefury Helm"]
+	helm.Gems = []Gem{GemLookup["Chaotic Skyfire Diamond"], {}}
+	helm.Enchant = EnchantLookup["Glyph of Power"]
+	equip, _ := NewEquipment(helm, ItemsByName["Sparking Arcanite Ring"], ItemsByName["Icon of the Silver Crescent"], ItemsByName["Gavel of Unearthed Secrets"])
+	return Setup{opts, equip}
+}
+
+// sameGear compares worn items by ID, items with an Activate func can't be compared with reflect.
+func sameGear(t *testing.T, got Equipment, want Equipment) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d slots, want %d", len(got), len(want))
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.ID != w.ID || g.Enchant.ID != w.Enchant.ID || len(g.Gems) != len(w.Gems) {
+			t.Fatalf("%s: got %d (enchant %d, %d gems), want %d (enchant %d, %d gems)", SlotName(byte(i)), g.ID, g.Enchant.ID, len(g.Gems), w.ID, w.Enchant.ID, len(w.Gems))
+		}
+		for j := range w.Gems {
+			if g.Gems[j].ID != w.Gems[j].ID {
+				t.Fatalf("%s gem %d: got %d, want %d", SlotName(byte(i)), j, g.Gems[j].ID, w.Gems[j].ID)
+			}
+		}
+	}
+}
+
+func TestPackRoundTrip(t *testing.T) {
+	want := packTestSetup()
+
+	opts, err := UnpackOptions(want.Options.Pack())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(opts, want.Options) {
+		t.Fatalf("options changed:\ngot  %+v\nwant %+v", opts, want.Options)
+	}
+
+	got, problems, err := UnpackSetup(want.Pack())
+	if err != nil || len(problems) > 0 {
+		t.Fatalf("unexpected error %v, problems %v", err, problems.Strings())
+	}
+	if !reflect.DeepEqual(got.Options, want.Options) {
+		t.Fatalf("options changed:\ngot  %+v\nwant %+v", got.Options, want.Options)
+	}
+	sameGear(t, got.Equip, want.Equip)
+
+	link := want.Link()
+	if strings.ContainsAny(link, "+/=") {
+		t.Fatalf("link isn't URL safe: %s", link)
+	}
+	got, _, err = ParseLink("https://example.com/wowsim/#" + link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Options, want.Options) {
+		t.Fatalf("options changed through the link:\ngot  %+v\nwant %+v", got.Options, want.Options)
+	}
+	sameGear(t, got.Equip, want.Equip)
+}
+
+func TestUnpackErrors(t *testing.T) {
+	packed := packTestSetup().Pack()
+	if _, _, err := UnpackSetup(packed[:len(packed)-3]); err != errPackTruncated {
+		t.Fatalf("expected truncated data to fail, got %v", err)
+	}
+	if _, _, err := UnpackSetup(append([]byte{PackVersion + 1}, packed[1:]...)); err == nil || !strings.Contains(err.Error(), "unknown packed version") {
+		t.Fatalf("expected a newer version to fail, got %v", err)
+	}
+	if _, _, err := ParseLink("not a link!"); err == nil {
+		t.Fatalf("expected an invalid link to fail")
+	}
+
+	s := packTestSetup()
+	s.Equip[EquipHead].ID = 1 // not in the item data
+	got, problems, err := UnpackSetup(s.Pack())
+	if err != nil || len(problems) != 1 || got.Equip[EquipHead].ID != 0 || got.Equip[EquipTrinket1].ID == 0 {
+		t.Fatalf("expected only the unknown item to be a problem, got %v %v", err, problems.Strings())
+	}
+}
+
+// TestParseLinkV0 reads a link in the format the web UI made before gear was packed.
+func TestParseLinkV0(t *testing.T) {
+	var gear bytes.Buffer
+	zw := zlib.NewWriter(&gear)
+	zw.Write([]byte(`[{"ID":28231,"g":[25893,0],"e":29191},{"ID":21709}]`))
+	zw.Close()
+
+	opts := []byte{
+		0, 1, 0, // version, bloodlust, drums
+		1 | 1<<5, 1 << 4, 0, 0x4c, 0x04, byte(RaceBonusOrc), 0, // buffs: arcane int, moonkin, misery, shadow priest 1100 dps
+		1 | 1<<5,                  // consumes: wizard oil, destruction potion
+		5, 3, 3, 5, 1, 3, 5, 5, 5, // talents
+		1, 1, // totem of wrath, wrath of air
+	}
+	data := make([]byte, 4, 4+gear.Len()+len(opts))
+	binary.BigEndian.PutUint32(data, uint32(gear.Len()))
+	data = append(append(data, gear.Bytes()...), opts...)
+
+	s, problems, err := ParseLink(base64.StdEncoding.EncodeToString(data))
+	if err != nil || len(problems) > 0 {
+		t.Fatalf("unexpected error %v, problems %v", err, problems.Strings())
+	}
+	want := Options{
+		NumBloodlust: 1,
+		Buffs:        Buffs{ArcaneInt: true, Moonkin: true, Misery: true, SpriestDPS: 1100, Race: RaceBonusOrc},
+		Consumes:     Consumes{BrilliantWizardOil: true, DestructionPotion: true},
+		Talents:      Talents{LightninOverload: 5, ElementalPrecision: 3, NaturesGuidance: 3, TidalMastery: 5, ElementalMastery: true, UnrelentingStorm: 3, CallOfThunder: 5, Convection: 5, Concussion: 5},
+		Totems:       Totems{TotemOfWrath: 1, WrathOfAir: true},
+	}
+	if !reflect.DeepEqual(s.Options, want) {
+		t.Fatalf("got options %+v\nwant %+v", s.Options, want)
+	}
+	chest := s.Equip[EquipChest]
+	if chest.ID != 28231 || len(chest.Gems) != 3 || chest.Gems[0].ID != 25893 || chest.Gems[1].ID != 0 || chest.Enchant.ID != 29191 || s.Equip[EquipFinger1].ID != 21709 {
+		t.Fatalf("gear wasn't read from the link: chest %d with %d gems and enchant %d, ring %d", chest.ID, len(chest.Gems), chest.Enchant.ID, s.Equip[EquipFinger1].ID)
+	}
+}
@@ -0,0 +1,113 @@
+package tbc
+
+// ConsumePolicy controls when a single in-combat consumable is used.
+// The zero value keeps the default behavior for that consumable.
+type ConsumePolicy struct {
+	ManaDeficit float64 // mana consumables: only use once missing at least this much mana (minus one regen tick).
+	After       int     // earliest second of the fight the consumable can be used.
+	MaxUses     int     // limit on uses per fight, 0 is unlimited.
+	PrePot      bool    // use it right before the pull. Only Destruction Potion benefits from this.
+}
+
+// ConsumePolicies holds a policy for each consumable used during the fight.
+// Destruction Potion and Super Mana Potion share the potion cooldown, so only one of them can be up at a time.
+type ConsumePolicies struct {
+	DestructionPotion ConsumePolicy
+	SuperManaPotion   ConsumePolicy
+	DarkRune          ConsumePolicy
+}
+
+// ConsumeMetrics counts how many of each consumable was used in a single iteration.
+type ConsumeMetrics struct {
+	DestructionPotion int
+	SuperManaPotion   int
+	DarkRune          int
+}
+
+// prePotLead is how many seconds before the pull a pre-pot is drunk.
+const prePotLead = 2
+
+func (c Consumes) destructionPolicy() ConsumePolicy {
+	p := c.Policies.DestructionPotion
+	if p.MaxUses == 0 && c.SuperManaPotion {
+		// If we are using mana potions, only use destruction potion once.
+		p.MaxUses = 1
+	}
+	return p
+}
+
+func (c Consumes) manaPotionPolicy() ConsumePolicy {
+	p := c.Policies.SuperManaPotion
+	if p.ManaDeficit == 0 {
+		p.ManaDeficit = 3000 // restores 1800 to 3000 mana.
+	}
+	return p
+}
+
+func (c Consumes) runePolicy() ConsumePolicy {
+	p := c.Policies.DarkRune
+	if p.ManaDeficit == 0 {
+		p.ManaDeficit = 1500 // restores 900 to 1500 mana.
+	}
+	return p
+}
+
+// canConsume reports if the policy allows a consumable with the given number of uses so far to be used now.
+func (sim *Simulation) canConsume(p ConsumePolicy, used int) bool {
+	if p.MaxUses > 0 && used >= p.MaxUses {
+		return false
+	}
+	return sim.CurrentTick >= p.After*TicksPerSecond
+}
+
+// manaMissing is how much mana we are missing, counting the next regen tick.
+func (sim *Simulation) manaMissing() float64 {
+	return sim.Stats[StatMana] - sim.CurrentMana + sim.Stats[StatMP5] + sim.Buffs[StatMP5]
+}
+
+// prePot drinks a destruction potion before the pull.
+// Both the buff and the shared potion cooldown have already been ticking for prePotLead seconds.
+// It counts as a use of the cooldown, so the pull only policy doesn't drink another.
+func (sim *Simulation) prePot() {
+	aura := ActivateDestructionPotion(sim)
+	aura.Expires -= prePotLead * TicksPerSecond
+	sim.CDs[MagicIDPotion] -= prePotLead * TicksPerSecond
+	sim.cdUses[MagicIDDestructionPotion]++
+	sim.addAura(aura)
+}
+
+// useConsumes will use any consumables allowed by their policies.
+// Returns true if mana was restored.
+func (sim *Simulation) useConsumes() bool {
+	consumes := sim.Options.Consumes
+	used := &sim.metrics.Consumed
+
+	if consumes.DestructionPotion && sim.CDs[MagicIDPotion] < 1 && sim.canConsume(consumes.destructionPolicy(), used.DestructionPotion) {
+		if sim.useCooldown(MagicIDDestructionPotion, 120) {
+			sim.addAura(ActivateDestructionPotion(sim))
+		}
+	}
+
+	didPot := false
+	if p := consumes.runePolicy(); consumes.DarkRune && sim.CDs[MagicIDRune] < 1 && sim.manaMissing() >= p.ManaDeficit && sim.canConsume(p, used.DarkRune) {
+		// Restores 900 to 1500 mana. (2 Min Cooldown)
+		sim.CurrentMana += 900 + (sim.rando.Float64() * 600)
+		sim.CDs[MagicIDRune] = 120 * TicksPerSecond
+		used.DarkRune++
+		didPot = true
+		if sim.Debug != nil {
+			sim.Debug("Used Dark Rune\n")
+		}
+	}
+	if p := consumes.manaPotionPolicy(); consumes.SuperManaPotion && sim.CDs[MagicIDPotion] < 1 && sim.manaMissing() >= p.ManaDeficit && sim.canConsume(p, used.SuperManaPotion) {
+		// Restores 1800 to 3000 mana. (2 Min Cooldown)
+		sim.CurrentMana += 1800 + (sim.rando.Float64() * 1200)
+		sim.CDs[MagicIDPotion] = 120 * TicksPerSecond
+		used.SuperManaPotion++
+		didPot = true
+		if sim.Debug != nil {
+			sim.Debug("Used Mana Potion\n")
+		}
+	}
+	return didPot
+}
@@ -0,0 +1,69 @@
+package tbc
+
+import "testing"
+
+// consumed sims a single iteration of the given length with the consumables and returns what was used.
+func consumed(t *testing.T, seconds int, consumes Consumes, policy CooldownPolicy) ConsumeMetrics {
+	t.Helper()
+	equip := NewEquipmentSet("Tidefury Helm", "Tidefury Chestpiece", "Gavel of Unearthed Secrets")
+	opts := Options{
+		SpellOrder: []string{"CL6", "LB12", "LB12", "LB12"},
+		RSeed:      1,
+		Talents:    Talents{Concussion: 5, CallOfThunder: 5},
+		Consumes:   consumes,
+		Cooldowns:  CooldownOptions{Policy: policy},
+	}
+	return NewSim(CalculateTotalStats(opts, equip), equip, opts).Run(seconds).Consumed
+}
+
+func TestDestructionPotionPolicy(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy ConsumePolicy
+		cd     CooldownPolicy
+		want   int
+	}{
+		{"every cooldown", ConsumePolicy{}, CooldownPolicyASAP, 3}, // 0, 120 and 240 seconds.
+		{"max uses", ConsumePolicy{MaxUses: 2}, CooldownPolicyASAP, 2},
+		{"after", ConsumePolicy{After: 200}, CooldownPolicyASAP, 1},
+		{"pre-pot", ConsumePolicy{PrePot: true}, CooldownPolicyASAP, 3}, // -2, 118 and 238 seconds.
+		{"pre-pot counts to max uses", ConsumePolicy{PrePot: true, MaxUses: 1}, CooldownPolicyASAP, 1},
+		{"pull only", ConsumePolicy{}, CooldownPolicyPullOnly, 1},
+		{"pre-pot is the pull only use", ConsumePolicy{PrePot: true}, CooldownPolicyPullOnly, 1},
+	}
+	for _, c := range cases {
+		consumes := Consumes{DestructionPotion: true, Policies: ConsumePolicies{DestructionPotion: c.policy}}
+		if got := consumed(t, 300, consumes, c.cd).DestructionPotion; got != c.want {
+			t.Errorf("%s: expected %d destruction potions, got %d", c.name, c.want, got)
+		}
+	}
+}
+
+func TestManaConsumePolicy(t *testing.T) {
+	base := consumed(t, 300, Consumes{SuperManaPotion: true, DarkRune: true}, CooldownPolicyASAP)
+	if base.SuperManaPotion == 0 || base.DarkRune == 0 {
+		t.Fatalf("expected a long fight to use mana consumables, got %+v", base)
+	}
+
+	cases := []struct {
+		name     string
+		policies ConsumePolicies
+		want     ConsumeMetrics
+	}{
+		{"never missing enough mana", ConsumePolicies{SuperManaPotion: ConsumePolicy{ManaDeficit: 1e9}, DarkRune: ConsumePolicy{ManaDeficit: 1e9}}, ConsumeMetrics{}},
+		{"after the fight", ConsumePolicies{SuperManaPotion: ConsumePolicy{After: 400}, DarkRune: ConsumePolicy{After: 400}}, ConsumeMetrics{}},
+		{"max uses", ConsumePolicies{SuperManaPotion: ConsumePolicy{MaxUses: 1}, DarkRune: ConsumePolicy{MaxUses: 1}}, ConsumeMetrics{SuperManaPotion: 1, DarkRune: 1}},
+	}
+	for _, c := range cases {
+		got := consumed(t, 300, Consumes{SuperManaPotion: true, DarkRune: true, Policies: c.policies}, CooldownPolicyASAP)
+		if got != c.want {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.want, got)
+		}
+	}
+
+	// Mana potions limit the destruction potion to one, and share its cooldown.
+	got := consumed(t, 300, Consumes{DestructionPotion: true, SuperManaPotion: true}, CooldownPolicyASAP)
+	if got.DestructionPotion != 1 || got.SuperManaPotion == 0 || got.SuperManaPotion > 2 {
+		t.Errorf("expected one destruction potion and at most two mana potions, got %+v", got)
+	}
+}
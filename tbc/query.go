@@ -0,0 +1,127 @@
+package tbc
+
+import (
+	"sort"
+	"strings"
+)
+
+// ItemFilter limits which items are considered by the optimizers, the gear list and item queries.
+// The zero value allows every item.
+type ItemFilter struct {
+	Slots      []byte      // only items for these slots, EquipFinger/EquipTrinket for rings/trinkets. Empty allows every slot.
+	MaxPhase   byte        // only use items available in this phase or earlier, 0 allows every phase.
+	MinQuality ItemQuality // only use items of at least this quality.
+	MinLevel   int         // only use items of at least this item level.
+
+	// Source zones are matched ignoring case against any part of the item's source zone, so Kara matches every Karazhan item.
+	Zones        []string // only use items from these source zones, empty allows every zone.
+	ExcludeZones []string // never use items from these source zones.
+
+	// Only use armor of these types, like mail for a shaman keeping the mail specialization.
+	// Items without an armor type (jewelry, cloaks, weapons) are always allowed.
+	ArmorTypes []ArmorType
+
+	// Professions the player has. Items that need a profession are only allowed if it is listed.
+	// Empty allows every profession, use ProfessionNone alone to allow no profession items.
+	Professions []Profession
+
+	NoShields    bool // don't consider shields for the offhand.
+	NoTwoHanders bool // don't consider two handed weapons.
+}
+
+// Allows checks an item against the filter.
+func (f ItemFilter) Allows(item Item) bool {
+	if item.Slot == EquipUnknown {
+		return false
+	}
+	if len(f.Slots) > 0 && !slotListed(f.Slots, item.Slot) {
+		return false
+	}
+	if f.MaxPhase != 0 && item.Phase > f.MaxPhase {
+		return false
+	}
+	if item.Quality < f.MinQuality || item.ItemLevel < f.MinLevel {
+		return false
+	}
+	if f.NoShields && item.SubSlot == SubslotShield {
+		return false
+	}
+	if f.NoTwoHanders && item.SubSlot == SubslotTwoHand {
+		return false
+	}
+	if len(f.ArmorTypes) > 0 && item.ArmorType != ArmorTypeNone && !armorListed(f.ArmorTypes, item.ArmorType) {
+		return false
+	}
+	if len(f.Professions) > 0 && item.Profession != ProfessionNone && !professionListed(f.Professions, item.Profession) {
+		return false
+	}
+	if len(f.Zones) > 0 && !zoneListed(f.Zones, item.SourceZone) {
+		return false
+	}
+	return !zoneListed(f.ExcludeZones, item.SourceZone)
+}
+
+// QueryItems returns every item in the item data the filter allows, ordered by ID.
+func QueryItems(f ItemFilter) []Item {
+	items := []Item{}
+	for _, item := range ItemsByID {
+		if f.Allows(item) {
+			items = append(items, item)
+		}
+	}
+	// map iteration order is random, keep results deterministic.
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items
+}
+
+// isArmorSlot reports if items in the slot have an armor type that classes are restricted on.
+func isArmorSlot(slot byte) bool {
+	switch slot {
+	case EquipHead, EquipShoulder, EquipChest, EquipWrist, EquipHands, EquipWaist, EquipLegs, EquipFeet:
+		return true
+	}
+	return false
+}
+
+func slotListed(slots []byte, slot byte) bool {
+	for _, s := range slots {
+		if s == slot {
+			return true
+		}
+	}
+	return false
+}
+
+func armorListed(types []ArmorType, a ArmorType) bool {
+	for _, t := range types {
+		if t == a {
+			return true
+		}
+	}
+	return false
+}
+
+func professionListed(profs []Profession, p Profession) bool {
+	for _, v := range profs {
+		if v == p {
+			return true
+		}
+	}
+	return false
+}
+
+func zoneListed(zones []string, zone string) bool {
+	for _, z := range zones {
+		if strings.Contains(strings.ToLower(zone), strings.ToLower(z)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSlot converts the name of a slot as used in the data files (head, finger, ...) to the slot.
+// Returns false if the name doesn't match any slot.
+func ParseSlot(name string) (byte, bool) {
+	slot, ok := slotKeys[strings.ToLower(name)]
+	return slot, ok
+}
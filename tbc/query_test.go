@@ -0,0 +1,53 @@
+package tbc
+
+import "testing"
+
+func TestQueryItems(t *testing.T) {
+	mail := QueryItems(ItemFilter{Slots: []byte{EquipHead, EquipNeck}, ArmorTypes: []ArmorType{ArmorTypeMail}})
+	if len(mail) == 0 {
+		t.Fatalf("expected mail helms and necks")
+	}
+	necks := 0
+	for i, item := range mail {
+		if item.Slot == EquipHead && item.ArmorType != ArmorTypeMail {
+			t.Errorf("%s is %s, not mail", item.Name, item.ArmorType)
+		}
+		if item.Slot == EquipNeck {
+			necks++
+		}
+		if i > 0 && mail[i-1].ID >= item.ID {
+			t.Errorf("items should be ordered by ID")
+		}
+	}
+	if necks == 0 {
+		t.Errorf("necks have no armor type and should always be allowed")
+	}
+
+	for _, item := range QueryItems(ItemFilter{Professions: []Profession{ProfessionNone}}) {
+		if item.Profession != ProfessionNone {
+			t.Errorf("%s needs %s and should be left out", item.Name, item.Profession)
+		}
+	}
+	jc := QueryItems(ItemFilter{Slots: []byte{EquipNeck}, Professions: []Profession{ProfessionJewelcrafting}})
+	if !containsItem(jc, "Pendant of Sunfire") {
+		t.Errorf("jewelcrafters should be able to use Pendant of Sunfire")
+	}
+
+	for _, item := range QueryItems(ItemFilter{Zones: []string{"kara"}, MinLevel: 120}) {
+		if item.ItemLevel < 120 {
+			t.Errorf("%s is below the minimum item level", item.Name)
+		}
+	}
+	if !containsItem(QueryItems(ItemFilter{Zones: []string{"kara"}}), "Ruby Slippers") {
+		t.Errorf("zones should match ignoring case")
+	}
+}
+
+func containsItem(items []Item, name string) bool {
+	for _, item := range items {
+		if item.Name == name {
+			return true
+		}
+	}
+	return false
+}
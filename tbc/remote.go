@@ -0,0 +1,141 @@
+package tbc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+)
+
+// RemoteRunner runs the iterations of a setup somewhere else, like on worker processes
+// (see package cluster). Once set, every setup the optimizers, stat weights and comparisons
+// sim is sent to it, and setups that fail remotely are simmed here instead.
+type RemoteRunner interface {
+	RunDPS(ctx context.Context, req DPSRequest) ([]float64, error)
+}
+
+var (
+	remoteMu     sync.RWMutex
+	remote       RemoteRunner
+	remoteFailed int32 // set once a failure is logged, so the setups after it don't log it again.
+)
+
+// SetRemoteRunner sends sims to r, nil sims everything in this process again.
+func SetRemoteRunner(r RemoteRunner) {
+	remoteMu.Lock()
+	remote = r
+	atomic.StoreInt32(&remoteFailed, 0)
+	remoteMu.Unlock()
+}
+
+func remoteRunner() RemoteRunner {
+	remoteMu.RLock()
+	defer remoteMu.RUnlock()
+	return remote
+}
+
+// DPSRequest is a setup to sim and how many iterations of it, as sent to a worker.
+// It has everything the result cache key has, the DPS of each iteration is the same wherever it is simmed.
+type DPSRequest struct {
+	Setup      []byte   // gear and options, written by Setup.Pack.
+	SpellOrder []string // the parts of the options Pack leaves out.
+	UseAI      bool
+	RSeed      int64
+	Stats      Stats
+	Inactive   []byte // slots of items with their effect turned off, like the gear optimizer does to measure it.
+	Seconds    int
+	Iterations int
+	Data       string // DataHash of the item data, workers must have the same data loaded.
+}
+
+func newDPSRequest(stats Stats, equip Equipment, opts Options, seconds int, numSims int) DPSRequest {
+	req := DPSRequest{
+		Setup:      Setup{Options: opts, Equip: equip}.Pack(),
+		SpellOrder: opts.SpellOrder,
+		UseAI:      opts.UseAI,
+		RSeed:      opts.RSeed,
+		Stats:      stats,
+		Seconds:    seconds,
+		Iterations: numSims,
+		Data:       DataHash(),
+	}
+	for slot, item := range equip {
+		if item.ID != 0 && item.Activate == nil && ItemsByID[item.ID].Activate != nil {
+			req.Inactive = append(req.Inactive, byte(slot))
+		}
+	}
+	return req
+}
+
+// DataHash identifies the item data loaded, the built in data and any data files.
+func DataHash() string {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.data
+}
+
+// The largest request a worker takes, so one caller can't tie it up for hours.
+const (
+	maxRequestIterations = 1000000
+	maxRequestSeconds    = 3600
+)
+
+// RunDPSRequest sims a request in this process, what a worker does with the requests it gets.
+// Returns the DPS of each iteration.
+func RunDPSRequest(req DPSRequest) ([]float64, error) {
+	if req.Data != DataHash() {
+		return nil, errors.New("the worker has different item data loaded, start it with the same --data files")
+	}
+	if req.Iterations < 0 || req.Iterations > maxRequestIterations || req.Seconds <= 0 || req.Seconds > maxRequestSeconds {
+		return nil, fmt.Errorf("invalid request for %d iterations of %d seconds", req.Iterations, req.Seconds)
+	}
+	if len(req.Stats) < int(StatLen) {
+		return nil, fmt.Errorf("invalid request with %d stats", len(req.Stats))
+	}
+	s, problems, err := UnpackSetup(req.Setup)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid gear: %v", problems.Strings())
+	}
+	opts := s.Options
+	opts.SpellOrder, opts.UseAI, opts.RSeed = req.SpellOrder, req.UseAI, req.RSeed
+	if len(opts.SpellOrder) == 0 && !opts.UseAI {
+		return nil, errors.New("no rotation to sim")
+	}
+	for _, slot := range req.Inactive {
+		if int(slot) < len(s.Equip) {
+			s.Equip[slot].Activate = nil
+		}
+	}
+	r := newSimRunner(req.Stats, s.Equip, opts, req.Seconds)
+	r.local = true
+	return r.first(req.Iterations), nil
+}
+
+// runRemote gets the first n iterations of the runner's setup from the remote runner,
+// false if there is none or it failed.
+func (r *simRunner) runRemote(n int) bool {
+	rr := remoteRunner()
+	if rr == nil || r.local || r.opts.Debug {
+		return false
+	}
+	job := r.opts.Job
+	ctx := context.Background()
+	if job != nil {
+		ctx = job.ctx
+	}
+	dps, err := rr.RunDPS(ctx, newDPSRequest(r.stats, r.equip, r.opts, r.seconds, n))
+	if err != nil || len(dps) != n {
+		if ctx.Err() == nil && atomic.CompareAndSwapInt32(&remoteFailed, 0, 1) {
+			log.Printf("Simming here after a worker failed: %v", err)
+		}
+		return false
+	}
+	job.countIterations(n - len(r.dps))
+	r.dps = dps
+	return true
+}
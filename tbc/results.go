@@ -0,0 +1,155 @@
+package tbc
+
+import (
+	"math"
+	"sort"
+)
+
+// SimResult is the summary of simulating a setup with a single rotation many times.
+// It is the result of the CLI sim command and of the web UI, and the JSON field names
+// are the schema scripts and the UI read: fields can be added but not renamed or removed.
+type SimResult struct {
+	Rotation     []string
+	SimSeconds   int
+	RealDuration float64 // seconds it took to run the sim.
+	Logs         string  // combat log, only filled in by the web UI with full logs on.
+
+	Iterations  int            `json:"iterations"`
+	DPSAvg      float64        `json:"dps"`
+	DPSDev      float64        `json:"dev"`
+	MinDPS      float64        `json:"min"`
+	MaxDPS      float64        `json:"max"`
+	Percentiles DPSPercentiles `json:"percentiles"`
+	DPSHist     map[int]int    `json:"dpsHist"` // DPS rounded to HistogramBucket, to the number of iterations.
+
+	NumOOM   int     `json:"numOOM"`   // iterations that ran out of mana.
+	OOMAt    float64 `json:"oomat"`    // average second mana ran out, of the iterations that did.
+	DPSAtOOM float64 `json:"dpsAtOOM"` // average DPS up to running out of mana, of the iterations that did.
+
+	Casts    map[int32]CastMetric `json:"casts"` // by spell ID, Lightning Overload procs are 1000 - spell ID.
+	Consumed ConsumeMetric        `json:"consumed"`
+}
+
+// HistogramBucket is the width in DPS of each bar of SimResult.DPSHist.
+const HistogramBucket = 10
+
+// DPSPercentiles are percentiles of the DPS of every iteration.
+type DPSPercentiles struct {
+	P5  float64 `json:"p5"`
+	P25 float64 `json:"p25"`
+	P50 float64 `json:"p50"`
+	P75 float64 `json:"p75"`
+	P95 float64 `json:"p95"`
+}
+
+// CastMetric is the total of a single spell over every iteration.
+type CastMetric struct {
+	Name  string  `json:"name"`
+	Count int     `json:"count"`
+	Dmg   float64 `json:"dmg"`
+	Crits int     `json:"crits"`
+}
+
+// ConsumeMetric is the average number of each consumable used per iteration.
+type ConsumeMetric struct {
+	DestructionPotion float64 `json:"destructionPotion"`
+	SuperManaPotion   float64 `json:"superManaPotion"`
+	DarkRune          float64 `json:"darkRune"`
+}
+
+// SummarizeSim runs the sim numSims times, like RunIterations, and summarizes every iteration.
+// DPS is over the report time of the options if it is set, otherwise over the whole fight.
+// Rotation, RealDuration and Logs are left for the caller to fill in.
+// The summary of a new sim that isn't logging is cached, so summarizing the same setup again is free.
+func SummarizeSim(sim *Simulation, seconds int, numSims int) SimResult {
+	key := ""
+	if sim.Debug == nil {
+		p := sim.Options.Precision
+		key = simKey("summary", sim.Stats, sim.Equip, sim.Options, seconds, float64(numSims), p.StdErr, float64(p.MaxIters), float64(p.Batch), float64(sim.RotationIdx))
+		if v, ok := cache.get(key); ok {
+			res := v.(SimResult)
+			sim.Options.Job.countIterations(res.Iterations)
+			return res
+		}
+	}
+	res := summarizeSim(sim, seconds, numSims)
+	if key != "" && !sim.Options.Job.cancelled() {
+		cache.put(key, res)
+	}
+	return res
+}
+
+func summarizeSim(sim *Simulation, seconds int, numSims int) SimResult {
+	res := SimResult{
+		SimSeconds: seconds,
+		DPSHist:    map[int]int{},
+		Casts:      map[int32]CastMetric{},
+	}
+	dps := []float64{}
+	res.Iterations = RunIterations(sim, seconds, numSims, func(metrics SimMetrics) {
+		v := metrics.TotalDamage / float64(seconds)
+		if sim.Options.DPSReportTime > 0 {
+			v = metrics.ReportedDamage / float64(sim.Options.DPSReportTime)
+		}
+		dps = append(dps, v)
+		res.DPSHist[int(math.Round(v/HistogramBucket)*HistogramBucket)]++
+		if metrics.OOMAt > 0 {
+			res.NumOOM++
+			res.OOMAt += float64(metrics.OOMAt)
+			res.DPSAtOOM += metrics.DamageAtOOM / float64(metrics.OOMAt)
+		}
+		for _, cast := range metrics.Casts {
+			id, name := cast.Spell.ID, AuraName(cast.Spell.ID)
+			if cast.IsLO {
+				id, name = 1000-cast.Spell.ID, name+" Overload"
+			}
+			cm := res.Casts[id]
+			cm.Name = name
+			cm.Count++
+			cm.Dmg += cast.DidDmg
+			if cast.DidCrit {
+				cm.Crits++
+			}
+			res.Casts[id] = cm
+		}
+		res.Consumed.DestructionPotion += float64(metrics.Consumed.DestructionPotion)
+		res.Consumed.SuperManaPotion += float64(metrics.Consumed.SuperManaPotion)
+		res.Consumed.DarkRune += float64(metrics.Consumed.DarkRune)
+	})
+
+	if len(dps) == 0 {
+		return res // no iterations were asked for, or the job was cancelled before the first.
+	}
+	n := float64(res.Iterations)
+	res.DPSAvg, res.DPSDev = meanStdev(dps)
+	sort.Float64s(dps)
+	res.MinDPS, res.MaxDPS = dps[0], dps[len(dps)-1]
+	res.Percentiles = DPSPercentiles{
+		P5:  percentile(dps, 0.05),
+		P25: percentile(dps, 0.25),
+		P50: percentile(dps, 0.5),
+		P75: percentile(dps, 0.75),
+		P95: percentile(dps, 0.95),
+	}
+	res.Consumed.DestructionPotion /= n
+	res.Consumed.SuperManaPotion /= n
+	res.Consumed.DarkRune /= n
+	if res.NumOOM > 0 {
+		res.OOMAt /= float64(res.NumOOM)
+		res.DPSAtOOM /= float64(res.NumOOM)
+	}
+	return res
+}
+
+// percentile returns the p (0 to 1) percentile of sorted values, interpolating between the closest two.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := p * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	if lo+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lo] + (pos-float64(lo))*(sorted[lo+1]-sorted[lo])
+}
@@ -0,0 +1,45 @@
+package tbc
+
+import "testing"
+
+func TestPercentile(t *testing.T) {
+	vals := []float64{10, 20, 30, 40, 50}
+	for _, tc := range []struct{ p, want float64 }{{0, 10}, {0.5, 30}, {0.25, 20}, {0.95, 48}, {1, 50}} {
+		if got := percentile(vals, tc.p); got != tc.want {
+			t.Errorf("percentile %0.2f: got %0.2f, want %0.2f", tc.p, got, tc.want)
+		}
+	}
+}
+
+func TestSummarizeSim(t *testing.T) {
+	equip := NewEquipmentSet("Tidefury Helm", "Tidefury Chestpiece", "Gavel of Unearthed Secrets")
+	opts := Options{UseAI: true, Talents: Talents{LightninOverload: 5, Concussion: 5}}
+	res := SummarizeSim(NewSim(CalculateTotalStats(opts, equip), equip, opts), 60, 100)
+
+	if res.Iterations != 100 {
+		t.Fatalf("expected 100 iterations, got %d", res.Iterations)
+	}
+	p := res.Percentiles
+	if !(res.MinDPS <= p.P5 && p.P5 <= p.P25 && p.P25 <= p.P50 && p.P50 <= p.P75 && p.P75 <= p.P95 && p.P95 <= res.MaxDPS) {
+		t.Fatalf("percentiles out of order: min %0.1f %+v max %0.1f", res.MinDPS, p, res.MaxDPS)
+	}
+	total := 0
+	for _, n := range res.DPSHist {
+		total += n
+	}
+	if total != res.Iterations {
+		t.Fatalf("histogram has %d iterations, expected %d", total, res.Iterations)
+	}
+	if res.Casts[MagicIDLB12].Name != "LB12" || res.Casts[1000-MagicIDLB12].Name != "LB12 Overload" {
+		t.Fatalf("unexpected cast names: %+v", res.Casts)
+	}
+}
+
+func TestSummarizeNoIterations(t *testing.T) {
+	equip := NewEquipmentSet("Tidefury Helm")
+	opts := Options{UseAI: true}
+	res := SummarizeSim(NewSim(CalculateTotalStats(opts, equip), equip, opts), 60, 0)
+	if res.Iterations != 0 || res.DPSAvg != 0 || res.MaxDPS != 0 {
+		t.Fatalf("expected an empty result, got %+v", res)
+	}
+}
@@ -0,0 +1,231 @@
+package tbc
+
+import (
+	"math"
+	"runtime"
+	"sync"
+)
+
+// Precision makes the sim run until a target precision is reached instead of a fixed number of iterations.
+// The requested number of iterations is run first, then more batches are added until the standard error
+// of mean DPS (or of the DPS difference when comparing against a base setup) is at most StdErr.
+type Precision struct {
+	StdErr   float64 // target standard error in DPS, 0 disables adaptive runs.
+	MaxIters int     // most iterations to run for a single setup. Defaults to 100,000
+	Batch    int     // iterations to add each time the target isn't met. Defaults to 500
+}
+
+func (p Precision) enabled() bool {
+	return p.StdErr > 0
+}
+
+func (p Precision) maxIters() int {
+	if p.MaxIters <= 0 {
+		return 100000
+	}
+	return p.MaxIters
+}
+
+func (p Precision) batch() int {
+	if p.Batch <= 0 {
+		return 500
+	}
+	return p.Batch
+}
+
+// start is how many iterations to run before checking the precision.
+func (p Precision) start(numSims int) int {
+	if p.enabled() && numSims > p.maxIters() {
+		return p.maxIters()
+	}
+	return numSims
+}
+
+// done reports if vals (per iteration DPS, or DPS differences) are precise enough to stop.
+func (p Precision) done(vals []float64) bool {
+	if !p.enabled() || len(vals) >= p.maxIters() {
+		return true
+	}
+	_, stdev := meanStdev(vals)
+	return stdErr(stdev, len(vals)) <= p.StdErr
+}
+
+// next is how many iterations to run in total after n wasn't precise enough.
+func (p Precision) next(n int) int {
+	n += p.batch()
+	if n > p.maxIters() {
+		n = p.maxIters()
+	}
+	return n
+}
+
+// simRunner incrementally runs a single setup so more iterations can be added later.
+// Iteration N is seeded with the options' seed plus N, so iteration N of two runners with the
+// same seed rolls the same dice, and stays paired no matter how many more either of them runs.
+// Iterations of the same setup and seed simmed before come from the result cache.
+type simRunner struct {
+	mu        sync.Mutex
+	sim       *Simulation
+	stats     Stats
+	equip     Equipment
+	opts      Options // as given, NewSim changes the rotation in the options of the sim.
+	seconds   int
+	precision Precision
+	dps       []float64
+	simmed    int    // iterations the sim has run, fewer than len(dps) when some came from the cache.
+	cached    int    // iterations that came from the cache.
+	counted   int    // of the cached iterations, how many have been counted to the job.
+	key       string // result cache key, "" to not cache.
+	local     bool   // sim here even with a remote runner, set on workers.
+}
+
+func newSimRunner(stats Stats, equip Equipment, opts Options, seconds int) *simRunner {
+	r := &simRunner{sim: NewSim(stats, equip, opts), stats: stats, equip: equip, opts: opts, seconds: seconds, precision: opts.Precision}
+	if !opts.Debug {
+		r.key = simKey("dps", stats, equip, opts, seconds)
+		r.dps = cache.dps(r.key)
+		r.cached = len(r.dps)
+	}
+	return r
+}
+
+// first returns the DPS of the first n iterations, running more iterations if needed.
+func (r *simRunner) first(n int) []float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	served := r.cached
+	if n < served {
+		served = n
+	}
+	if served > r.counted {
+		r.sim.Options.Job.countIterations(served - r.counted)
+		r.counted = served
+	}
+	if len(r.dps) >= n {
+		return r.dps[:n]
+	}
+	if r.runRemote(n) {
+		if r.key != "" {
+			cache.putDPS(r.key, r.dps)
+		}
+		return r.dps[:n]
+	}
+	// Every iteration is seeded on its own, so the sim can skip the ones from the cache.
+	r.sim.iteration = int64(len(r.dps))
+	for len(r.dps) < n {
+		metrics := r.sim.Run(r.seconds)
+		r.simmed++
+		r.dps = append(r.dps, metrics.TotalDamage/float64(r.seconds))
+	}
+	if r.key != "" && !r.sim.Options.Job.cancelled() {
+		cache.putDPS(r.key, r.dps)
+	}
+	return r.dps[:n]
+}
+
+// untilPrecise runs at least numSims iterations, then keeps adding batches until the
+// standard error is below the precision target. If base is set the standard error is of
+// the paired difference to base. Returns how many iterations were used.
+func (r *simRunner) untilPrecise(numSims int, base *simRunner) int {
+	p := r.precision
+	n := p.start(numSims)
+	for {
+		vals := r.first(n)
+		if base != nil && p.enabled() {
+			vals, _, _ = pairedDiffs(vals, base.first(n))
+		}
+		if p.done(vals) || r.sim.Options.Job.cancelled() {
+			return n
+		}
+		n = p.next(n)
+	}
+}
+
+// runDPS runs numSims iterations of a single setup and returns the DPS of each iteration.
+// With a precision target in the options, it keeps running until the mean DPS is precise enough.
+func runDPS(stats Stats, equip Equipment, opts Options, seconds int, numSims int) []float64 {
+	r := newSimRunner(stats, equip, opts, seconds)
+	return r.first(r.untilPrecise(numSims, nil))
+}
+
+// runPairedDPS runs a setup paired with base, both using the same seed.
+// With a precision target in the options, it keeps running until the DPS difference is precise enough.
+// Returns the DPS of the setup and of base for the same iterations.
+func runPairedDPS(base *simRunner, stats Stats, equip Equipment, opts Options, seconds int, numSims int) ([]float64, []float64) {
+	r := newSimRunner(stats, equip, opts, seconds)
+	n := r.untilPrecise(numSims, base)
+	return r.first(n), base.first(n)
+}
+
+// RunIterations runs the sim numSims times, or with a precision target in the sim options until
+// mean DPS is precise enough, calling each with the metrics of every iteration.
+// Returns how many iterations were run.
+func RunIterations(sim *Simulation, seconds int, numSims int, each func(SimMetrics)) int {
+	p := sim.Options.Precision
+	dps := []float64{}
+	n := p.start(numSims)
+	for {
+		for len(dps) < n {
+			metrics := sim.Run(seconds)
+			dps = append(dps, metrics.TotalDamage/float64(seconds))
+			each(metrics)
+		}
+		if p.done(dps) || sim.Options.Job.cancelled() {
+			return n
+		}
+		n = p.next(n)
+	}
+}
+
+// parallel calls fn for 0 to n-1 on at most one goroutine per CPU and waits for all of them.
+func parallel(n int, fn func(i int)) {
+	workers := runtime.NumCPU()
+	if workers > n {
+		workers = n
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
+
+// pairedDiffs returns a[i] - b[i] and the mean and standard error of the difference.
+func pairedDiffs(a, b []float64) ([]float64, float64, float64) {
+	diffs := make([]float64, len(a))
+	for i := range a {
+		diffs[i] = a[i] - b[i]
+	}
+	mean, stdev := meanStdev(diffs)
+	return diffs, mean, stdErr(stdev, len(diffs))
+}
+
+// meanStdev returns the mean and (population) standard deviation of vals.
+func meanStdev(vals []float64) (float64, float64) {
+	if len(vals) == 0 {
+		return 0, 0
+	}
+	total := 0.0
+	totalSq := 0.0
+	for _, v := range vals {
+		total += v
+		totalSq += v * v
+	}
+	mean := total / float64(len(vals))
+	variance := totalSq/float64(len(vals)) - mean*mean
+	if variance < 0 {
+		variance = 0 // floating point noise
+	}
+	return mean, math.Sqrt(variance)
+}
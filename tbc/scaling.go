@@ -0,0 +1,157 @@
+package tbc
+
+import (
+	"sort"
+)
+
+// HitCapRating is the total hit rating where spells reach the 99% hit cap in Simulation.Cast.
+const HitCapRating = (0.99 - 0.83) * 1260
+
+// StatSweep is a range of a single stat to add on top of the current stats.
+type StatSweep struct {
+	Stat Stat
+	From float64
+	To   float64
+	Step float64 // defaults to a tenth of the range.
+}
+
+func (ss StatSweep) values() []float64 {
+	step := ss.Step
+	if step <= 0 {
+		step = (ss.To - ss.From) / 10
+	}
+	if step <= 0 {
+		return []float64{ss.From}
+	}
+	vals := []float64{}
+	for v := ss.From; v <= ss.To+step/1000; v += step {
+		vals = append(vals, v)
+	}
+	return vals
+}
+
+// ScalingOptions configures StatScaling.
+type ScalingOptions struct {
+	X StatSweep
+	Y *StatSweep // optional second stat, sweeping both makes a grid.
+
+	// A point is marked as collapsed when the marginal DPS per point of X drops
+	// below this fraction of the first marginal value in its row. Defaults to 0.25
+	CollapseRatio float64
+}
+
+// ScalingPoint is the DPS at one point of the sweep.
+type ScalingPoint struct {
+	X     float64 // amount of the X stat added
+	Y     float64 // amount of the Y stat added, 0 if only sweeping one stat.
+	DPS   float64
+	Stdev float64
+
+	Marginal  float64 // DPS gained per point of X since the previous point in the row.
+	Collapsed bool    // the marginal value has collapsed, for example past the hit cap.
+
+	Iterations int // iterations simulated, can vary with a precision target.
+}
+
+// ScalingResult is the curve (or grid) of DPS across the sweep.
+type ScalingResult struct {
+	XStat Stat
+	YStat Stat // StatLen if only one stat was swept.
+
+	// HitCapAt is how much hit rating needs to be added to reach the hit cap,
+	// set only when sweeping hit. It is negative if already past the cap.
+	HitCapAt *float64 `json:",omitempty"`
+	// Lightning Overload procs don't get the talent hit, so they cap later than the casts that trigger them.
+	OverloadHitCapAt *float64 `json:",omitempty"`
+
+	Points []ScalingPoint // ordered by Y then X.
+}
+
+// StatScaling simulates DPS across a range of a stat (or a grid of two stats).
+// Every point uses the same random seed so the differences between points are from the stats.
+func StatScaling(opts Options, equip Equipment, seconds int, numSims int, sopts ScalingOptions) ScalingResult {
+	opts.UseAI = true
+	if sopts.CollapseRatio <= 0 {
+		sopts.CollapseRatio = 0.25
+	}
+	output := ScalingResult{XStat: sopts.X.Stat, YStat: StatLen}
+	xs := sopts.X.values()
+	ys := []float64{0}
+	if sopts.Y != nil {
+		output.YStat = sopts.Y.Stat
+		ys = sopts.Y.values()
+	}
+
+	opts.Job.Phase("sweep", phaseTotal(opts, len(xs)*len(ys), numSims))
+	output.Points = make([]ScalingPoint, 0, len(xs)*len(ys))
+	for _, y := range ys {
+		for _, x := range xs {
+			output.Points = append(output.Points, ScalingPoint{X: x, Y: y})
+		}
+	}
+	parallel(len(output.Points), func(i int) {
+		p := &output.Points[i]
+		popts := opts
+		popts.Buffs.Custom = Stats{StatLen: 0}
+		copy(popts.Buffs.Custom, opts.Buffs.Custom) // clone existing buff
+		popts.Buffs.Custom[sopts.X.Stat] += p.X
+		if sopts.Y != nil {
+			popts.Buffs.Custom[sopts.Y.Stat] += p.Y
+		}
+		dps := runDPS(CalculateTotalStats(popts, equip), equip, popts, seconds, numSims)
+		p.DPS, p.Stdev = meanStdev(dps)
+		p.Iterations = len(dps)
+	})
+
+	for row := 0; row < len(ys); row++ {
+		markCollapse(output.Points[row*len(xs):(row+1)*len(xs)], sopts.CollapseRatio)
+	}
+
+	hitStat := sopts.X.Stat == StatSpellHit || (sopts.Y != nil && sopts.Y.Stat == StatSpellHit)
+	if hitStat {
+		capAt := HitCapRating - CalculateTotalStats(opts, equip)[StatSpellHit] - setBonusHit(opts, equip)
+		output.HitCapAt = &capAt
+		if opts.Talents.LightninOverload > 0 {
+			talentHit := (0.02*float64(opts.Talents.ElementalPrecision) + 0.01*float64(opts.Talents.NaturesGuidance)) * 1260
+			overloadAt := capAt + talentHit
+			output.OverloadHitCapAt = &overloadAt
+		}
+	}
+	return output
+}
+
+// setBonusHit is the hit rating from permanent effects like set bonuses, which only show up once the sim activates them.
+func setBonusHit(opts Options, equip Equipment) float64 {
+	sim := NewSim(CalculateTotalStats(opts, equip), equip, opts)
+	sim.ActivateSets()
+	return sim.Buffs[StatSpellHit]
+}
+
+// markCollapse fills in the marginal value of each point in a row and marks where it collapses.
+func markCollapse(row []ScalingPoint, ratio float64) {
+	first := 0.0
+	for i := 1; i < len(row); i++ {
+		row[i].Marginal = (row[i].DPS - row[i-1].DPS) / (row[i].X - row[i-1].X)
+		if first == 0 && row[i].Marginal > 0 {
+			first = row[i].Marginal
+			continue
+		}
+		row[i].Collapsed = first > 0 && row[i].Marginal < first*ratio
+	}
+}
+
+// Collapses returns the first point of each row where the marginal value collapsed.
+func (sr ScalingResult) Collapses() []ScalingPoint {
+	found := map[float64]ScalingPoint{}
+	for _, p := range sr.Points {
+		if _, ok := found[p.Y]; !ok && p.Collapsed {
+			found[p.Y] = p
+		}
+	}
+	points := make([]ScalingPoint, 0, len(found))
+	for _, p := range found {
+		points = append(points, p)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Y < points[j].Y })
+	return points
+}
@@ -0,0 +1,22 @@
+package tbc
+
+import "testing"
+
+func TestMarkCollapse(t *testing.T) {
+	row := []ScalingPoint{
+		{X: 0, DPS: 1000},
+		{X: 10, DPS: 1010},
+		{X: 20, DPS: 1020},
+		{X: 30, DPS: 1021},
+		{X: 40, DPS: 1021},
+	}
+	markCollapse(row, 0.25)
+	for i, collapsed := range []bool{false, false, false, true, true} {
+		if row[i].Collapsed != collapsed {
+			t.Fatalf("point %d: expected collapsed=%v, marginal %0.2f", i, collapsed, row[i].Marginal)
+		}
+	}
+	if row[1].Marginal != 1 {
+		t.Fatalf("expected marginal of 1 DPS per point, got %0.2f", row[1].Marginal)
+	}
+}
@@ -3,6 +3,7 @@ package tbc
 import (
 	"fmt"
 	"math/rand"
+	"sort"
 	"strconv"
 )
 
@@ -21,12 +22,15 @@ type Simulation struct {
 	Buffs       Stats     // temp increases
 	Equip       Equipment // Current Gear
 	activeEquip Equipment // cache of gear that can activate.
+	activeGems  []Gem     // cache of socketed gems that activate an aura.
 
-	bloodlustCasts    int
-	destructionPotion bool
-	Options           Options
-	SpellRotation     []*Spell
-	RotationIdx       int
+	bloodlustCasts   int
+	cdUses           map[int32]int // number of times each cooldown has been used this run.
+	trinketStaggerAt int           // tick the next trinket is allowed when staggering trinkets.
+	clJump           float64       // share of its damage Chain Lightning keeps on each jump.
+	Options          Options
+	SpellRotation    []*Spell
+	RotationIdx      int
 
 	// ticks until cast is complete
 	CastingSpell *Cast
@@ -40,6 +44,7 @@ type Simulation struct {
 
 	rando       *rand.Rand
 	rseed       int64
+	iteration   int64 // runs so far, each run's dice are seeded with rseed+iteration.
 	CurrentTick int
 	endTick     int
 
@@ -54,6 +59,28 @@ type SimMetrics struct {
 	Casts          []*Cast
 	ManaAtEnd      int
 	Rotation       []string
+	Consumed       ConsumeMetrics
+}
+
+// CheckRotation returns an error if a fixed rotation is empty or has a spell the sim doesn't know.
+// A rotation starting with "pri" is a priority list.
+func CheckRotation(rot []string) error {
+	if len(rot) > 0 && rot[0] == "pri" {
+		rot = rot[1:]
+	}
+	if len(rot) == 0 {
+		return fmt.Errorf("rotation has no spells")
+	}
+	for _, name := range rot {
+		known := false
+		for _, sp := range spells {
+			known = known || sp.Name == name
+		}
+		if !known {
+			return fmt.Errorf("unknown spell %q in rotation", name)
+		}
+	}
+	return nil
 }
 
 // New sim contructs a simulator with the given stats / equipment / options.
@@ -105,13 +132,29 @@ func NewSim(stats Stats, equip Equipment, options Options) *Simulation {
 		sim.Debug = debugFunc(sim)
 	}
 
+	metaActive := equip.MetaActive()
 	for _, eq := range equip {
 		if eq.Activate != nil {
 			sim.activeEquip = append(sim.activeEquip, eq)
 		}
 		for _, g := range eq.Gems {
-			if g.Activate != nil {
-				sim.activeEquip = append(sim.activeEquip, eq)
+			if g.Activate == nil {
+				continue
+			}
+			if g.Color == GemColorMeta && !metaActive {
+				continue // meta requirements not met, the gem does nothing.
+			}
+			sim.activeGems = append(sim.activeGems, g)
+		}
+	}
+
+	// Put the preferred trinket first so it wins the shared trinket cooldown.
+	if first := options.Cooldowns.TrinketFirst; first != 0 {
+		for i, eq := range sim.activeEquip {
+			if eq.ID == first {
+				copy(sim.activeEquip[1:i+1], sim.activeEquip[:i])
+				sim.activeEquip[0] = eq
+				break
 			}
 		}
 	}
@@ -122,10 +165,15 @@ func NewSim(stats Stats, equip Equipment, options Options) *Simulation {
 // This is automatically called before every 'Run'
 //  This includes resetting and reactivating always on trinkets, auras, set bonuses, etc
 func (sim *Simulation) reset() {
-	// sim.rseed++
-	// sim.rando.Seed(sim.rseed)
+	// Reseed every run so run N of any setup with the same seed rolls the same dice,
+	// even if an earlier run of it rolled more or fewer times.
+	sim.rando.Seed(sim.rseed + sim.iteration)
+	sim.iteration++
 
 	sim.bloodlustCasts = 0
+	sim.cdUses = map[int32]int{}
+	sim.trinketStaggerAt = 0
+	sim.clJump = 0.7
 	sim.CurrentTick = 0
 	sim.CurrentMana = sim.Stats[StatMana]
 	sim.CastingSpell = nil
@@ -154,15 +202,17 @@ func (sim *Simulation) reset() {
 		if item.Activate != nil && item.ActivateCD == -1 {
 			sim.addAura(item.Activate(sim))
 		}
-		for _, g := range item.Gems {
-			if g.Activate != nil {
-				sim.addAura(g.Activate(sim))
-			}
-		}
+	}
+	for _, g := range sim.activeGems {
+		sim.addAura(g.Activate(sim))
 	}
 
 	sim.ActivateSets()
 
+	if sim.Options.Consumes.DestructionPotion && sim.Options.Consumes.Policies.DestructionPotion.PrePot {
+		sim.prePot()
+	}
+
 	if sim.Options.UseAI {
 		// Reset a new AI
 		// TODO: Can we take learnings from the last AI to modulate this AIs behavior?
@@ -173,11 +223,18 @@ func (sim *Simulation) reset() {
 
 // Run will run the simulation for number of seconds.
 // Returns metrics for what was cast and how much damage was done.
+// If the job in the options is cancelled it stops early, with whatever metrics it has so far.
 func (sim *Simulation) Run(seconds int) SimMetrics {
+	if sim.Options.Job.cancelled() {
+		return SimMetrics{}
+	}
 	sim.endTick = seconds * TicksPerSecond
 	sim.reset()
 
 	for i := 0; i < sim.endTick; {
+		if sim.Options.Job.cancelled() {
+			return sim.metrics
+		}
 		if sim.CurrentMana < 0 {
 			panic("you should never have negative mana.")
 		}
@@ -186,6 +243,7 @@ func (sim *Simulation) Run(seconds int) SimMetrics {
 		advance := sim.Spellcasting(i)
 
 		if sim.Options.ExitOnOOM && sim.metrics.OOMAt > 0 {
+			sim.Options.Job.iterationDone()
 			return sim.metrics
 		}
 
@@ -193,6 +251,7 @@ func (sim *Simulation) Run(seconds int) SimMetrics {
 		i += advance
 	}
 	sim.metrics.ManaAtEnd = int(sim.CurrentMana)
+	sim.Options.Job.iterationDone()
 
 	return sim.metrics
 }
@@ -329,6 +388,19 @@ func (sim *Simulation) Cast(cast *Cast) {
 				}
 			}
 		}
+		if cast.Spell.ID == MagicIDCL6 && sim.Options.Targets > 1 {
+			// The jumps share the first target's rolls.
+			jumps, hit := sim.Options.Targets-1, 1.0
+			if jumps > 2 {
+				jumps = 2
+			}
+			total := 1.0
+			for j := 0; j < jumps; j++ {
+				hit *= sim.clJump
+				total += hit
+			}
+			dmg *= total
+		}
 		cast.DidDmg = dmg
 		// Apply any effects specific to this cast.
 		for _, eff := range cast.Effects {
@@ -373,7 +445,7 @@ func (sim *Simulation) ActivateRacial() {
 	case RaceBonusOrc:
 		const spBonus = 143
 		const dur = 15
-		if sim.CDs[MagicIDOrcBloodFury] < 1 {
+		if sim.CDs[MagicIDOrcBloodFury] < 1 && sim.useCooldown(MagicIDOrcBloodFury, 120) {
 			sim.Buffs[StatSpellDmg] += spBonus
 			sim.addAura(AuraStatRemoval(sim.CurrentTick, dur, spBonus, StatSpellDmg, MagicIDOrcBloodFury))
 			sim.CDs[MagicIDOrcBloodFury] = 120 * TicksPerSecond
@@ -384,7 +456,7 @@ func (sim *Simulation) ActivateRacial() {
 		if v == RaceBonusTroll30 {
 			hasteBonus = 1.3 // 30% haste
 		}
-		if sim.CDs[MagicIDTrollBerserking] < 1 {
+		if sim.CDs[MagicIDTrollBerserking] < 1 && sim.useCooldown(MagicIDTrollBerserking, 180) {
 			sim.addAura(ActivateBerserking(sim, hasteBonus))
 		}
 	}
@@ -395,17 +467,16 @@ func (sim *Simulation) ActivateSets() []string {
 	active := []string{}
 	// Activate Set Bonuses
 	for _, set := range sets {
-		itemCount := 0
-		for _, i := range sim.Equip {
-			if set.Items[i.Name] {
-				itemCount++
-				if bonus, ok := set.Bonuses[itemCount]; ok {
-					active = append(active, set.Name+" ("+strconv.Itoa(itemCount)+"pc)")
-					sim.addAura(bonus(sim))
-				}
+		count := set.Count(sim.Equip)
+		for _, pieces := range set.Thresholds() {
+			if count < pieces {
+				break
 			}
+			active = append(active, set.Name+" ("+strconv.Itoa(pieces)+"pc)")
+			sim.addAura(set.Bonuses[pieces](sim))
 		}
 	}
+	sort.Strings(active)
 	return active
 }
 
@@ -421,7 +492,13 @@ func (sim *Simulation) Spellcasting(tickID int) int {
 	}
 
 	if sim.CastingSpell == nil {
-		if sim.Options.NumDrums > 0 && sim.CDs[MagicIDDrums] < 1 {
+		// Bloodlust goes first so anything aligned with it can be used on the same tick.
+		if sim.useBloodlust() {
+			sim.addAura(ActivateBloodlust(sim))
+			sim.bloodlustCasts++ // TODO: will this break anything?
+		}
+
+		if sim.Options.NumDrums > 0 && sim.CDs[MagicIDDrums] < 1 && sim.useCooldown(MagicIDDrums, 30) {
 			// We have drums in the sim, and the drums aura isn't turned on.
 			// Iterate our drum
 			for i, v := range []int32{MagicIDDrum1, MagicIDDrum2, MagicIDDrum3, MagicIDDrum4} {
@@ -435,48 +512,15 @@ func (sim *Simulation) Spellcasting(tickID int) int {
 				}
 			}
 		}
-		// Activate any specials
-		if sim.Options.NumBloodlust > sim.bloodlustCasts && sim.CDs[MagicIDBloodlust] < 1 {
-			sim.addAura(ActivateBloodlust(sim))
-			sim.bloodlustCasts++ // TODO: will this break anything?
-		}
 
-		if sim.Options.Talents.ElementalMastery && sim.CDs[MagicIDEleMastery] < 1 {
+		if sim.Options.Talents.ElementalMastery && sim.CDs[MagicIDEleMastery] < 1 && !sim.hasAura(MagicIDEleMastery) && sim.useCooldown(MagicIDEleMastery, 180) {
 			// Apply auras
 			sim.addAura(AuraEleMastery())
 		}
 
 		sim.ActivateRacial()
 
-		if sim.Options.Consumes.DestructionPotion && sim.CDs[MagicIDPotion] < 1 {
-			// Only use dest potion if not using mana or if we haven't used it once.
-			// If we are using mana, only use destruction potion on the pull.
-			if !sim.Options.Consumes.SuperManaPotion || !sim.destructionPotion {
-				sim.addAura(ActivateDestructionPotion(sim))
-			}
-		}
-
-		didPot := false
-		totalRegen := (sim.Stats[StatMP5] + sim.Buffs[StatMP5])
-		// Pop potion before next cast if we have less than the mana provided by the potion minues 1mp5 tick.
-		if sim.Options.Consumes.DarkRune && sim.Stats[StatMana]-sim.CurrentMana+totalRegen >= 1500 && sim.CDs[MagicIDRune] < 1 {
-			// Restores 900 to 1500 mana. (2 Min Cooldown)
-			sim.CurrentMana += 900 + (sim.rando.Float64() * 600)
-			sim.CDs[MagicIDRune] = 120 * TicksPerSecond
-			didPot = true
-			if sim.Debug != nil {
-				sim.Debug("Used Dark Rune\n")
-			}
-		}
-		if sim.Options.Consumes.SuperManaPotion && sim.Stats[StatMana]-sim.CurrentMana+totalRegen >= 3000 && sim.CDs[MagicIDPotion] < 1 {
-			// Restores 1800 to 3000 mana. (2 Min Cooldown)
-			sim.CurrentMana += 1800 + (sim.rando.Float64() * 1200)
-			sim.CDs[MagicIDPotion] = 120 * TicksPerSecond
-			didPot = true
-			if sim.Debug != nil {
-				sim.Debug("Used Mana Potion\n")
-			}
-		}
+		didPot := sim.useConsumes()
 
 		// Pop any on-use trinkets
 		for _, item := range sim.activeEquip {
@@ -486,7 +530,7 @@ func (sim *Simulation) Spellcasting(tickID int) int {
 			if sim.CDs[item.CoolID] > 0 {
 				continue
 			}
-			if item.Slot == EquipTrinket && sim.CDs[MagicIDAllTrinket] > 0 {
+			if item.Slot == EquipTrinket && (sim.CDs[MagicIDAllTrinket] > 0 || !sim.useTrinket(item)) {
 				continue
 			}
 			sim.addAura(item.Activate(sim))
@@ -2,6 +2,7 @@ package tbc
 
 import (
 	"strconv"
+	"strings"
 )
 
 const TicksPerSecond = 60
@@ -52,6 +53,36 @@ func (s Stat) StatName() string {
 	return "none"
 }
 
+// statAliases are the short names accepted by ParseStat.
+var statAliases = map[string]Stat{
+	"int":    StatInt,
+	"stm":    StatStm,
+	"crit":   StatSpellCrit,
+	"hit":    StatSpellHit,
+	"sp":     StatSpellDmg,
+	"dmg":    StatSpellDmg,
+	"haste":  StatHaste,
+	"mp5":    StatMP5,
+	"mana":   StatMana,
+	"pen":    StatSpellPen,
+	"spirit": StatSpirit,
+}
+
+// ParseStat converts either a StatName or a short name like "hit" back to the stat.
+// Returns false if the name doesn't match any stat.
+func ParseStat(name string) (Stat, bool) {
+	name = strings.ToLower(name)
+	if s, ok := statAliases[name]; ok {
+		return s, true
+	}
+	for s := StatInt; s < StatLen; s++ {
+		if strings.ToLower(s.StatName()) == name {
+			return s, true
+		}
+	}
+	return StatLen, false
+}
+
 func (st Stats) Clone() Stats {
 	ns := make(Stats, StatLen)
 	for i, v := range st {
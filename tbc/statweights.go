@@ -0,0 +1,193 @@
+package tbc
+
+import (
+	"math"
+)
+
+// StatWeightOptions configures how stat weights are calculated.
+type StatWeightOptions struct {
+	Stats []Stat // stats to weigh. Defaults to int, spell power, crit, hit, haste and mp5.
+
+	Delta  float64          // how much of each stat to add. Defaults to 50.
+	Deltas map[Stat]float64 // per stat override of Delta, for example a smaller hit delta near the hit cap.
+
+	// Central also sims each stat reduced by its delta and uses the central difference,
+	// which cancels out most of the curvature of the DPS curve around the current stats.
+	Central bool
+}
+
+// StatWeight is the value of a single stat.
+type StatWeight struct {
+	Stat  Stat
+	Delta float64
+
+	DPS    float64 // DPS gained per point of the stat.
+	DPSErr float64 // standard error of DPS.
+
+	Weight    float64 // DPS normalized to spell power.
+	WeightErr float64 // standard error of Weight.
+}
+
+// StatWeightsResult is the full output of CalculateStatWeights.
+type StatWeightsResult struct {
+	BaseDPS    float64
+	BaseStdev  float64
+	Iterations int
+
+	Weights []StatWeight // in the order of StatWeightOptions.Stats
+}
+
+// Vector returns the weights indexed by stat, the format used by the optimizers.
+func (r StatWeightsResult) Vector() []float64 {
+	output := make([]float64, StatMP5+1) // one more than MP5
+	for _, w := range r.Weights {
+		if int(w.Stat) < len(output) {
+			output[w.Stat] = w.Weight
+		}
+	}
+	return output
+}
+
+var defaultWeightStats = []Stat{StatInt, StatSpellDmg, StatSpellCrit, StatSpellHit, StatHaste, StatMP5}
+
+func (wo StatWeightOptions) delta(s Stat) float64 {
+	if d, ok := wo.Deltas[s]; ok && d > 0 {
+		return d
+	}
+	if wo.Delta > 0 {
+		return wo.Delta
+	}
+	return 50
+}
+
+// CalculateStatWeights finds how much DPS each stat is worth.
+//
+// Every variant is simmed with the same random seed (common random numbers), so iteration N of each variant
+// sees the same dice as iteration N of the base. Weights are computed from the per-iteration differences
+// which makes their standard error much smaller than comparing two independent means.
+func CalculateStatWeights(opts Options, equip Equipment, seconds int, numSims int, wopts StatWeightOptions) StatWeightsResult {
+	opts.UseAI = true
+	if len(wopts.Stats) == 0 {
+		wopts.Stats = defaultWeightStats
+	}
+
+	type variant struct {
+		stat  int // index into wopts.Stats
+		sign  float64
+		delta float64
+	}
+	baseRunner := newSimRunner(CalculateTotalStats(opts, equip), equip, opts, seconds)
+	variants := []variant{}
+	for i, s := range wopts.Stats {
+		variants = append(variants, variant{stat: i, sign: 1, delta: wopts.delta(s)})
+		if wopts.Central {
+			variants = append(variants, variant{stat: i, sign: -1, delta: wopts.delta(s)})
+		}
+	}
+	opts.Job.Phase("stat weights", phaseTotal(opts, len(variants)+1, numSims))
+
+	// The base runs alongside the variants, as the last one.
+	runners := make([]*simRunner, len(variants))
+	needed := make([]int, len(variants)+1)
+	parallel(len(variants)+1, func(i int) {
+		if i == len(variants) {
+			needed[i] = baseRunner.untilPrecise(numSims, nil)
+			return
+		}
+		v := variants[i]
+		vopts := opts
+		vopts.Buffs.Custom = Stats{StatLen: 0}
+		copy(vopts.Buffs.Custom, opts.Buffs.Custom) // clone existing buff
+		vopts.Buffs.Custom[wopts.Stats[v.stat]] += v.sign * v.delta
+		runners[i] = newSimRunner(CalculateTotalStats(vopts, equip), equip, vopts, seconds)
+		needed[i] = runners[i].untilPrecise(numSims, baseRunner)
+	})
+
+	// With a precision target each variant can need a different number of iterations.
+	// They are all prefixes of the same seeded runs, so extend them all to the longest to keep them paired.
+	iters := 0
+	for _, n := range needed {
+		if n > iters {
+			iters = n
+		}
+	}
+	base := baseRunner.first(iters)
+	plus := make([][]float64, len(wopts.Stats))
+	minus := make([][]float64, len(wopts.Stats))
+	for i, v := range variants {
+		if v.sign > 0 {
+			plus[v.stat] = runners[i].first(iters)
+		} else {
+			minus[v.stat] = runners[i].first(iters)
+		}
+	}
+
+	output := StatWeightsResult{Iterations: iters, Weights: make([]StatWeight, len(wopts.Stats))}
+	output.BaseDPS, output.BaseStdev = meanStdev(base)
+
+	// Per iteration DPS gained per point of each stat.
+	perPoint := make([][]float64, len(wopts.Stats))
+	for i, s := range wopts.Stats {
+		delta := wopts.delta(s)
+		lower, span := base, delta
+		if wopts.Central {
+			lower, span = minus[i], 2*delta
+		}
+		perPoint[i] = make([]float64, len(base))
+		for n := range base {
+			perPoint[i][n] = (plus[i][n] - lower[n]) / span
+		}
+		mean, stdev := meanStdev(perPoint[i])
+		output.Weights[i] = StatWeight{Stat: s, Delta: delta, DPS: mean, DPSErr: stdErr(stdev, len(base))}
+	}
+
+	// Normalize to spell power if it was weighed.
+	sp := -1
+	for i, s := range wopts.Stats {
+		if s == StatSpellDmg {
+			sp = i
+		}
+	}
+	if sp == -1 || output.Weights[sp].DPS == 0 {
+		return output
+	}
+	for i := range output.Weights {
+		w := &output.Weights[i]
+		w.Weight, w.WeightErr = ratioStdErr(perPoint[i], perPoint[sp])
+	}
+	return output
+}
+
+// StatWeights returns the weight of each stat normalized to spell power, indexed by stat.
+func StatWeights(opts Options, equip Equipment, seconds int, numSims int) []float64 {
+	return CalculateStatWeights(opts, equip, seconds, numSims, StatWeightOptions{}).Vector()
+}
+
+func stdErr(stdev float64, n int) float64 {
+	if n < 2 {
+		return 0
+	}
+	return stdev / math.Sqrt(float64(n-1))
+}
+
+// ratioStdErr returns mean(a)/mean(b) and its standard error using the delta method.
+// a and b are paired samples so their covariance is included.
+func ratioStdErr(a, b []float64) (float64, float64) {
+	n := len(a)
+	ma, sa := meanStdev(a)
+	mb, sb := meanStdev(b)
+	if mb == 0 || n < 2 {
+		return 0, 0
+	}
+	cov := 0.0
+	for i := range a {
+		cov += (a[i] - ma) * (b[i] - mb)
+	}
+	cov /= float64(n)
+	r := ma / mb
+	variance := (sa*sa - 2*r*cov + r*r*sb*sb) / (mb * mb) / float64(n-1)
+	if variance < 0 {
+		variance = 0
+	}
+	return r, math.Sqrt(variance)
+}
@@ -0,0 +1,45 @@
+package tbc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRatioStdErr(t *testing.T) {
+	// a is exactly twice b, so the ratio has no error at all.
+	b := []float64{1, 2, 3, 4, 5}
+	a := []float64{2, 4, 6, 8, 10}
+	r, err := ratioStdErr(a, b)
+	if math.Abs(r-2) > 1e-9 || err > 1e-6 {
+		t.Fatalf("expected ratio 2 with no error, got %0.3f +/- %0.3f", r, err)
+	}
+
+	// independent noise should show up in the error.
+	a = []float64{3, 3, 7, 7, 10}
+	r, err = ratioStdErr(a, b)
+	if math.Abs(r-2) > 1e-9 || err <= 0 {
+		t.Fatalf("expected ratio 2 with some error, got %0.3f +/- %0.3f", r, err)
+	}
+}
+
+// TestPairedIterations checks a variant that rolls the dice a different number of times stays paired with the base.
+func TestPairedIterations(t *testing.T) {
+	equip := NewEquipmentSet("Tidefury Helm", "Tidefury Chestpiece", "Gavel of Unearthed Secrets")
+	opts := Options{UseAI: true, RSeed: 7, Talents: Talents{Concussion: 5, CallOfThunder: 5, LightninOverload: 5}}
+	stats := CalculateTotalStats(opts, equip)
+	crit := CalculateTotalStats(opts, equip)
+	crit[StatSpellCrit] += 150
+
+	const n = 1500
+	base := newSimRunner(stats, equip, opts, 60).first(n)
+	plus := newSimRunner(crit, equip, opts, 60).first(n)
+	mb, sb := meanStdev(base)
+	mp, sp := meanStdev(plus)
+	cov := 0.0
+	for i := range base {
+		cov += (base[i] - mb) * (plus[i] - mp)
+	}
+	if corr := cov / n / (sb * sp); corr < 0.5 {
+		t.Fatalf("expected iterations with more crit to stay correlated with the base, got %0.2f", corr)
+	}
+}
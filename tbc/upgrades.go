@@ -0,0 +1,221 @@
+package tbc
+
+import (
+	"sort"
+)
+
+// UpgradeOptions configures FindUpgrades.
+type UpgradeOptions struct {
+	ItemFilter
+
+	Slots []byte // equipment slots to check, defaults to every slot. Use EquipFinger/EquipTrinket for both rings/trinkets.
+	TopN  int    // upgrades to return per slot, defaults to 5.
+
+	// Stat weights used to gem candidates, normalized to spell power.
+	// If not set, they are calculated with StatWeights first.
+	Weights []float64
+}
+
+// Upgrade is a single item simulated in place of the current gear.
+type Upgrade struct {
+	Item Item // gemmed and enchanted as it was simulated
+	Slot byte // equipment index the item replaced
+
+	DPS      float64
+	Delta    float64 // DPS gained over the current gear
+	DeltaErr float64 // standard error of Delta, from paired iterations.
+
+	Iterations int // iterations simulated, can vary with a precision target.
+
+	SetsGained []string `json:",omitempty"`
+	SetsLost   []string `json:",omitempty"`
+}
+
+// Conf90 is the 90% confidence interval of the DPS gain.
+func (u Upgrade) Conf90() (float64, float64) {
+	return u.Delta - 1.645*u.DeltaErr, u.Delta + 1.645*u.DeltaErr
+}
+
+// SlotUpgrades is the ranked upgrades for a single slot.
+type SlotUpgrades struct {
+	Slot     byte // item slot, EquipFinger and EquipTrinket cover both equipment slots.
+	Current  []Item
+	Upgrades []Upgrade // best first
+}
+
+// UpgradeResult is the output of FindUpgrades.
+type UpgradeResult struct {
+	BaseDPS float64
+	Slots   []SlotUpgrades
+}
+
+var upgradeSlots = []byte{EquipHead, EquipNeck, EquipShoulder, EquipBack, EquipChest, EquipWrist, EquipHands, EquipWaist, EquipLegs, EquipFeet, EquipFinger, EquipTrinket, EquipWeapon, EquipOffhand, EquipTotem}
+
+// UpgradeSlot reports if FindUpgrades can rank upgrades for the item slot.
+func UpgradeSlot(slot byte) bool {
+	for _, s := range upgradeSlots {
+		if s == slot {
+			return true
+		}
+	}
+	return false
+}
+
+// FindUpgrades simulates every item allowed by the filter in place of the current gear, ranking them per slot.
+//
+// Candidates are gemmed with the gem optimizer (keeping the gems in the rest of the gear)
+// and keep the current enchant if it fits. Set bonuses, on-use effects and the shared trinket
+// cooldown are all accounted for by simulating the full set of gear.
+// Rings and trinkets are tried in both slots, keeping the better one.
+func FindUpgrades(opts Options, equip Equipment, seconds int, numSims int, uopts UpgradeOptions) UpgradeResult {
+	opts.UseAI = true
+	if uopts.TopN <= 0 {
+		uopts.TopN = 5
+	}
+	if len(uopts.Slots) == 0 {
+		uopts.Slots = upgradeSlots
+	}
+	if len(equip) <= int(EquipTotem) {
+		full := make(Equipment, EquipTotem+1)
+		copy(full, equip)
+		equip = full
+	}
+	weights := uopts.Weights
+	if len(weights) == 0 {
+		weights = StatWeights(opts, equip, seconds, numSims)
+	}
+	gemOpts := GemOptimizerOptions{MaxPhase: uopts.MaxPhase, MinQuality: uopts.MinQuality, Confirm: 1}
+
+	base := newSimRunner(CalculateTotalStats(opts, equip), equip, opts, seconds)
+	baseSets := activeSets(opts, equip)
+	output := UpgradeResult{}
+
+	doCandidate := func(slot byte, item Item) Upgrade {
+		e := equip.Clone()
+		item.Enchant = Enchant{}
+		if equip[slot].Enchant.Fits(item) {
+			item.Enchant = equip[slot].Enchant
+		}
+		e[slot] = item
+		if item.SubSlot == SubslotTwoHand {
+			e[EquipOffhand] = Item{}
+		}
+		if gemmed := searchItemGems(e, int(slot), gemOpts, weights); len(gemmed) > 0 {
+			e = gemmed[0].Equip
+		}
+		dps, baseDPS := runPairedDPS(base, CalculateTotalStats(opts, e), e, opts, seconds, numSims)
+		up := Upgrade{Item: e[slot], Slot: slot, Iterations: len(dps)}
+		up.DPS, _ = meanStdev(dps)
+		_, up.Delta, up.DeltaErr = pairedDiffs(dps, baseDPS)
+		up.SetsGained, up.SetsLost = diffSets(baseSets, activeSets(opts, e))
+		return up
+	}
+
+	type candidate struct {
+		si   int
+		slot byte
+		item Item
+	}
+	candidates := []candidate{}
+	pool := QueryItems(uopts.ItemFilter)
+	output.Slots = make([]SlotUpgrades, len(uopts.Slots))
+	for si, slot := range uopts.Slots {
+		equipSlots := []byte{slot}
+		switch slot {
+		case EquipFinger:
+			equipSlots = []byte{EquipFinger1, EquipFinger2}
+		case EquipTrinket:
+			equipSlots = []byte{EquipTrinket1, EquipTrinket2}
+		}
+		output.Slots[si].Slot = slot
+		for _, es := range equipSlots {
+			output.Slots[si].Current = append(output.Slots[si].Current, equip[es])
+		}
+		for _, item := range pool {
+			if item.Slot != slot || (item.Unique && isEquipped(equip, equipSlots, item.ID)) {
+				continue
+			}
+			if slot == EquipOffhand && equip[EquipWeapon].SubSlot == SubslotTwoHand {
+				continue // nowhere to put it
+			}
+			for _, es := range equipSlots {
+				if equip[es].ID != item.ID {
+					candidates = append(candidates, candidate{si, es, item})
+				}
+			}
+		}
+	}
+
+	opts.Job.Phase("upgrades", phaseTotal(opts, len(candidates)+1, numSims))
+	output.BaseDPS, _ = meanStdev(base.first(base.untilPrecise(numSims, nil)))
+	results := make([]Upgrade, len(candidates))
+	parallel(len(candidates), func(i int) {
+		results[i] = doCandidate(candidates[i].slot, candidates[i].item)
+	})
+
+	// Keep the better slot for rings and trinkets.
+	best := make([]map[int32]Upgrade, len(uopts.Slots))
+	for i := range best {
+		best[i] = map[int32]Upgrade{}
+	}
+	for i, c := range candidates {
+		up := results[i]
+		if cur, ok := best[c.si][up.Item.ID]; !ok || up.Delta > cur.Delta {
+			best[c.si][up.Item.ID] = up
+		}
+	}
+	for si := range output.Slots {
+		ups := make([]Upgrade, 0, len(best[si]))
+		for _, up := range best[si] {
+			ups = append(ups, up)
+		}
+		sort.Slice(ups, func(i, j int) bool {
+			if ups[i].Delta == ups[j].Delta {
+				return ups[i].Item.ID < ups[j].Item.ID
+			}
+			return ups[i].Delta > ups[j].Delta
+		})
+		if len(ups) > uopts.TopN {
+			ups = ups[:uopts.TopN]
+		}
+		output.Slots[si].Upgrades = ups
+	}
+	return output
+}
+
+func isEquipped(equip Equipment, slots []byte, id int32) bool {
+	for _, s := range slots {
+		if equip[s].ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// activeSets returns the set bonuses the equipment activates.
+func activeSets(opts Options, equip Equipment) []string {
+	return NewSim(CalculateTotalStats(opts, equip), equip, opts).ActivateSets()
+}
+
+// diffSets returns the bonuses in after but not before, and in before but not after.
+func diffSets(before, after []string) (gained []string, lost []string) {
+	has := func(list []string, v string) bool {
+		for _, s := range list {
+			if s == v {
+				return true
+			}
+		}
+		return false
+	}
+	for _, s := range after {
+		if !has(before, s) {
+			gained = append(gained, s)
+		}
+	}
+	for _, s := range before {
+		if !has(after, s) {
+			lost = append(lost, s)
+		}
+	}
+	return gained, lost
+}
@@ -0,0 +1,23 @@
+package tbc
+
+import "testing"
+
+func TestDiffSets(t *testing.T) {
+	gained, lost := diffSets([]string{"Netherstrike (3pc)", "Spellstrike (2pc)"}, []string{"Spellstrike (2pc)", "Tidefury (4pc)"})
+	if len(gained) != 1 || gained[0] != "Tidefury (4pc)" {
+		t.Fatalf("unexpected gained sets: %v", gained)
+	}
+	if len(lost) != 1 || lost[0] != "Netherstrike (3pc)" {
+		t.Fatalf("unexpected lost sets: %v", lost)
+	}
+}
+
+func TestEnchantFits(t *testing.T) {
+	shieldEnchant := EnchantLookup["Shield - Intellect"]
+	if !shieldEnchant.Fits(ItemsByName["Mazthoril Honor Shield"]) {
+		t.Fatalf("shield enchant should fit a shield")
+	}
+	if shieldEnchant.Fits(ItemsByName["Talisman of Nightbane"]) {
+		t.Fatalf("shield enchant should not fit a held in offhand item")
+	}
+}
@@ -0,0 +1,199 @@
+package tbc
+
+import "fmt"
+
+// GearProblem is a single problem with a set of gear, found while loading or validating it.
+type GearProblem struct {
+	Slot    byte   // equipment slot of the item, EquipUnknown if the item was never placed.
+	Item    string `json:",omitempty"` // name of the item, as given if it isn't in the item data.
+	Message string
+
+	// Warning problems don't stop the gear from being simmed, like an inactive meta gem.
+	Warning bool `json:",omitempty"`
+}
+
+func (p GearProblem) String() string {
+	where := SlotName(p.Slot)
+	if p.Item != "" {
+		where += fmt.Sprintf(" (%s)", p.Item)
+	}
+	return where + ": " + p.Message
+}
+
+// GearProblems is a list of problems with a set of gear.
+type GearProblems []GearProblem
+
+// Errors returns the problems that aren't warnings.
+func (ps GearProblems) Errors() GearProblems {
+	errs := GearProblems{}
+	for _, p := range ps {
+		if !p.Warning {
+			errs = append(errs, p)
+		}
+	}
+	return errs
+}
+
+func (ps GearProblems) Strings() []string {
+	out := make([]string, len(ps))
+	for i, p := range ps {
+		out[i] = p.String()
+	}
+	return out
+}
+
+var slotNames = map[byte]string{
+	EquipUnknown:  "Gear",
+	EquipHead:     "Head",
+	EquipNeck:     "Neck",
+	EquipShoulder: "Shoulder",
+	EquipBack:     "Back",
+	EquipChest:    "Chest",
+	EquipWrist:    "Wrist",
+	EquipHands:    "Hands",
+	EquipWaist:    "Waist",
+	EquipLegs:     "Legs",
+	EquipFeet:     "Feet",
+	EquipFinger:   "Finger",
+	EquipFinger1:  "Finger 1",
+	EquipFinger2:  "Finger 2",
+	EquipTrinket:  "Trinket",
+	EquipTrinket1: "Trinket 1",
+	EquipTrinket2: "Trinket 2",
+	EquipWeapon:   "Weapon",
+	EquipOffhand:  "Offhand",
+	EquipTotem:    "Totem",
+}
+
+// SlotName is the display name of an equipment slot.
+func SlotName(slot byte) string {
+	if name, ok := slotNames[slot]; ok {
+		return name
+	}
+	return fmt.Sprintf("Slot %d", slot)
+}
+
+// slotsFor returns the equipment slots an item can go in.
+func slotsFor(item Item) []byte {
+	switch item.Slot {
+	case EquipFinger:
+		return []byte{EquipFinger1, EquipFinger2}
+	case EquipTrinket:
+		return []byte{EquipTrinket1, EquipTrinket2}
+	}
+	return []byte{item.Slot}
+}
+
+// Fits is true if the item can go in the equipment slot.
+func (item Item) Fits(slot byte) bool {
+	for _, s := range slotsFor(item) {
+		if s == slot {
+			return true
+		}
+	}
+	return false
+}
+
+// NewEquipment puts a list of items into their equipment slots.
+// Rings and trinkets go into the first free slot. Items that have no free slot are left out and reported.
+func NewEquipment(items ...Item) (Equipment, GearProblems) {
+	e := make(Equipment, EquipTotem+1)
+	problems := GearProblems{}
+	for _, item := range items {
+		if item.ID == 0 && item.Name == "" {
+			continue // empty slot
+		}
+		if !e.Place(item) {
+			problems = append(problems, GearProblem{Slot: item.Slot, Item: item.Name, Message: "no free slot for this item"})
+		}
+	}
+	return e, problems
+}
+
+// Place puts an item in the first free slot it can go in.
+// Returns false if every slot it can go in is taken.
+func (e Equipment) Place(item Item) bool {
+	for _, slot := range slotsFor(item) {
+		if int(slot) < len(e) && slot != EquipUnknown && e[slot].ID == 0 && e[slot].Name == "" {
+			e[slot] = item
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the equipment is gear a player could actually wear:
+// items are in the right slots and not equipped twice, two-handers have nothing in the offhand,
+// gems fit their sockets, enchants fit their items and the meta gem is active.
+// Equipment is expected to be indexed by slot, as built by NewEquipment.
+func (e Equipment) Validate() GearProblems {
+	problems := GearProblems{}
+	add := func(slot byte, item Item, warning bool, format string, args ...interface{}) {
+		problems = append(problems, GearProblem{Slot: slot, Item: item.Name, Message: fmt.Sprintf(format, args...), Warning: warning})
+	}
+
+	equipped := map[int32]byte{}
+	uniqueGems := map[int32]int{}
+	for i, item := range e {
+		slot := byte(i)
+		if item.ID == 0 && item.Name == "" {
+			continue
+		}
+		if _, ok := ItemsByID[item.ID]; !ok {
+			add(slot, item, false, "not in the item data")
+			continue
+		}
+		fits := false
+		for _, s := range slotsFor(item) {
+			fits = fits || s == slot
+		}
+		if !fits {
+			add(slot, item, false, "is a %s item", SlotName(item.Slot))
+		}
+		if first, ok := equipped[item.ID]; ok && item.Unique {
+			add(slot, item, false, "is unique-equipped and already in %s", SlotName(first))
+		} else if !ok {
+			equipped[item.ID] = slot
+		}
+
+		for gi, g := range item.Gems {
+			if g.ID == 0 {
+				continue
+			}
+			if gi >= len(item.GemSlots) {
+				add(slot, item, false, "%s is in socket %d but the item has %d sockets", g.Name, gi+1, len(item.GemSlots))
+				continue
+			}
+			if (g.Color == GemColorMeta) != (item.GemSlots[gi] == GemColorMeta) {
+				add(slot, item, false, "%s can't go in a %s socket", g.Name, item.GemSlots[gi])
+			}
+			if g.Unique {
+				uniqueGems[g.ID]++
+				if uniqueGems[g.ID] == 2 {
+					add(slot, item, false, "%s is unique-equipped and socketed more than once", g.Name)
+				}
+			}
+		}
+
+		if item.Enchant.ID != 0 && !item.Enchant.Fits(item) {
+			add(slot, item, false, "%s can't be applied to this item", item.Enchant.Name)
+		}
+	}
+
+	if int(EquipOffhand) < len(e) && e[EquipWeapon].SubSlot == SubslotTwoHand && e[EquipOffhand].ID != 0 {
+		add(EquipOffhand, e[EquipOffhand], false, "can't be used with the two-hand weapon %s", e[EquipWeapon].Name)
+	}
+
+	if w := e.MetaWarning(); w != "" {
+		meta, _ := e.MetaGem()
+		for i, item := range e {
+			for _, g := range item.Gems {
+				if g.ID == meta.ID {
+					add(byte(i), item, true, "%s", w)
+					return problems
+				}
+			}
+		}
+	}
+	return problems
+}
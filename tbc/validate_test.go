@@ -0,0 +1,65 @@
+package tbc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	ring := ItemsByName["Sparking Arcanite Ring"]
+	helm := ItemsByName["Tidefury Helm"]
+	helm.Gems = []Gem{GemLookup["Runed Living Ruby"], GemLookup["Chaotic Skyfire Diamond"]}
+	helm.Enchant = EnchantLookup["Ring - Spellpower"]
+
+	equip, problems := NewEquipment(
+		helm,
+		ring,
+		ring,
+		ItemsByName["Terokk's Shadowstaff"],
+		ItemsByName["Mazthoril Honor Shield"],
+	)
+	if len(problems) != 0 {
+		t.Fatalf("unexpected placement problems: %v", problems.Strings())
+	}
+	if equip[EquipFinger1].ID != ring.ID || equip[EquipFinger2].ID != ring.ID {
+		t.Fatalf("rings should fill both finger slots")
+	}
+
+	problems = equip.Validate()
+	want := []string{
+		"Head (Tidefury Helm): Runed Living Ruby can't go in a meta socket",
+		"Head (Tidefury Helm): Chaotic Skyfire Diamond can't go in a yellow socket",
+		"Head (Tidefury Helm): Ring - Spellpower can't be applied to this item",
+		"Finger 2 (Sparking Arcanite Ring): is unique-equipped and already in Finger 1",
+		"Offhand (Mazthoril Honor Shield): can't be used with the two-hand weapon Terokk's Shadowstaff",
+	}
+	got := problems.Errors().Strings()
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("got problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if len(problems) != len(got)+1 || !problems[len(problems)-1].Warning {
+		t.Fatalf("expected an inactive meta gem warning, got %v", problems.Strings())
+	}
+
+	equip[EquipHead] = ring
+	if problems := equip.Validate(); !strings.Contains(problems[0].String(), "is a Finger item") {
+		t.Fatalf("expected a wrong slot problem, got %v", problems.Strings())
+	}
+
+	_, problems = NewEquipment(ring, ring, ring)
+	if len(problems) != 1 {
+		t.Fatalf("expected a third ring to have no free slot, got %v", problems.Strings())
+	}
+
+	equip, _ = NewEquipment(ring)
+	other := ItemsByName["Cobalt Band of Tyrigosa"]
+	if !equip.Place(other) || equip[EquipFinger2].ID != other.ID || equip.Place(other) {
+		t.Fatalf("expected a ring to go in the free finger slot and then have no free slot")
+	}
+
+	lola := ItemsByName["Lola's Eve"]
+	equip, _ = NewEquipment(lola, lola)
+	if problems := equip.Validate(); len(problems) != 0 {
+		t.Fatalf("expected two of a ring that isn't unique to be fine, got %v", problems.Strings())
+	}
+}
@@ -1,14 +1,17 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math"
-	"strings"
 	"syscall/js"
 	"time"
 
+	"github.com/lologarithm/wowsim/api"
+	"github.com/lologarithm/wowsim/importer"
 	"github.com/lologarithm/wowsim/tbc"
+	"github.com/lologarithm/wowsim/wire"
 )
 
 func main() {
@@ -16,88 +19,125 @@ func main() {
 
 	simfunc := js.FuncOf(Simulate)
 	statfunc := js.FuncOf(StatWeight)
+	statWeightsfunc := js.FuncOf(StatWeights)
 	statComputefunc := js.FuncOf(ComputeStats)
 	gearlistfunc := js.FuncOf(GearList)
-	packOptfunc := js.FuncOf(PackOptions)
+	shareLinkfunc := js.FuncOf(ShareLink)
+	parseLinkfunc := js.FuncOf(ParseLink)
+	importGearfunc := js.FuncOf(ImportGear)
+	simBinfunc := js.FuncOf(SimulateBinary)
+	statWeightsBinfunc := js.FuncOf(StatWeightsBinary)
+	statComputeBinfunc := js.FuncOf(ComputeStatsBinary)
+	gearlistBinfunc := js.FuncOf(GearListBinary)
 
 	js.Global().Set("simulate", simfunc)
 	js.Global().Set("statweight", statfunc)
+	js.Global().Set("statweights", statWeightsfunc)
 	js.Global().Set("computestats", statComputefunc)
 	js.Global().Set("gearlist", gearlistfunc)
-	js.Global().Set("packopts", packOptfunc)
+	js.Global().Set("sharelink", shareLinkfunc)
+	js.Global().Set("parselink", parseLinkfunc)
+	js.Global().Set("importgear", importGearfunc)
+	js.Global().Set("simulatebin", simBinfunc)
+	js.Global().Set("statweightsbin", statWeightsBinfunc)
+	js.Global().Set("computestatsbin", statComputeBinfunc)
+	js.Global().Set("gearlistbin", gearlistBinfunc)
 	js.Global().Call("wasmready")
 	<-c
 }
 
-func PackOptions(this js.Value, args []js.Value) interface{} {
-	opt := parseOptions(args[0])
-	packedOpts := opt.Pack()
-	js.CopyBytesToJS(js.Global().Get("results").Get(args[1].String()), packedOpts)
-	return len(packedOpts)
+// ShareLink packs the gear and options into the text of a share link.
+// (gearlist, options)
+func ShareLink(this js.Value, args []js.Value) interface{} {
+	gear, _ := getGear(args[0])
+	return tbc.Setup{Options: parseOptions(args[1]), Equip: gear}.Link()
+}
+
+// ParseLink reads the gear and options of a share link, including links from before gear was packed.
+// The options are in the format the UI sends to the sim.
+// (link text)
+func ParseLink(this js.Value, args []js.Value) interface{} {
+	s, problems, err := tbc.ParseLink(args[0].String())
+	if err != nil {
+		return errorJSON(err)
+	}
+	out, err := json.Marshal(struct {
+		Gear     api.Gear
+		Options  api.Options
+		Problems []string
+	}{Gear: api.GearOf(s.Equip), Options: api.OptionsOf(s.Options), Problems: problems.Strings()})
+	if err != nil {
+		fmt.Printf("Failed to format JSON output: %s\n", err)
+	}
+	return string(out)
 }
 
 // GearList reports all items of gear to the UI to display.
+// (optional filter) the filter is either a slot number or a JSON encoded tbc.ItemFilter.
 func GearList(this js.Value, args []js.Value) interface{} {
-	slot := byte(128)
-
+	filter := tbc.ItemFilter{}
 	if len(args) == 1 {
-		slot = byte(args[0].Int())
-	}
-	gear := struct {
-		Items    []tbc.Item
-		Gems     []tbc.Gem
-		Enchants []tbc.Enchant
-	}{
-		Items: make([]tbc.Item, 0, len(tbc.ItemsByID)),
-	}
-	for _, v := range tbc.ItemsByID {
-		if slot != 128 && v.Slot != slot {
-			continue
+		switch args[0].Type() {
+		case js.TypeNumber:
+			filter.Slots = []byte{byte(args[0].Int())}
+		case js.TypeString:
+			if err := json.Unmarshal([]byte(args[0].String()), &filter); err != nil {
+				return errorJSON(fmt.Errorf("invalid item filter: %s", err))
+			}
 		}
-		gear.Items = append(gear.Items, v)
 	}
-	gear.Gems = tbc.Gems
-	gear.Enchants = tbc.Enchants
-
-	output, err := json.Marshal(gear)
+	output, err := json.Marshal(api.GearList(filter))
 	if err != nil {
 		// fmt.Printf("Failed to marshal gear list: %s", err)
-		output = []byte(`{"error": ` + err.Error() + `}`)
+		return errorJSON(err)
 	}
 	// fmt.Printf("Item Output: %s", string(output))
 	return string(output)
 }
 
+// ImportGear converts gear exported from a gear planner or addon into the gear list format the UI uses.
+// (export text)
+func ImportGear(this js.Value, args []js.Value) interface{} {
+	res, err := importer.Import([]byte(args[0].String()))
+	if err != nil {
+		return errorJSON(err)
+	}
+	missing := make([]string, len(res.Missing))
+	for i, m := range res.Missing {
+		missing[i] = m.String()
+	}
+	out, err := json.Marshal(struct {
+		Format   string
+		Gear     api.Gear
+		Missing  []string
+		Problems []string
+	}{Format: res.Format, Gear: api.GearOf(res.Gear), Missing: missing, Problems: res.Problems.Strings()})
+	if err != nil {
+		fmt.Printf("Failed to format JSON output: %s\n", err)
+	}
+	return string(out)
+}
+
 // GearStats takes a gear list and returns their total stats.
 // This could power a simple 'current stats of all gear' UI.
 func ComputeStats(this js.Value, args []js.Value) interface{} {
-	gear := getGear(args[0])
 	if len(args) != 2 {
 		return `{"error": "incorrect args. expected computestats(gear, options)}`
 	}
-	if args[1].IsNull() {
-		gearStats := gear.Stats()
-		gearStats = gearStats.CalculatedTotal()
-		out, err := json.Marshal(gearStats)
-		if err != nil {
-			fmt.Printf("Failed to format JSON output: %s\n", err)
-		}
-		return string(out)
+	req := api.ComputeStatsRequest{}
+	decode(args[0], &req.Gear)
+	if !args[1].IsNull() {
+		req.Opts = &api.Options{}
+		decode(args[1], req.Opts)
 	}
-	opt := parseOptions(args[1])
-	stats := tbc.CalculateTotalStats(opt, gear)
-	opt.UseAI = true // stupid complaining sim...maybe I should just default AI on.
-	fakesim := tbc.NewSim(stats, gear, opt)
-	sets := fakesim.ActivateSets()
-
-	finalStats := stats
-	for i, v := range fakesim.Buffs {
-		finalStats[i] += v
+	res := api.ComputeStats(req)
+	var out []byte
+	var err error
+	if req.Opts == nil {
+		out, err = json.Marshal(res.Stats)
+	} else {
+		out, err = json.Marshal(res)
 	}
-	out, err := json.Marshal(struct {
-		Stats []float64
-		Sets  []string
-	}{Stats: finalStats, Sets: sets})
 	if err != nil {
 		fmt.Printf("Failed to format JSON output: %s\n", err)
 	}
@@ -109,7 +149,7 @@ func ComputeStats(this js.Value, args []js.Value) interface{} {
 func StatWeight(this js.Value, args []js.Value) interface{} {
 	numSims := args[0].Int()
 	seconds := args[1].Int()
-	gear := getGear(args[2])
+	gear, _ := getGear(args[2])
 	opts := parseOptions(args[3])
 	stat := args[4].Int()
 	statModVal := args[5].Float()
@@ -158,43 +198,65 @@ func StatWeight(this js.Value, args []js.Value) interface{} {
 	return fmt.Sprintf("%0.2f,%0.2f,%0.2f", mean, stdev, conf90)
 }
 
+// StatWeights calculates the weight of every stat with paired seeds, returning tbc.StatWeightsResult as JSON.
+// (iterations, duration, gearlist, options, <optional, stat delta>, <optional, central differences>)
+func StatWeights(this js.Value, args []js.Value) interface{} {
+	if len(args) < 4 {
+		print("Expected 4 min arguments:  (#iterations, duration, gearlist, options)")
+		return `{"error": "invalid arguments supplied"}`
+	}
+	req := api.StatWeightsRequest{SimRequest: simRequest(args)}
+	if len(args) > 4 && args[4].Truthy() {
+		req.Delta = args[4].Float()
+	}
+	if len(args) > 5 {
+		req.Central = args[5].Truthy()
+	}
+	var job *tbc.Job
+	if len(args) > 6 {
+		job = progressJob(args[6])
+	}
+
+	out, err := json.Marshal(api.StatWeights(req, job))
+	if err != nil {
+		fmt.Printf("Failed to format JSON output: %s\n", err)
+		return `{"error": "failed to format output"}`
+	}
+	return string(out)
+}
+
 // Simulate takes in number of iterations, duration, a gear list, and simulation options.
-// (iterations, duration, gearlist, options, <optional, custom rotation)
+// (iterations, duration, gearlist, options, <optional, custom rotation, haste, full logs, progress callback>)
 func Simulate(this js.Value, args []js.Value) interface{} {
 	if len(args) < 4 {
 		print("Expected 4 min arguments:  (#iterations, duration, gearlist, options)")
 		return `{"error": "invalid arguments supplied"}`
 	}
 
-	customRotation := [][]string{}
-	customHaste := 0.0
+	req := api.SimulateRequest{SimRequest: simRequest(args)}
 	if len(args) >= 6 {
 		if args[4].Truthy() {
-			customRotation = parseRotation(args[4])
+			decode(args[4], &req.Rots)
 		}
 		if args[5].Truthy() {
-			customHaste = args[5].Float()
+			req.Haste = args[5].Float()
 		}
 	}
-	gear := getGear(args[2])
-	opt := parseOptions(args[3])
-	stats := tbc.CalculateTotalStats(opt, gear)
-	if customHaste != 0 {
-		stats[tbc.StatHaste] = customHaste
+	req.Debug = req.Iters == 1 // if single iteration, dump all logs to console.
+	if len(args) > 6 {
+		req.FullLogs = args[6].Truthy()
+		fmt.Printf("Building Full Log:%v\n", req.FullLogs)
 	}
 
-	simi := args[0].Int()
-	if simi == 1 { // if single iteration, dump all logs to console.
-		opt.Debug = true
-	}
-	dur := args[1].Int()
-	fullLogs := false
-	if len(args) > 6 {
-		fullLogs = args[6].Truthy()
-		fmt.Printf("Building Full Log:%v\n", fullLogs)
+	var job *tbc.Job
+	if len(args) > 7 {
+		job = progressJob(args[7])
 	}
 
-	results := runTBCSim(opt, stats, gear, dur, simi, customRotation, fullLogs)
+	print("\nSim Duration:", req.Dur)
+	print("\nNum Simulations: ", req.Iters)
+	print("\n")
+	results := api.Simulate(req, job)
 	st := time.Now()
 	output, err := json.Marshal(results)
 	if err != nil {
@@ -204,255 +266,125 @@ func Simulate(this js.Value, args []js.Value) interface{} {
 	return string(output)
 }
 
-// getGear converts js string array to a list of equipment items.
-func getGear(val js.Value) tbc.Equipment {
-	numGear := val.Length()
-	gearSet := make([]tbc.Item, numGear)
-	for i := range gearSet {
-		v := val.Index(i)
-		name := v.Get("Name")
-		id := v.Get("ID")
-		var ic tbc.Item
-		if !name.IsUndefined() {
-			ic = tbc.ItemsByName[name.String()]
-		} else if !id.IsUndefined() {
-			ic = tbc.ItemsByID[int32(id.Int())]
-		}
-		gems := v.Get("Gems")
-		gemids := v.Get("g")
-		if !(gems.IsUndefined() || gems.IsNull()) && gems.Length() > 0 {
-			ic.Gems = make([]tbc.Gem, len(ic.GemSlots))
-			for i := range ic.Gems {
-				jsgem := gems.Index(i)
-				if jsgem.IsNull() {
-					continue
-				}
-				gv, ok := tbc.GemLookup[jsgem.String()]
-				if !ok {
-					continue // wasn't a valid gem
-				}
-				ic.Gems[i] = gv
-			}
-		} else if !(gemids.IsUndefined() || gemids.IsNull()) && gemids.Length() > 0 {
-			ic.Gems = make([]tbc.Gem, len(ic.GemSlots))
-			for i := range ic.Gems {
-				jsgem := gemids.Index(i)
-				if jsgem.IsNull() || jsgem.IsUndefined() {
-					continue
-				}
-				gv, ok := tbc.GemsByID[int32(jsgem.Int())]
-				if !ok {
-					continue // wasn't a valid gem
-				}
-				ic.Gems[i] = gv
-			}
-		}
-		if !v.Get("Enchant").IsNull() && !v.Get("Enchant").IsUndefined() {
-			ic.Enchant = tbc.EnchantLookup[v.Get("Enchant").String()]
-		} else if !v.Get("e").IsNull() && !v.Get("e").IsUndefined() {
-			ic.Enchant = tbc.EnchantByID[int32(v.Get("e").Int())]
-		}
-		gearSet[i] = ic
+// The binary functions take and return the messages of api.Messages, encoded and decoded by ui/wire.js.
+// They replace simulate, statweights, computestats and gearlist, which are kept (returning JSON) until
+// nothing calls them. Errors are returned as {error}.
+
+// SimulateBinary is Simulate with an api.SimulateRequest, returning SimResults.
+// (request, <optional, progress callback>)
+func SimulateBinary(this js.Value, args []js.Value) interface{} {
+	req := api.SimulateRequest{}
+	if err := readWire(args[0], &req); err != nil {
+		return jsError(err)
 	}
-	return tbc.Equipment(gearSet)
+	req.Debug = req.Iters == 1 // if single iteration, dump all logs to console.
+	return writeWire(api.Simulate(req, progressArg(args, 1)))
 }
 
-func parseOptions(val js.Value) tbc.Options {
-	var custom = val.Get("custom")
-	opt := tbc.Options{
-		ExitOnOOM:    val.Get("exitoom").Truthy(),
-		NumBloodlust: val.Get("buffbl").Int(),
-		NumDrums:     val.Get("buffdrums").Int(),
-		UseAI:        val.Get("useai").Truthy(),
-		Buffs: tbc.Buffs{
-			ArcaneInt:                val.Get("buffai").Truthy(),
-			GiftOftheWild:            val.Get("buffgotw").Truthy(),
-			BlessingOfKings:          val.Get("buffbk").Truthy(),
-			ImprovedBlessingOfWisdom: val.Get("buffibow").Truthy(),
-			ImprovedDivineSpirit:     val.Get("buffids").Truthy(),
-			JudgementOfWisdom:        val.Get("debuffjow").Truthy(),
-			ImpSealofCrusader:        val.Get("debuffisoc").Truthy(),
-			Misery:                   val.Get("debuffmis").Truthy(),
-			Moonkin:                  val.Get("buffmoon").Truthy(),
-			MoonkinRavenGoddess:      val.Get("buffmoonrg").Truthy(),
-			SpriestDPS:               uint16(val.Get("buffspriest").Int()),
-			WaterShield:              val.Get("sbufws").Truthy(),
-			EyeOfNight:               val.Get("buffeyenight").Truthy(),
-			TwilightOwl:              val.Get("bufftwilightowl").Truthy(),
-			Race:                     tbc.RaceBonusType(val.Get("sbufrace").Int()),
-			Custom: tbc.Stats{
-				tbc.StatInt:       custom.Get("custint").Float(),
-				tbc.StatSpellCrit: custom.Get("custsc").Float(),
-				tbc.StatSpellHit:  custom.Get("custsh").Float(),
-				tbc.StatSpellDmg:  custom.Get("custsp").Float(),
-				tbc.StatHaste:     custom.Get("custha").Float(),
-				tbc.StatMP5:       custom.Get("custmp5").Float(),
-				tbc.StatMana:      custom.Get("custmana").Float(),
-			},
-		},
-		Consumes: tbc.Consumes{
-			FlaskOfBlindingLight:     val.Get("confbl").Truthy(),
-			FlaskOfMightyRestoration: val.Get("confmr").Truthy(),
-			BrilliantWizardOil:       val.Get("conbwo").Truthy(),
-			MajorMageblood:           val.Get("conmm").Truthy(),
-			BlackendBasilisk:         val.Get("conbb").Truthy(),
-			DestructionPotion:        val.Get("condp").Truthy(),
-			SuperManaPotion:          val.Get("consmp").Truthy(),
-			DarkRune:                 val.Get("condr").Truthy(),
-		},
-		Talents: tbc.Talents{
-			LightninOverload:   5,
-			ElementalPrecision: 3,
-			NaturesGuidance:    3,
-			TidalMastery:       5,
-			ElementalMastery:   true,
-			UnrelentingStorm:   3,
-			CallOfThunder:      5,
-			Concussion:         5,
-			Convection:         5,
-		},
-		Totems: tbc.Totems{
-			TotemOfWrath: val.Get("totwr").Int(),
-			WrathOfAir:   val.Get("totwoa").Truthy(),
-			Cyclone2PC:   val.Get("totcycl2p").Truthy(),
-			ManaStream:   val.Get("totms").Truthy(),
-		},
-		DPSReportTime: val.Get("dpsReportTime").Int(),
-	}
-
-	return opt
+// StatWeightsBinary is StatWeights with an api.StatWeightsRequest, returning a StatWeightsResult.
+// (request, <optional, progress callback>)
+func StatWeightsBinary(this js.Value, args []js.Value) interface{} {
+	req := api.StatWeightsRequest{}
+	if err := readWire(args[0], &req); err != nil {
+		return jsError(err)
+	}
+	return writeWire(api.StatWeights(req, progressArg(args, 1)))
 }
 
-func parseRotation(val js.Value) [][]string {
-
-	out := [][]string{}
+// ComputeStatsBinary is ComputeStats with an api.ComputeStatsRequest, returning a ComputeStatsResult.
+// (request)
+func ComputeStatsBinary(this js.Value, args []js.Value) interface{} {
+	req := api.ComputeStatsRequest{}
+	if err := readWire(args[0], &req); err != nil {
+		return jsError(err)
+	}
+	return writeWire(api.ComputeStats(req))
+}
 
-	for i := 0; i < val.Length(); i++ {
-		rot := []string{}
-		jsrot := val.Index(i)
-		for j := 0; j < jsrot.Length(); j++ {
-			rot = append(rot, jsrot.Index(j).String())
+// GearListBinary is GearList with an optional ItemFilter, returning a GearListResult.
+// (<optional, filter>)
+func GearListBinary(this js.Value, args []js.Value) interface{} {
+	filter := tbc.ItemFilter{}
+	if len(args) > 0 && args[0].Truthy() {
+		if err := readWire(args[0], &filter); err != nil {
+			return jsError(err)
 		}
-		out = append(out, rot)
 	}
+	return writeWire(api.GearList(filter))
+}
 
-	return out
+func readWire(val js.Value, v interface{}) error {
+	data := make([]byte, val.Get("length").Int())
+	js.CopyBytesToGo(data, val)
+	return wire.Unmarshal(data, v)
 }
 
-type SimResult struct {
-	Rotation     []string
-	SimSeconds   int
-	RealDuration float64
-	Logs         string
-	DPSAvg       float64              `json:"dps"`
-	DPSDev       float64              `json:"dev"`
-	MaxDPS       float64              `json:"max"`
-	OOMAt        float64              `json:"oomat"`
-	NumOOM       int                  `json:"numOOM"`
-	DPSAtOOM     float64              `json:"dpsAtOOM"`
-	Casts        map[int32]CastMetric `json:"casts"`
-	DPSHist      map[int]int          `json:"dpsHist"` // rounded DPS to count
+func writeWire(v interface{}) interface{} {
+	data, err := wire.Marshal(v)
+	if err != nil {
+		return jsError(err)
+	}
+	out := js.Global().Get("Uint8Array").New(len(data))
+	js.CopyBytesToJS(out, data)
+	return out
 }
 
-type CastMetric struct {
-	Count int     `json:"count"`
-	Dmg   float64 `json:"dmg"`
-	Crits int     `json:"crits"`
+func jsError(err error) interface{} {
+	return map[string]interface{}{"error": err.Error()}
 }
 
-func runTBCSim(opts tbc.Options, stats tbc.Stats, equip tbc.Equipment, seconds int, numSims int, customRotation [][]string, fullLogs bool) []SimResult {
-	print("\nSim Duration:", seconds)
-	print("\nNum Simulations: ", numSims)
-	print("\n")
+// errorJSON is err as the JSON error object the UI expects from functions that return JSON text.
+func errorJSON(err error) string {
+	out, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return string(out)
+}
 
-	spellOrders := [][]string{}
-	doingCustom := false
-	if len(customRotation) > 0 {
-		doingCustom = true
-		spellOrders = customRotation
+// progressArg is the job of an optional progress callback argument.
+func progressArg(args []js.Value, i int) *tbc.Job {
+	if len(args) <= i {
+		return nil
 	}
-	results := []SimResult{}
-	logsBuffer := &strings.Builder{}
-
-	dosim := func(spells []string, simsec int) {
-		simMetrics := SimResult{
-			DPSHist:  map[int]int{},
-			Casts:    map[int32]CastMetric{},
-			Rotation: spells,
-		}
-		if opts.UseAI {
-			simMetrics.Rotation = []string{"AI Optimized"}
-		}
-		st := time.Now()
-		rseed := time.Now().Unix()
-		optNow := opts
-		optNow.SpellOrder = spells
-		optNow.RSeed = rseed
-		sim := tbc.NewSim(stats, equip, optNow)
-
-		var totalSq float64
-		for ns := 0; ns < numSims; ns++ {
-			if fullLogs {
-				sim.Debug = func(s string, vals ...interface{}) {
-					logsBuffer.WriteString(fmt.Sprintf("[%0.1f] "+s, append([]interface{}{(float64(sim.CurrentTick) / float64(tbc.TicksPerSecond))}, vals...)...))
-				}
-			}
-			metrics := sim.Run(simsec)
-			dps := metrics.TotalDamage / float64(simsec)
-			if opts.DPSReportTime > 0 {
-				dps = metrics.ReportedDamage / float64(opts.DPSReportTime)
-			}
-			totalSq += dps * dps
-			simMetrics.DPSAvg += dps
-			dpsRounded := int(math.Round(dps/10) * 10)
-			simMetrics.DPSHist[dpsRounded] += 1
-			if dps > simMetrics.MaxDPS {
-				simMetrics.MaxDPS = dps
-			}
-			if (metrics.OOMAt) > 0 {
-				simMetrics.OOMAt += float64(metrics.OOMAt)
-				simMetrics.DPSAtOOM += float64(metrics.DamageAtOOM) / float64(metrics.OOMAt)
-				simMetrics.NumOOM++
-			}
-			for _, cast := range metrics.Casts {
-				var id = cast.Spell.ID
-				if cast.IsLO {
-					id = 1000 - cast.Spell.ID
-				}
-				cm := simMetrics.Casts[id]
-				cm.Count++
-				cm.Dmg += cast.DidDmg
-				if cast.DidCrit {
-					cm.Crits++
-				}
-				simMetrics.Casts[id] = cm
-			}
-
-		}
+	return progressJob(args[i])
+}
 
-		meanSq := totalSq / float64(numSims)
-		mean := simMetrics.DPSAvg / float64(numSims)
-		stdev := math.Sqrt(meanSq - mean*mean)
+// progressJob makes a job that calls onProgress with {phase, iterations, total} as the sim runs,
+// or nil if onProgress isn't a function. The sim blocks the worker, so it can't be cancelled from JS.
+func progressJob(onProgress js.Value) *tbc.Job {
+	if onProgress.Type() != js.TypeFunction {
+		return nil
+	}
+	return tbc.NewJob(context.Background(), func(p tbc.Progress) {
+		onProgress.Invoke(map[string]interface{}{"phase": p.Phase, "iterations": p.Iterations, "total": p.Total})
+	})
+}
 
-		simMetrics.DPSDev = stdev
-		simMetrics.DPSAvg /= float64(numSims)
-		if simMetrics.NumOOM > 0 {
-			simMetrics.OOMAt /= float64(simMetrics.NumOOM)
-			simMetrics.DPSAtOOM /= float64(simMetrics.NumOOM)
-		}
+// simRequest reads the arguments every sim function starts with.
+// (iterations, duration, gearlist, options)
+func simRequest(args []js.Value) api.SimRequest {
+	req := api.SimRequest{Iters: args[0].Int(), Dur: args[1].Int()}
+	decode(args[2], &req.Gearlist)
+	decode(args[3], &req.Opts)
+	return req
+}
 
-		simMetrics.Logs = logsBuffer.String()
-		simMetrics.SimSeconds = simsec
-		simMetrics.RealDuration = time.Now().Sub(st).Seconds()
-		results = append(results, simMetrics)
+// decode converts a JS value to Go through JSON, so the wasm takes the same shapes as the HTTP API.
+func decode(val js.Value, v interface{}) {
+	text := js.Global().Get("JSON").Call("stringify", val).String()
+	if err := json.Unmarshal([]byte(text), v); err != nil {
+		fmt.Printf("Failed to read %T from the UI: %s\n", v, err)
 	}
+}
 
-	if !doingCustom && opts.UseAI {
-		dosim([]string{"AI Optimized"}, seconds) // Let AI determine best possible DPS
-	} else {
-		for _, spells := range spellOrders {
-			dosim(spells, seconds)
-		}
-	}
-	return results
+// getGear converts a JS gear list to equipment, along with any problems with the gear.
+// Items that can't be found are left out.
+func getGear(val js.Value) (tbc.Equipment, tbc.GearProblems) {
+	var gear api.Gear
+	decode(val, &gear)
+	return gear.Equipment()
+}
+
+func parseOptions(val js.Value) tbc.Options {
+	var opt api.Options
+	decode(val, &opt)
+	return opt.TBC()
 }
@@ -34,6 +34,8 @@ simlib.onmessage = (event) => {
     } else if (m == "getGearList") {
         // do something
         popgear(event.data.payload);
+    } else if (m == "progress") {
+        onProgress(event.data);
     } else {
         var onComp = simrequests[event.data.id];
         if (onComp != null) {
@@ -49,6 +51,10 @@ simlib2.onmessage = (event) => {
         simlib2.postMessage({msg: "setID", payload: "2"});
         return;
     }
+    if (m == "progress") {
+        onProgress(event.data);
+        return;
+    }
     var onComp = simrequests[event.data.id];
     if (onComp != null) {
         onComp(event.data.payload);
@@ -56,9 +62,19 @@ simlib2.onmessage = (event) => {
 }
 
 var simrequests = {};
-function simulate(iters, dur, gearlist, opts, rots, haste, fullLogs, onComplete) {
+var simprogress = {}; // optional callbacks with the {phase, iterations, total} of a running request.
+
+function onProgress(data) {
+    var onProg = simprogress[data.id];
+    if (onProg != null) {
+        onProg(data.payload);
+    }
+}
+
+function simulate(iters, dur, gearlist, opts, rots, haste, fullLogs, onComplete, onProg) {
     var id = makeid();
     simrequests[id] = onComplete
+    simprogress[id] = onProg
     var worker = simlib;
     if (simlibBusy) {
         worker = simlib2;
@@ -70,30 +86,35 @@ function simulate(iters, dur, gearlist, opts, rots, haste, fullLogs, onComplete)
     }});
 }
 
-var worker2 =  false;
-function statweight(iters, dur, gearlist, opts, statToMod, modAmount, onComplete) {
+function statweights(iters, dur, gearlist, opts, onComplete, onProg) {
     var id = makeid();
     simrequests[id] = onComplete
-    var worker = simlib;
-    if (worker2) {
-        worker = simlib2;
-        worker2 = false;
-    } else {
-        worker2 = true;
-    }
-    worker.postMessage({msg: "statweight", id: id, payload: {
-        iters: iters, dur: dur, gearlist: gearlist, opts: opts, stat: statToMod, modVal: modAmount
+    simprogress[id] = onProg
+    simlib.postMessage({msg: "statweights", id: id, payload: {
+        iters: iters, dur: dur, gearlist: gearlist, opts: opts
     }});
 }
 
-function packOptions(opt, onComplete) {
+function importPlannerGear(text, onComplete) {
+    var id = makeid();
+    simrequests[id] = onComplete
+    simlib.postMessage({msg: "importGear", id: id, payload: text});
+}
+
+function shareLink(gear, opt, onComplete) {
     var id = makeid();
     simrequests[id] = onComplete
-    simlib.postMessage({msg: "packopt", id: id, payload: {
-        opt: opt,
+    simlib.postMessage({msg: "shareLink", id: id, payload: {
+        gear: gear, opt: opt,
     }});
 }
 
+function parseLink(link, onComplete) {
+    var id = makeid();
+    simrequests[id] = onComplete
+    simlib.postMessage({msg: "parseLink", id: id, payload: link});
+}
+
 function makeid() {
     var result           = '';
     var characters       = 'ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789';
@@ -160,83 +181,21 @@ function getOptions() {
     return options;
 }
 
-// basically this is a parser for the compact serializer for options.
-//  for some reason I wrote the writer in go and the parser here. 
-//  maybe its time to re-evaluate my life choices.
-function setOptions(data) {
-    
-    document.getElementById("buffbl").selectedIndex = data[1];
-    document.getElementById("buffdrums").selectedIndex = data[2];
-    
-    var dst = new ArrayBuffer(data.byteLength);
-    new Uint8Array(dst).set(new Uint8Array(data));
-
-    var buffView = new DataView(dst, 3);
-    
-    var idx = 0;
-
-    var buffOpt1 = buffView.getUint8(idx, true); idx++;
-    var buffOpt2 = buffView.getUint8(idx, true); idx++;
-
-    document.getElementById("buffai").checked = (buffOpt1 & 1) == 1;
-    document.getElementById("buffgotw").checked = (buffOpt1 & 1<<1) == 1<<1;
-    document.getElementById("buffbk").checked = (buffOpt1 & 1<<2) == 1<<2;
-    document.getElementById("buffibow").checked = (buffOpt1 & 1<<3) == 1<<3;
-    document.getElementById("buffids").checked = (buffOpt1 & 1<<4) == 1<<4;
-    document.getElementById("buffmoon").checked = (buffOpt1 & 1<<5) == 1<<5;
-    document.getElementById("buffmoonrg").checked = (buffOpt1 & 1<<6) == 1<<6;
-    document.getElementById("buffeyenight").checked = (buffOpt1 & 1<<7) == 1<<7;
-    
-    document.getElementById("bufftwilightowl").checked = (buffOpt2 & 1) == 1;
-    document.getElementById("sbufws").checked = (buffOpt2 & 1<<1) == 1<<1;
-    document.getElementById("debuffjow").checked = (buffOpt2 & 1<<2) == 1<<2;
-    document.getElementById("debuffisoc").checked = (buffOpt2 & 1<<3) == 1<<3;
-    document.getElementById("debuffmis").checked = (buffOpt2 & 1<<4) == 1<<4;
-    
-    idx++; // water shield procs not implemented
-    document.getElementById("buffspriest").value = buffView.getUint16(idx, true); idx+=2;
-    document.getElementById("sbufrace").selectedIndex = buffView.getUint8(idx, true); idx++;
-
-    var numCustom = buffView.getUint8(idx, true); idx++;
-    if (numCustom > 0) {
-        document.getElementById("custint").value = buffView.getFloat64(7, true);
-        // document.getElementById("custstm").value = buffView.getFloat64(7+8*1);
-        document.getElementById("custsc").value = buffView.getFloat64(7+8*2, true);
-        document.getElementById("custsh").value = buffView.getFloat64(7+8*3, true);
-        document.getElementById("custsp").value = buffView.getFloat64(7+8*4, true);
-        document.getElementById("custha").value = buffView.getFloat64(7+8*5, true);
-        document.getElementById("custmp5").value = buffView.getFloat64(7+8*6, true);
-        document.getElementById("custmana").value = buffView.getFloat64(7+8*7, true);
-        idx += numCustom*8;
-    } else {
-        document.getElementById("custint").value = 0;
-        // document.getElementById("custstm").value = 0;
-        document.getElementById("custsc").value = 0;
-        document.getElementById("custsh").value = 0;
-        document.getElementById("custsp").value = 0;
-        document.getElementById("custha").value = 0;
-        document.getElementById("custmp5").value = 0;
-        document.getElementById("custmana").value = 0;
-    }
-    
-    var consumOpt = buffView.getUint8(idx, true); idx++;
-    document.getElementById("conbwo").checked = (consumOpt & 1) == 1;
-    document.getElementById("conmm").checked = (consumOpt & 1<<1) == 1<<1;
-    document.getElementById("confbl").checked = (consumOpt & 1<<2) == 1<<2;
-    document.getElementById("confmr").checked = (consumOpt & 1<<3) == 1<<3;
-    document.getElementById("conbb").checked = (consumOpt & 1<<4) == 1<<4;
-    document.getElementById("condp").checked = (consumOpt & 1<<5) == 1<<5;
-    document.getElementById("consmp").checked = (consumOpt & 1<<6) == 1<<6;
-    document.getElementById("condr").checked = (consumOpt & 1<<7) == 1<<7;
-
-    // talents
-    idx += 9;
-
-    document.getElementById("totwr").selectedIndex = buffView.getUint8(idx, true); idx++;
-	var totemOpt = buffView.getUint8(idx, true); idx++;
-    document.getElementById("totwoa").checked = (totemOpt & 1) == 1;
-    document.getElementById("totms").checked = (totemOpt & 1<<1) == 1<<1;
-    document.getElementById("totcycl2p").checked = (totemOpt & 1<<2) == 1<<2;
+// setOptions is the reverse of getOptions, for options read from a share link.
+function setOptions(options) {
+    ["buffai", "buffgotw", "buffbk", "buffibow", "buffids", "buffmoon", "buffmoonrg", "buffeyenight", "bufftwilightowl",
+     "sbufws", "debuffjow", "debuffisoc", "debuffmis",
+     "conbwo", "conmm", "confbl", "confmr", "conbb", "condp", "consmp", "condr",
+     "totwoa", "totms", "totcycl2p"].forEach((id)=>{
+        document.getElementById(id).checked = options[id];
+    });
+    ["buffbl", "buffdrums", "sbufrace", "totwr"].forEach((id)=>{
+        document.getElementById(id).selectedIndex = options[id];
+    });
+    document.getElementById("buffspriest").value = options.buffspriest;
+    Object.entries(options.custom).forEach((entry)=>{
+        document.getElementById(entry[0]).value = entry[1];
+    });
 }
 
 var castIDToName = {
@@ -449,161 +408,52 @@ function calcStatWeights(gear) {
     var iters = parseInt(document.getElementById("switer").value);
     var dur = parseInt(document.getElementById("swdur").value);
     var opts = getOptions();
-    
-    var baseDPS = 0.0;
-    var baseConf = 0.0;
-
-    var sp_hitModDPS = 0.0;
-    var sp_hitModConf = 0.0;
 
-    var modDPS = [0, 0, 0, 0, 0, 0]; // SP, Int, Crit, Hit, Haste, MP5
-    var modConf = [0, 0, 0, 0, 0, 0]
-    var weights = [0, 0, 0, 0, 0, 0, 0]; // Int, X, Crit, Hit, Dmg, Haste, MP5
-    modDPS.forEach((v, i)=>{
+    var cellStats = [4, 0, 2, 3, 5, 6]; // Dmg, Int, Crit, Hit, Haste, MP5
+    cellStats.forEach((v, i)=>{
         var cell = document.getElementById("w"+i.toString());
         cell.innerHTML = "<div uk-spinner=\"ratio: 1\"></div>";
     });
 
-    // A base DPS without any modified stats.
-    statweight(iters, dur, gear, opts, 0, 0, (res) => {
-        var resVals = res.split(",")
-        baseDPS = parseFloat(resVals[0]);
-        baseConf = parseFloat(resVals[2]);
-        if (baseDPS < 1) {
+    var progress = document.getElementById("wc0"); // spell power has no range, so show the progress there.
+    statweights(iters, dur, gear, opts, (result) => {
+        progress.innerText = "";
+        if (result.BaseDPS < 1 || result.Weights == null) {
             // we failed.
-            modDPS.forEach((v, i)=>{
+            cellStats.forEach((v, i)=>{
                 var cell = document.getElementById("w"+i.toString());
                 cell.innerHTML = `<text style="color:#FF6961">OOM</text>`;
-            });        
+            });
             var uptab = document.getElementById("upgrades");
             var nr = document.createElement("text");
             nr.innerText = `Simulations went OOM and so weights will be incorrect as downranking is not yet implemented.`;
             uptab.appendChild(nr);
-        }
-        console.log(`Base DPS: ${baseDPS} +/- ${baseConf}`);
-    }); // base
-
-
-    var done = [];
-    var onfinish = () => {
-        done.push(true);
-        if (baseDPS < 1) {
             return;
         }
-        if (modDPS[0] == 0) {
-            return;
-        }
-        var baseMax = baseDPS + baseConf
-        var baseMin = baseDPS - baseConf
-
-        var ddpsMax = (modDPS[0]+modConf[0]) - baseMin;
-        var ddpsMin = (modDPS[0]-modConf[0]) - baseMax;
-
-        modDPS.forEach((v, i)=>{
-            if (v == 0) {
-                return;
-            }
-            var cell = document.getElementById("w"+i.toString());
-            var cellConf = document.getElementById("wc"+i.toString());
-            if (v == -1) {
-                cell.innerHTML = `<text style="color:#FF6961">OOM</text>`;
-                return;
-            }
-            // sphit uses different value;
-            if (i == 3 && sp_hitModDPS != 0.0) {
-                var sphitMax = sp_hitModDPS+sp_hitModConf - baseMin;
-                var sphitMin = sp_hitModDPS-sp_hitModConf - baseMax;
-                
-                var wmax  = (v + modConf[i] - baseMin) / sphitMax;
-                var wmin = (v - modConf[i] - baseMax) / sphitMin;
-                var weight = (wmax + wmin) / 2;
-                if (weight < 0.01) {
-                    weight = 0.0;
-                }
-                if (currentFinalStats[3] > 202) {
-                    weight = 0.0; // Just going to force 0 weight if you are hit capped.
-                }
+        console.log(`Base DPS: ${result.BaseDPS} +/- ${result.BaseStdev}`);
 
-                weights[3] = weight;
-                cell.innerText = weight.toFixed(2);
-                cellConf.innerText = wmin.toFixed(2) + " - " + wmax.toFixed(2);
-                return; 
-            }
-            
-            var wmax  = (v + modConf[i] - baseMin) / ddpsMax;
-            var wmin = (v - modConf[i] - baseMax) / ddpsMin;
-
-            var weight = (wmax + wmin) / 2;
+        var weights = [0, 0, 0, 0, 0, 0, 0]; // Int, X, Crit, Hit, Dmg, Haste, MP5
+        result.Weights.forEach((w) => {
+            var weight = w.Weight;
             if (weight < 0.01) {
                 weight = 0.0;
             }
-            
-            if (i == 0) {
-                weights[4] = weight;
-            } else if (i == 1) {
-                weights[0] = weight;
-            } else if (i == 2) {
-                weights[2] = weight;
-            } else if (i == 4) {
-                weights[5] = weight;
-            } else if (i == 5) {
-                weights[6] = weight;
-            }
-            cell.innerText = weight.toFixed(2);
-            if (wmax != wmin) {
-                cellConf.innerText = wmin.toFixed(2) + " - " + wmax.toFixed(2);
-            }
-        });
+            weights[w.Stat] = weight;
 
-        if (done.length == 7) {
-            var oomed = true;
-            modDPS.forEach((v) => {
-                if (v > 0) {
-                    oomed = false;
-                }
-            });
-            if (oomed) {
+            var i = cellStats.indexOf(w.Stat);
+            if (i == -1) {
                 return;
             }
-            showGearRecommendations(weights);
-        }
-    };
-
-    statweight(iters, dur, gear, opts, 4, 20, (res) => {
-        var resVals = res.split(",");
-        sp_hitModDPS = parseFloat(resVals[0]);
-        sp_hitModConf = parseFloat(resVals[2]);
-        onfinish();}); // sp
-    statweight(iters, dur, gear, opts, 3, 20, (res) => {
-        var resVals = res.split(",");
-        modConf[3] = parseFloat(resVals[2])
-        modDPS[3] = parseFloat(resVals[0]);
-        onfinish();}); // hit
-    statweight(iters, dur, gear, opts, 4, 50, (res) => {
-        var resVals = res.split(",");
-        modConf[0] = parseFloat(resVals[2])
-        modDPS[0] = parseFloat(resVals[0]);
-        onfinish();}); // sp
-    statweight(iters, dur, gear, opts, 0, 50, (res) => {
-        var resVals = res.split(",");
-        modConf[1] = parseFloat(resVals[2])
-        modDPS[1] = parseFloat(resVals[0]);
-        onfinish();}); // int
-    statweight(iters, dur, gear, opts, 2, 50, (res) => {
-        var resVals = res.split(",");
-        modConf[2] = parseFloat(resVals[2])
-        modDPS[2] = parseFloat(resVals[0]);
-        onfinish();}); // crit
-    statweight(iters, dur, gear, opts, 5, 50, (res) => {
-        var resVals = res.split(",");
-        modConf[4] = parseFloat(resVals[2])
-        modDPS[4] = parseFloat(resVals[0]);
-        onfinish();}); // haste
-    statweight(iters, dur, gear, opts, 6, 50, (res) => {
-        var resVals = res.split(",");
-        modConf[5] = parseFloat(resVals[2])
-        modDPS[5] = parseFloat(resVals[0]);
-        onfinish();}); // mp5
+            document.getElementById("w"+i.toString()).innerText = weight.toFixed(2);
+            if (w.Stat != 4) {
+                var conf90 = 1.645 * w.WeightErr;
+                document.getElementById("wc"+i.toString()).innerText = (w.Weight - conf90).toFixed(2) + " - " + (w.Weight + conf90).toFixed(2);
+            }
+        });
+        showGearRecommendations(weights);
+    }, (p) => {
+        progress.innerText = `${Math.round(100 * p.iterations / p.total)}%`;
+    });
 }
 
 function showGearRecommendations(weights) {
@@ -709,6 +559,8 @@ function showGearRecommendations(weights) {
                             item.GemSlots.forEach((color, i) => {             
                                 if (color == 1) {
                                     item.Gems[i] = gearUI.allgems["Chaotic Skyfire Diamond"];
+                                } else if (color == 3) {
+                                    item.Gems[i] = gearUI.allgems["Glowing Nightseye"]; // counts as blue for the meta gem
                                 } else {
                                     item.Gems[i] = gearUI.allgems["Runed Living Ruby"];
                                 }                                    
@@ -902,7 +754,8 @@ function updateGearStats(gearlist) {
         currentFinalStats = result.Stats;
         var sets = result.Sets;
         var setlist = document.getElementById("setlist");
-        setlist.innerHTML = sets.join("<br />");
+        var warnings = (result.Warnings || []).map((w) => "<span class=\"uk-text-danger\">" + w + "</span>");
+        setlist.innerHTML = warnings.concat(sets).join("<br />");
         for (const [key, value] of Object.entries(currentFinalStats)) {
             var lab = document.getElementById("f"+statToName[key].toLowerCase());
             if (key == 2) {
@@ -1110,17 +963,36 @@ function importGearHandler() {
 
 function importGear(inputVal) {
     var gearCache = inputVal;
-    if (inputVal[0] != "[") { // that is opening brace for a gear list in JSON, but not valid base64
-        if (window.pako === undefined) {
-            loadPako(()=>{
-                importGear(inputVal); // try again
-            });
-            return;
-        } else {
-            var infdata = pakoInflate(inputVal);
-            gearCache = infdata.gear;
-            setOptions(infdata.buffs);
-        }
+    var trimmed = inputVal.trim();
+    if (trimmed[0] == "{" || trimmed.includes("item:") || trimmed.includes(",id=")) {
+        // Export from a gear planner or addon
+        importPlannerGear(trimmed, (out) => {
+            if (out.error != null) {
+                UIkit.notification({message: "Failed to import gear: " + out.error, status: "danger"});
+                return;
+            }
+            if (out.Missing.length > 0) {
+                UIkit.notification({message: "Not imported:<br />" + out.Missing.join("<br />"), status: "warning", timeout: 10000});
+            }
+            var currentGear = gearUI.updateEquipped(out.Gear);
+            updateGearStats(currentGear);
+        });
+        return;
+    }
+    if (trimmed[0] != "[") { // that is opening brace for a gear list in JSON, anything else is a share link
+        parseLink(trimmed, (out) => {
+            if (out.error != null) {
+                UIkit.notification({message: "Failed to read link: " + out.error, status: "danger"});
+                return;
+            }
+            if (out.Problems.length > 0) {
+                UIkit.notification({message: "Problems with the link:<br />" + out.Problems.join("<br />"), status: "warning", timeout: 10000});
+            }
+            setOptions(out.Options);
+            var currentGear = gearUI.updateEquipped(out.Gear);
+            updateGearStats(currentGear);
+        });
+        return;
     }
     if (gearCache && gearCache.length > 0) {
         var parsedGear = JSON.parse(gearCache);
@@ -1131,20 +1003,6 @@ function importGear(inputVal) {
     }
 }
 
-function pakoInflate(v) {
-    var binary = atob(v);
-    var bytes = new Uint8Array(binary.length);
-    for (let i = 0; i < bytes.length; i++) {
-      bytes[i] = binary.charCodeAt(i);
-    }
-    // var bytes = base2048.decode(v);
-    var dv = new DataView(bytes.buffer);
-    var leng = dv.getInt32(0);
-
-    return {gear: pako.inflate(bytes.subarray(4, leng+4), {to:'string'}), buffs: bytes.subarray(leng+4, bytes.length)};
-}
-
-
 var currentHash = "";
 
 function exportGear(compressed) {
@@ -1152,30 +1010,13 @@ function exportGear(compressed) {
     var box = document.getElementById("importexport");
     var enc = JSON.stringify(cleanedGear);
     if (compressed) {
-        if (window.pako === undefined) {
-            loadPako(()=>{
-                exportGear(compressed); // try again
-            });
-            return;
-        } else {
-            packOptions(getOptions(), (r)=>{
-                var val = pako.deflate(enc, { to: 'string' });
-                var mergedArray = new Uint8Array(val.length + r.length+4);
-                var dv = new DataView(mergedArray.buffer);
-                dv.setInt32(0, val.length);
-                mergedArray.set(val, 4);
-                mergedArray.set(r, 4+val.length);
-                // var output = base2048.encode(mergedArray);
-                var output = btoa(String.fromCharCode(...mergedArray));
-                // console.log(`JSON Size: ${enc.length}, Bin Size: ${mergedArray.length}, b2048: ${output.length}`);    
-                // box.value = output;
-                if (currentHash != output) {
-                    currentHash = output;
-                    location.hash = output;
-                }
-            });
-            return;
-        }
+        shareLink(cleanedGear, getOptions(), (output)=>{
+            if (currentHash != output) {
+                currentHash = output;
+                location.hash = output;
+            }
+        });
+        return;
     }
     box.value = enc;
 }
@@ -1192,10 +1033,3 @@ function getQueryVariable(variable) {
 
     return "";
 }
-
-function loadPako(onLoad) {
-    var script = document.createElement('script');
-    script.onload = onLoad
-    script.src = "pako.js";
-    document.head.appendChild(script);
-}
\ No newline at end of file
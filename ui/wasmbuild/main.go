@@ -0,0 +1,40 @@
+// Command wasmbuild builds ui/lib.wasm and copies the wasm_exec.js of the same Go toolchain next to it.
+// The JS runtime has to match the Go version that built the wasm, so the two are always updated together.
+// Run it from the repository root, or with go generate . there.
+package main
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	build := exec.Command("go", "build", "-o", filepath.Join("ui", "lib.wasm"), "./ui")
+	build.Env = append(os.Environ(), "GOOS=js", "GOARCH=wasm")
+	build.Stdout, build.Stderr = os.Stdout, os.Stderr
+	if err := build.Run(); err != nil {
+		log.Fatalf("Failed to build the wasm: %s", err)
+	}
+
+	out, err := exec.Command("go", "env", "GOROOT").Output()
+	if err != nil {
+		log.Fatalf("Failed to find GOROOT: %s", err)
+	}
+	root := strings.TrimSpace(string(out))
+	// Newer toolchains keep it in lib/wasm, older ones in misc/wasm.
+	for _, dir := range []string{"lib", "misc"} {
+		runtime, err := ioutil.ReadFile(filepath.Join(root, dir, "wasm", "wasm_exec.js"))
+		if err != nil {
+			continue
+		}
+		if err := ioutil.WriteFile(filepath.Join("ui", "wasm_exec.js"), runtime, 0644); err != nil {
+			log.Fatalf("Failed to write wasm_exec.js: %s", err)
+		}
+		return
+	}
+	log.Fatalf("No wasm_exec.js in %s", root)
+}
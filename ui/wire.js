@@ -0,0 +1,1148 @@
+// Code generated by go generate ./api; DO NOT EDIT.
+
+// wire decodes and encodes the binary messages of the sim, see package wire.
+var wire = (() => {
+	const version = 1;
+	const textDecoder = new TextDecoder();
+	const textEncoder = new TextEncoder();
+
+	class Reader {
+		constructor(bytes, fingerprint) {
+			this.b = bytes;
+			this.v = new DataView(bytes.buffer, bytes.byteOffset, bytes.byteLength);
+			this.p = 0;
+			if (this.byte() != version) {
+				throw new Error("wire: message is of another version of the format");
+			}
+			this.need(4);
+			if (this.v.getUint32(this.p, true) != fingerprint) {
+				throw new Error("wire: message is of a different type, or wire.js is out of date");
+			}
+			this.p += 4;
+		}
+		need(n) {
+			if (this.p + n > this.b.length) {
+				throw new Error("wire: message is cut short");
+			}
+		}
+		end() {
+			if (this.p != this.b.length) {
+				throw new Error("wire: " + (this.b.length - this.p) + " bytes left over");
+			}
+		}
+		byte() {
+			this.need(1);
+			return this.b[this.p++];
+		}
+		bool() {
+			return this.byte() != 0;
+		}
+		uvarint() {
+			let x = 0, scale = 1, b;
+			do {
+				b = this.byte();
+				x += (b & 0x7f) * scale;
+				scale *= 128;
+			} while (b & 0x80);
+			return x;
+		}
+		varint() {
+			const u = this.uvarint();
+			return u % 2 ? -(u + 1) / 2 : u / 2;
+		}
+		float32() {
+			this.need(4);
+			const x = this.v.getFloat32(this.p, true);
+			this.p += 4;
+			return x;
+		}
+		float64() {
+			this.need(8);
+			const x = this.v.getFloat64(this.p, true);
+			this.p += 8;
+			return x;
+		}
+		string() {
+			const n = this.uvarint();
+			this.need(n);
+			const s = textDecoder.decode(this.b.subarray(this.p, this.p + n));
+			this.p += n;
+			return s;
+		}
+		bytes() {
+			const n = this.uvarint();
+			if (n == 0) {
+				return null;
+			}
+			this.need(n - 1);
+			const out = this.b.slice(this.p, this.p + n - 1);
+			this.p += n - 1;
+			return out;
+		}
+		list(read) {
+			const n = this.uvarint();
+			if (n == 0) {
+				return null;
+			}
+			const out = new Array(n - 1);
+			for (let i = 0; i < n - 1; i++) {
+				out[i] = read(this);
+			}
+			return out;
+		}
+		array(n, read) {
+			const out = new Array(n);
+			for (let i = 0; i < n; i++) {
+				out[i] = read(this);
+			}
+			return out;
+		}
+		map(readKey, readValue) {
+			const n = this.uvarint();
+			if (n == 0) {
+				return null;
+			}
+			const out = {};
+			for (let i = 0; i < n - 1; i++) {
+				const k = readKey(this);
+				out[k] = readValue(this);
+			}
+			return out;
+		}
+		ptr(read) {
+			return this.bool() ? read(this) : null;
+		}
+	}
+
+	// Writer takes what JSON would: numbers as strings, and null or missing values as the zero value.
+	class Writer {
+		constructor(fingerprint) {
+			this.b = new Uint8Array(256);
+			this.v = new DataView(this.b.buffer);
+			this.p = 0;
+			this.byte(version);
+			this.grow(4);
+			this.v.setUint32(this.p, fingerprint, true);
+			this.p += 4;
+		}
+		grow(n) {
+			if (this.p + n <= this.b.length) {
+				return;
+			}
+			let size = this.b.length * 2;
+			while (size < this.p + n) {
+				size *= 2;
+			}
+			const b = new Uint8Array(size);
+			b.set(this.b);
+			this.b = b;
+			this.v = new DataView(b.buffer);
+		}
+		done() {
+			return this.b.slice(0, this.p);
+		}
+		byte(x) {
+			this.grow(1);
+			this.b[this.p++] = x;
+		}
+		bool(x) {
+			this.byte(x ? 1 : 0);
+		}
+		uvarint(x) {
+			x = Math.max(0, Math.trunc(Number(x)) || 0);
+			while (x >= 128) {
+				this.byte(x % 128 + 128);
+				x = Math.floor(x / 128);
+			}
+			this.byte(x);
+		}
+		varint(x) {
+			x = Math.trunc(Number(x)) || 0;
+			this.uvarint(x < 0 ? -2 * x - 1 : 2 * x);
+		}
+		float32(x) {
+			this.grow(4);
+			this.v.setFloat32(this.p, Number(x) || 0, true);
+			this.p += 4;
+		}
+		float64(x) {
+			this.grow(8);
+			this.v.setFloat64(this.p, Number(x) || 0, true);
+			this.p += 8;
+		}
+		string(x) {
+			const s = textEncoder.encode(x == null ? "" : String(x));
+			this.uvarint(s.length);
+			this.grow(s.length);
+			this.b.set(s, this.p);
+			this.p += s.length;
+		}
+		bytes(x) {
+			if (x == null) {
+				this.byte(0);
+				return;
+			}
+			this.uvarint(x.length + 1);
+			this.grow(x.length);
+			this.b.set(x, this.p);
+			this.p += x.length;
+		}
+		list(x, write) {
+			if (x == null) {
+				this.byte(0);
+				return;
+			}
+			this.uvarint(x.length + 1);
+			for (const v of x) {
+				write(this, v);
+			}
+		}
+		array(n, x, write) {
+			for (let i = 0; i < n; i++) {
+				write(this, x == null ? undefined : x[i]);
+			}
+		}
+		map(x, writeKey, writeValue) {
+			if (x == null) {
+				this.byte(0);
+				return;
+			}
+			const keys = Object.keys(x);
+			this.uvarint(keys.length + 1);
+			for (const k of keys) {
+				writeKey(this, k);
+				writeValue(this, x[k]);
+			}
+		}
+		ptr(x, write) {
+			if (x == null) {
+				this.byte(0);
+				return;
+			}
+			this.byte(1);
+			write(this, x);
+		}
+	}
+
+	function read_api_GearItem(r) {
+		const o = {};
+		{
+			const v = r.string();
+			if (v !== "") {
+				o.Name = v;
+			}
+		}
+		{
+			const v = r.varint();
+			if (v !== 0) {
+				o.ID = v;
+			}
+		}
+		{
+			const v = r.list((r) => r.string());
+			if (v !== null && v.length > 0) {
+				o.Gems = v;
+			}
+		}
+		{
+			const v = r.string();
+			if (v !== "") {
+				o.Enchant = v;
+			}
+		}
+		{
+			const v = r.list((r) => r.varint());
+			if (v !== null && v.length > 0) {
+				o.g = v;
+			}
+		}
+		{
+			const v = r.varint();
+			if (v !== 0) {
+				o.e = v;
+			}
+		}
+		return o;
+	}
+
+	function write_api_GearItem(w, o) {
+		o = o || {};
+		w.string(o.Name);
+		w.varint(o.ID);
+		w.list(o.Gems, (w, v) => w.string(v));
+		w.string(o.Enchant);
+		w.list(o.g, (w, v) => w.varint(v));
+		w.varint(o.e);
+	}
+
+	function read_api_CustomStats(r) {
+		const o = {};
+		o.custint = r.float64();
+		o.custsc = r.float64();
+		o.custsh = r.float64();
+		o.custsp = r.float64();
+		o.custha = r.float64();
+		o.custmp5 = r.float64();
+		o.custmana = r.float64();
+		return o;
+	}
+
+	function write_api_CustomStats(w, o) {
+		o = o || {};
+		w.float64(o.custint);
+		w.float64(o.custsc);
+		w.float64(o.custsh);
+		w.float64(o.custsp);
+		w.float64(o.custha);
+		w.float64(o.custmp5);
+		w.float64(o.custmana);
+	}
+
+	function read_api_Options(r) {
+		const o = {};
+		o.exitoom = r.bool();
+		o.useai = r.bool();
+		o.buffbl = r.varint();
+		o.buffdrums = r.varint();
+		o.buffai = r.bool();
+		o.buffgotw = r.bool();
+		o.buffbk = r.bool();
+		o.buffibow = r.bool();
+		o.buffids = r.bool();
+		o.debuffjow = r.bool();
+		o.debuffisoc = r.bool();
+		o.debuffmis = r.bool();
+		o.buffmoon = r.bool();
+		o.buffmoonrg = r.bool();
+		o.buffspriest = r.uvarint();
+		o.sbufws = r.bool();
+		o.buffeyenight = r.bool();
+		o.bufftwilightowl = r.bool();
+		o.sbufrace = r.varint();
+		o.custom = read_api_CustomStats(r);
+		o.confbl = r.bool();
+		o.confmr = r.bool();
+		o.conbwo = r.bool();
+		o.conmm = r.bool();
+		o.conbb = r.bool();
+		o.condp = r.bool();
+		o.consmp = r.bool();
+		o.condr = r.bool();
+		o.totwr = r.varint();
+		o.totwoa = r.bool();
+		o.totcycl2p = r.bool();
+		o.totms = r.bool();
+		o.dpsReportTime = r.varint();
+		return o;
+	}
+
+	function write_api_Options(w, o) {
+		o = o || {};
+		w.bool(o.exitoom);
+		w.bool(o.useai);
+		w.varint(o.buffbl);
+		w.varint(o.buffdrums);
+		w.bool(o.buffai);
+		w.bool(o.buffgotw);
+		w.bool(o.buffbk);
+		w.bool(o.buffibow);
+		w.bool(o.buffids);
+		w.bool(o.debuffjow);
+		w.bool(o.debuffisoc);
+		w.bool(o.debuffmis);
+		w.bool(o.buffmoon);
+		w.bool(o.buffmoonrg);
+		w.uvarint(o.buffspriest);
+		w.bool(o.sbufws);
+		w.bool(o.buffeyenight);
+		w.bool(o.bufftwilightowl);
+		w.varint(o.sbufrace);
+		write_api_CustomStats(w, o.custom);
+		w.bool(o.confbl);
+		w.bool(o.confmr);
+		w.bool(o.conbwo);
+		w.bool(o.conmm);
+		w.bool(o.conbb);
+		w.bool(o.condp);
+		w.bool(o.consmp);
+		w.bool(o.condr);
+		w.varint(o.totwr);
+		w.bool(o.totwoa);
+		w.bool(o.totcycl2p);
+		w.bool(o.totms);
+		w.varint(o.dpsReportTime);
+	}
+
+	function read_api_SimulateRequest(r) {
+		const o = {};
+		o.iters = r.varint();
+		o.dur = r.varint();
+		o.gearlist = r.list((r) => read_api_GearItem(r));
+		o.opts = read_api_Options(r);
+		{
+			const v = r.varint();
+			if (v !== 0) {
+				o.seed = v;
+			}
+		}
+		{
+			const v = r.list((r) => r.list((r) => r.string()));
+			if (v !== null && v.length > 0) {
+				o.rots = v;
+			}
+		}
+		{
+			const v = r.float64();
+			if (v !== 0) {
+				o.haste = v;
+			}
+		}
+		{
+			const v = r.bool();
+			if (v) {
+				o.fullLogs = v;
+			}
+		}
+		return o;
+	}
+
+	function write_api_SimulateRequest(w, o) {
+		o = o || {};
+		w.varint(o.iters);
+		w.varint(o.dur);
+		w.list(o.gearlist, (w, v) => write_api_GearItem(w, v));
+		write_api_Options(w, o.opts);
+		w.varint(o.seed);
+		w.list(o.rots, (w, v) => w.list(v, (w, v) => w.string(v)));
+		w.float64(o.haste);
+		w.bool(o.fullLogs);
+	}
+
+	function read_tbc_DPSPercentiles(r) {
+		const o = {};
+		o.p5 = r.float64();
+		o.p25 = r.float64();
+		o.p50 = r.float64();
+		o.p75 = r.float64();
+		o.p95 = r.float64();
+		return o;
+	}
+
+	function write_tbc_DPSPercentiles(w, o) {
+		o = o || {};
+		w.float64(o.p5);
+		w.float64(o.p25);
+		w.float64(o.p50);
+		w.float64(o.p75);
+		w.float64(o.p95);
+	}
+
+	function read_tbc_CastMetric(r) {
+		const o = {};
+		o.name = r.string();
+		o.count = r.varint();
+		o.dmg = r.float64();
+		o.crits = r.varint();
+		return o;
+	}
+
+	function write_tbc_CastMetric(w, o) {
+		o = o || {};
+		w.string(o.name);
+		w.varint(o.count);
+		w.float64(o.dmg);
+		w.varint(o.crits);
+	}
+
+	function read_tbc_ConsumeMetric(r) {
+		const o = {};
+		o.destructionPotion = r.float64();
+		o.superManaPotion = r.float64();
+		o.darkRune = r.float64();
+		return o;
+	}
+
+	function write_tbc_ConsumeMetric(w, o) {
+		o = o || {};
+		w.float64(o.destructionPotion);
+		w.float64(o.superManaPotion);
+		w.float64(o.darkRune);
+	}
+
+	function read_tbc_SimResult(r) {
+		const o = {};
+		o.Rotation = r.list((r) => r.string());
+		o.SimSeconds = r.varint();
+		o.RealDuration = r.float64();
+		o.Logs = r.string();
+		o.iterations = r.varint();
+		o.dps = r.float64();
+		o.dev = r.float64();
+		o.min = r.float64();
+		o.max = r.float64();
+		o.percentiles = read_tbc_DPSPercentiles(r);
+		o.dpsHist = r.map((r) => r.varint(), (r) => r.varint());
+		o.numOOM = r.varint();
+		o.oomat = r.float64();
+		o.dpsAtOOM = r.float64();
+		o.casts = r.map((r) => r.varint(), (r) => read_tbc_CastMetric(r));
+		o.consumed = read_tbc_ConsumeMetric(r);
+		return o;
+	}
+
+	function write_tbc_SimResult(w, o) {
+		o = o || {};
+		w.list(o.Rotation, (w, v) => w.string(v));
+		w.varint(o.SimSeconds);
+		w.float64(o.RealDuration);
+		w.string(o.Logs);
+		w.varint(o.iterations);
+		w.float64(o.dps);
+		w.float64(o.dev);
+		w.float64(o.min);
+		w.float64(o.max);
+		write_tbc_DPSPercentiles(w, o.percentiles);
+		w.map(o.dpsHist, (w, v) => w.varint(v), (w, v) => w.varint(v));
+		w.varint(o.numOOM);
+		w.float64(o.oomat);
+		w.float64(o.dpsAtOOM);
+		w.map(o.casts, (w, v) => w.varint(v), (w, v) => write_tbc_CastMetric(w, v));
+		write_tbc_ConsumeMetric(w, o.consumed);
+	}
+
+	function read_api_StatWeightsRequest(r) {
+		const o = {};
+		o.iters = r.varint();
+		o.dur = r.varint();
+		o.gearlist = r.list((r) => read_api_GearItem(r));
+		o.opts = read_api_Options(r);
+		{
+			const v = r.varint();
+			if (v !== 0) {
+				o.seed = v;
+			}
+		}
+		{
+			const v = r.float64();
+			if (v !== 0) {
+				o.delta = v;
+			}
+		}
+		{
+			const v = r.bool();
+			if (v) {
+				o.central = v;
+			}
+		}
+		return o;
+	}
+
+	function write_api_StatWeightsRequest(w, o) {
+		o = o || {};
+		w.varint(o.iters);
+		w.varint(o.dur);
+		w.list(o.gearlist, (w, v) => write_api_GearItem(w, v));
+		write_api_Options(w, o.opts);
+		w.varint(o.seed);
+		w.float64(o.delta);
+		w.bool(o.central);
+	}
+
+	function read_tbc_StatWeight(r) {
+		const o = {};
+		o.Stat = r.uvarint();
+		o.Delta = r.float64();
+		o.DPS = r.float64();
+		o.DPSErr = r.float64();
+		o.Weight = r.float64();
+		o.WeightErr = r.float64();
+		return o;
+	}
+
+	function write_tbc_StatWeight(w, o) {
+		o = o || {};
+		w.uvarint(o.Stat);
+		w.float64(o.Delta);
+		w.float64(o.DPS);
+		w.float64(o.DPSErr);
+		w.float64(o.Weight);
+		w.float64(o.WeightErr);
+	}
+
+	function read_tbc_StatWeightsResult(r) {
+		const o = {};
+		o.BaseDPS = r.float64();
+		o.BaseStdev = r.float64();
+		o.Iterations = r.varint();
+		o.Weights = r.list((r) => read_tbc_StatWeight(r));
+		return o;
+	}
+
+	function write_tbc_StatWeightsResult(w, o) {
+		o = o || {};
+		w.float64(o.BaseDPS);
+		w.float64(o.BaseStdev);
+		w.varint(o.Iterations);
+		w.list(o.Weights, (w, v) => write_tbc_StatWeight(w, v));
+	}
+
+	function read_api_ComputeStatsRequest(r) {
+		const o = {};
+		o.gear = r.list((r) => read_api_GearItem(r));
+		o.opts = r.ptr((r) => read_api_Options(r));
+		return o;
+	}
+
+	function write_api_ComputeStatsRequest(w, o) {
+		o = o || {};
+		w.list(o.gear, (w, v) => write_api_GearItem(w, v));
+		w.ptr(o.opts, (w, v) => write_api_Options(w, v));
+	}
+
+	function read_api_ComputeStatsResult(r) {
+		const o = {};
+		o.Stats = r.list((r) => r.float64());
+		o.Sets = r.list((r) => r.string());
+		o.Warnings = r.list((r) => r.string());
+		return o;
+	}
+
+	function write_api_ComputeStatsResult(w, o) {
+		o = o || {};
+		w.list(o.Stats, (w, v) => w.float64(v));
+		w.list(o.Sets, (w, v) => w.string(v));
+		w.list(o.Warnings, (w, v) => w.string(v));
+	}
+
+	function read_tbc_ItemFilter(r) {
+		const o = {};
+		o.Slots = r.bytes();
+		o.MaxPhase = r.uvarint();
+		o.MinQuality = r.uvarint();
+		o.MinLevel = r.varint();
+		o.Zones = r.list((r) => r.string());
+		o.ExcludeZones = r.list((r) => r.string());
+		o.ArmorTypes = r.bytes();
+		o.Professions = r.bytes();
+		o.NoShields = r.bool();
+		o.NoTwoHanders = r.bool();
+		return o;
+	}
+
+	function write_tbc_ItemFilter(w, o) {
+		o = o || {};
+		w.bytes(o.Slots);
+		w.uvarint(o.MaxPhase);
+		w.uvarint(o.MinQuality);
+		w.varint(o.MinLevel);
+		w.list(o.Zones, (w, v) => w.string(v));
+		w.list(o.ExcludeZones, (w, v) => w.string(v));
+		w.bytes(o.ArmorTypes);
+		w.bytes(o.Professions);
+		w.bool(o.NoShields);
+		w.bool(o.NoTwoHanders);
+	}
+
+	function read_tbc_MetaRequirement(r) {
+		const o = {};
+		{
+			const v = r.varint();
+			if (v !== 0) {
+				o.Red = v;
+			}
+		}
+		{
+			const v = r.varint();
+			if (v !== 0) {
+				o.Yellow = v;
+			}
+		}
+		{
+			const v = r.varint();
+			if (v !== 0) {
+				o.Blue = v;
+			}
+		}
+		{
+			const v = r.uvarint();
+			if (v !== 0) {
+				o.More = v;
+			}
+		}
+		{
+			const v = r.uvarint();
+			if (v !== 0) {
+				o.Than = v;
+			}
+		}
+		return o;
+	}
+
+	function write_tbc_MetaRequirement(w, o) {
+		o = o || {};
+		w.varint(o.Red);
+		w.varint(o.Yellow);
+		w.varint(o.Blue);
+		w.uvarint(o.More);
+		w.uvarint(o.Than);
+	}
+
+	function read_tbc_Gem(r) {
+		const o = {};
+		o.ID = r.varint();
+		o.Name = r.string();
+		o.Stats = r.list((r) => r.float64());
+		o.Color = r.uvarint();
+		o.Phase = r.uvarint();
+		o.Quality = r.uvarint();
+		{
+			const v = r.bool();
+			if (v) {
+				o.Unique = v;
+			}
+		}
+		o.Requires = read_tbc_MetaRequirement(r);
+		return o;
+	}
+
+	function write_tbc_Gem(w, o) {
+		o = o || {};
+		w.varint(o.ID);
+		w.string(o.Name);
+		w.list(o.Stats, (w, v) => w.float64(v));
+		w.uvarint(o.Color);
+		w.uvarint(o.Phase);
+		w.uvarint(o.Quality);
+		w.bool(o.Unique);
+		write_tbc_MetaRequirement(w, o.Requires);
+	}
+
+	function read_tbc_Enchant(r) {
+		const o = {};
+		o.ID = r.varint();
+		{
+			const v = r.varint();
+			if (v !== 0) {
+				o.EffectID = v;
+			}
+		}
+		o.Name = r.string();
+		o.Bonus = r.list((r) => r.float64());
+		o.Slot = r.uvarint();
+		return o;
+	}
+
+	function write_tbc_Enchant(w, o) {
+		o = o || {};
+		w.varint(o.ID);
+		w.varint(o.EffectID);
+		w.string(o.Name);
+		w.list(o.Bonus, (w, v) => w.float64(v));
+		w.uvarint(o.Slot);
+	}
+
+	function read_tbc_Item(r) {
+		const o = {};
+		o.ID = r.varint();
+		o.Slot = r.uvarint();
+		{
+			const v = r.uvarint();
+			if (v !== 0) {
+				o.subSlot = v;
+			}
+		}
+		o.Name = r.string();
+		o.SourceZone = r.string();
+		o.SourceDrop = r.string();
+		o.Stats = r.list((r) => r.float64());
+		o.Phase = r.uvarint();
+		o.Quality = r.uvarint();
+		o.ItemLevel = r.varint();
+		{
+			const v = r.uvarint();
+			if (v !== 0) {
+				o.ArmorType = v;
+			}
+		}
+		{
+			const v = r.uvarint();
+			if (v !== 0) {
+				o.Binding = v;
+			}
+		}
+		{
+			const v = r.uvarint();
+			if (v !== 0) {
+				o.Profession = v;
+			}
+		}
+		{
+			const v = r.bool();
+			if (v) {
+				o.Unique = v;
+			}
+		}
+		o.GemSlots = r.bytes();
+		o.SocketBonus = r.list((r) => r.float64());
+		o.Gems = r.list((r) => read_tbc_Gem(r));
+		o.Enchant = read_tbc_Enchant(r);
+		return o;
+	}
+
+	function write_tbc_Item(w, o) {
+		o = o || {};
+		w.varint(o.ID);
+		w.uvarint(o.Slot);
+		w.uvarint(o.subSlot);
+		w.string(o.Name);
+		w.string(o.SourceZone);
+		w.string(o.SourceDrop);
+		w.list(o.Stats, (w, v) => w.float64(v));
+		w.uvarint(o.Phase);
+		w.uvarint(o.Quality);
+		w.varint(o.ItemLevel);
+		w.uvarint(o.ArmorType);
+		w.uvarint(o.Binding);
+		w.uvarint(o.Profession);
+		w.bool(o.Unique);
+		w.bytes(o.GemSlots);
+		w.list(o.SocketBonus, (w, v) => w.float64(v));
+		w.list(o.Gems, (w, v) => write_tbc_Gem(w, v));
+		write_tbc_Enchant(w, o.Enchant);
+	}
+
+	function read_api_GearListResult(r) {
+		const o = {};
+		o.Items = r.list((r) => read_tbc_Item(r));
+		o.Gems = r.list((r) => read_tbc_Gem(r));
+		o.Enchants = r.list((r) => read_tbc_Enchant(r));
+		return o;
+	}
+
+	function write_api_GearListResult(w, o) {
+		o = o || {};
+		w.list(o.Items, (w, v) => write_tbc_Item(w, v));
+		w.list(o.Gems, (w, v) => write_tbc_Gem(w, v));
+		w.list(o.Enchants, (w, v) => write_tbc_Enchant(w, v));
+	}
+
+	function read_api_GemsRequest(r) {
+		const o = {};
+		o.iters = r.varint();
+		o.dur = r.varint();
+		o.gearlist = r.list((r) => read_api_GearItem(r));
+		o.opts = read_api_Options(r);
+		{
+			const v = r.varint();
+			if (v !== 0) {
+				o.seed = v;
+			}
+		}
+		o.MaxPhase = r.uvarint();
+		o.MinQuality = r.uvarint();
+		o.Weights = r.list((r) => r.float64());
+		o.Confirm = r.varint();
+		return o;
+	}
+
+	function write_api_GemsRequest(w, o) {
+		o = o || {};
+		w.varint(o.iters);
+		w.varint(o.dur);
+		w.list(o.gearlist, (w, v) => write_api_GearItem(w, v));
+		write_api_Options(w, o.opts);
+		w.varint(o.seed);
+		w.uvarint(o.MaxPhase);
+		w.uvarint(o.MinQuality);
+		w.list(o.Weights, (w, v) => w.float64(v));
+		w.varint(o.Confirm);
+	}
+
+	function read_tbc_GemCandidate(r) {
+		const o = {};
+		o.Equip = r.list((r) => read_tbc_Item(r));
+		o.Score = r.float64();
+		o.DPS = r.float64();
+		o.Stdev = r.float64();
+		o.Iterations = r.varint();
+		return o;
+	}
+
+	function write_tbc_GemCandidate(w, o) {
+		o = o || {};
+		w.list(o.Equip, (w, v) => write_tbc_Item(w, v));
+		w.float64(o.Score);
+		w.float64(o.DPS);
+		w.float64(o.Stdev);
+		w.varint(o.Iterations);
+	}
+
+	function read_tbc_GemOptimizerResult(r) {
+		const o = {};
+		o.Equip = r.list((r) => read_tbc_Item(r));
+		o.DPS = r.float64();
+		o.BaseDPS = r.float64();
+		o.Delta = r.float64();
+		o.Candidates = r.list((r) => read_tbc_GemCandidate(r));
+		return o;
+	}
+
+	function write_tbc_GemOptimizerResult(w, o) {
+		o = o || {};
+		w.list(o.Equip, (w, v) => write_tbc_Item(w, v));
+		w.float64(o.DPS);
+		w.float64(o.BaseDPS);
+		w.float64(o.Delta);
+		w.list(o.Candidates, (w, v) => write_tbc_GemCandidate(w, v));
+	}
+
+	function read_api_UpgradesRequest(r) {
+		const o = {};
+		o.iters = r.varint();
+		o.dur = r.varint();
+		o.gearlist = r.list((r) => read_api_GearItem(r));
+		o.opts = read_api_Options(r);
+		{
+			const v = r.varint();
+			if (v !== 0) {
+				o.seed = v;
+			}
+		}
+		o.MaxPhase = r.uvarint();
+		o.MinQuality = r.uvarint();
+		o.MinLevel = r.varint();
+		o.Zones = r.list((r) => r.string());
+		o.ExcludeZones = r.list((r) => r.string());
+		o.ArmorTypes = r.bytes();
+		o.Professions = r.bytes();
+		o.NoShields = r.bool();
+		o.NoTwoHanders = r.bool();
+		o.Slots = r.bytes();
+		o.TopN = r.varint();
+		o.Weights = r.list((r) => r.float64());
+		return o;
+	}
+
+	function write_api_UpgradesRequest(w, o) {
+		o = o || {};
+		w.varint(o.iters);
+		w.varint(o.dur);
+		w.list(o.gearlist, (w, v) => write_api_GearItem(w, v));
+		write_api_Options(w, o.opts);
+		w.varint(o.seed);
+		w.uvarint(o.MaxPhase);
+		w.uvarint(o.MinQuality);
+		w.varint(o.MinLevel);
+		w.list(o.Zones, (w, v) => w.string(v));
+		w.list(o.ExcludeZones, (w, v) => w.string(v));
+		w.bytes(o.ArmorTypes);
+		w.bytes(o.Professions);
+		w.bool(o.NoShields);
+		w.bool(o.NoTwoHanders);
+		w.bytes(o.Slots);
+		w.varint(o.TopN);
+		w.list(o.Weights, (w, v) => w.float64(v));
+	}
+
+	function read_tbc_Upgrade(r) {
+		const o = {};
+		o.Item = read_tbc_Item(r);
+		o.Slot = r.uvarint();
+		o.DPS = r.float64();
+		o.Delta = r.float64();
+		o.DeltaErr = r.float64();
+		o.Iterations = r.varint();
+		{
+			const v = r.list((r) => r.string());
+			if (v !== null && v.length > 0) {
+				o.SetsGained = v;
+			}
+		}
+		{
+			const v = r.list((r) => r.string());
+			if (v !== null && v.length > 0) {
+				o.SetsLost = v;
+			}
+		}
+		return o;
+	}
+
+	function write_tbc_Upgrade(w, o) {
+		o = o || {};
+		write_tbc_Item(w, o.Item);
+		w.uvarint(o.Slot);
+		w.float64(o.DPS);
+		w.float64(o.Delta);
+		w.float64(o.DeltaErr);
+		w.varint(o.Iterations);
+		w.list(o.SetsGained, (w, v) => w.string(v));
+		w.list(o.SetsLost, (w, v) => w.string(v));
+	}
+
+	function read_tbc_SlotUpgrades(r) {
+		const o = {};
+		o.Slot = r.uvarint();
+		o.Current = r.list((r) => read_tbc_Item(r));
+		o.Upgrades = r.list((r) => read_tbc_Upgrade(r));
+		return o;
+	}
+
+	function write_tbc_SlotUpgrades(w, o) {
+		o = o || {};
+		w.uvarint(o.Slot);
+		w.list(o.Current, (w, v) => write_tbc_Item(w, v));
+		w.list(o.Upgrades, (w, v) => write_tbc_Upgrade(w, v));
+	}
+
+	function read_tbc_UpgradeResult(r) {
+		const o = {};
+		o.BaseDPS = r.float64();
+		o.Slots = r.list((r) => read_tbc_SlotUpgrades(r));
+		return o;
+	}
+
+	function write_tbc_UpgradeResult(w, o) {
+		o = o || {};
+		w.float64(o.BaseDPS);
+		w.list(o.Slots, (w, v) => write_tbc_SlotUpgrades(w, v));
+	}
+
+	return {
+		decodeSimulateRequest: (bytes) => {
+			const r = new Reader(bytes, 0x1830358c);
+			const v = read_api_SimulateRequest(r);
+			r.end();
+			return v;
+		},
+		encodeSimulateRequest: (v) => {
+			const w = new Writer(0x1830358c);
+			write_api_SimulateRequest(w, v);
+			return w.done();
+		},
+		decodeSimResults: (bytes) => {
+			const r = new Reader(bytes, 0x8f1d8aba);
+			const v = r.list((r) => read_tbc_SimResult(r));
+			r.end();
+			return v;
+		},
+		encodeSimResults: (v) => {
+			const w = new Writer(0x8f1d8aba);
+			w.list(v, (w, v) => write_tbc_SimResult(w, v));
+			return w.done();
+		},
+		decodeStatWeightsRequest: (bytes) => {
+			const r = new Reader(bytes, 0x14325c39);
+			const v = read_api_StatWeightsRequest(r);
+			r.end();
+			return v;
+		},
+		encodeStatWeightsRequest: (v) => {
+			const w = new Writer(0x14325c39);
+			write_api_StatWeightsRequest(w, v);
+			return w.done();
+		},
+		decodeStatWeightsResult: (bytes) => {
+			const r = new Reader(bytes, 0x4a291da4);
+			const v = read_tbc_StatWeightsResult(r);
+			r.end();
+			return v;
+		},
+		encodeStatWeightsResult: (v) => {
+			const w = new Writer(0x4a291da4);
+			write_tbc_StatWeightsResult(w, v);
+			return w.done();
+		},
+		decodeComputeStatsRequest: (bytes) => {
+			const r = new Reader(bytes, 0x8176da1c);
+			const v = read_api_ComputeStatsRequest(r);
+			r.end();
+			return v;
+		},
+		encodeComputeStatsRequest: (v) => {
+			const w = new Writer(0x8176da1c);
+			write_api_ComputeStatsRequest(w, v);
+			return w.done();
+		},
+		decodeComputeStatsResult: (bytes) => {
+			const r = new Reader(bytes, 0x1fb237d2);
+			const v = read_api_ComputeStatsResult(r);
+			r.end();
+			return v;
+		},
+		encodeComputeStatsResult: (v) => {
+			const w = new Writer(0x1fb237d2);
+			write_api_ComputeStatsResult(w, v);
+			return w.done();
+		},
+		decodeItemFilter: (bytes) => {
+			const r = new Reader(bytes, 0x5719e6b2);
+			const v = read_tbc_ItemFilter(r);
+			r.end();
+			return v;
+		},
+		encodeItemFilter: (v) => {
+			const w = new Writer(0x5719e6b2);
+			write_tbc_ItemFilter(w, v);
+			return w.done();
+		},
+		decodeGearListResult: (bytes) => {
+			const r = new Reader(bytes, 0x5804adf9);
+			const v = read_api_GearListResult(r);
+			r.end();
+			return v;
+		},
+		encodeGearListResult: (v) => {
+			const w = new Writer(0x5804adf9);
+			write_api_GearListResult(w, v);
+			return w.done();
+		},
+		decodeGemsRequest: (bytes) => {
+			const r = new Reader(bytes, 0xbde8e575);
+			const v = read_api_GemsRequest(r);
+			r.end();
+			return v;
+		},
+		encodeGemsRequest: (v) => {
+			const w = new Writer(0xbde8e575);
+			write_api_GemsRequest(w, v);
+			return w.done();
+		},
+		decodeGemOptimizerResult: (bytes) => {
+			const r = new Reader(bytes, 0x721911da);
+			const v = read_tbc_GemOptimizerResult(r);
+			r.end();
+			return v;
+		},
+		encodeGemOptimizerResult: (v) => {
+			const w = new Writer(0x721911da);
+			write_tbc_GemOptimizerResult(w, v);
+			return w.done();
+		},
+		decodeUpgradesRequest: (bytes) => {
+			const r = new Reader(bytes, 0x03217e9a);
+			const v = read_api_UpgradesRequest(r);
+			r.end();
+			return v;
+		},
+		encodeUpgradesRequest: (v) => {
+			const w = new Writer(0x03217e9a);
+			write_api_UpgradesRequest(w, v);
+			return w.done();
+		},
+		decodeUpgradeResult: (bytes) => {
+			const r = new Reader(bytes, 0x0b619f55);
+			const v = read_tbc_UpgradeResult(r);
+			r.end();
+			return v;
+		},
+		encodeUpgradeResult: (v) => {
+			const w = new Writer(0x0b619f55);
+			write_tbc_UpgradeResult(w, v);
+			return w.done();
+		},
+	};
+})();
+
+if (typeof module !== "undefined") {
+	module.exports = wire;
+}
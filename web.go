@@ -1,5 +1,7 @@
 package main
 
+//go:generate go run ./ui/wasmbuild
+
 import (
 	"log"
 	"net/http"
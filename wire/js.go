@@ -0,0 +1,406 @@
+package wire
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Message is a type with a generated JS decoder and encoder, decode<Name> and encode<Name>.
+type Message struct {
+	Name  string
+	Value interface{} // a value of the type.
+}
+
+// GenerateJS writes a script defining a global wire object with a decoder and encoder for each message.
+// The decoders take a Uint8Array and the encoders return one. generator is the command that
+// writes the script, for its header.
+func GenerateJS(w io.Writer, generator string, messages []Message) error {
+	g := &jsGen{names: map[*schema]string{}, taken: map[string]bool{}}
+	exports := []string{}
+	for _, m := range messages {
+		s, err := schemaOf(reflect.TypeOf(m.Value))
+		if err != nil {
+			return fmt.Errorf("message %s: %w", m.Name, err)
+		}
+		fp := s.fingerprint()
+		exports = append(exports,
+			fmt.Sprintf("\t\tdecode%s: (bytes) => {\n\t\t\tconst r = new Reader(bytes, 0x%08x);\n\t\t\tconst v = %s;\n\t\t\tr.end();\n\t\t\treturn v;\n\t\t},\n", m.Name, fp, g.read(s, "r")),
+			fmt.Sprintf("\t\tencode%s: (v) => {\n\t\t\tconst w = new Writer(0x%08x);\n\t\t\t%s;\n\t\t\treturn w.done();\n\t\t},\n", m.Name, fp, g.write(s, "w", "v")))
+	}
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "// Code generated by %s; DO NOT EDIT.\n\n", generator)
+	fmt.Fprintf(out, "// wire decodes and encodes the binary messages of the sim, see package wire.\n")
+	fmt.Fprintf(out, "var wire = (() => {\n")
+	fmt.Fprintf(out, jsRuntime, Version)
+	for _, fn := range g.funcs {
+		out.WriteString(fn)
+	}
+	out.WriteString("\treturn {\n")
+	for _, e := range exports {
+		out.WriteString(e)
+	}
+	out.WriteString("\t};\n})();\n\nif (typeof module !== \"undefined\") {\n\tmodule.exports = wire;\n}\n")
+	return out.Flush()
+}
+
+// jsGen names a reader and writer function for every struct type and collects them.
+type jsGen struct {
+	names map[*schema]string
+	taken map[string]bool
+	funcs []string
+}
+
+// name returns the name of the functions of a struct, writing them the first time.
+func (g *jsGen) name(s *schema) string {
+	if name, ok := g.names[s]; ok {
+		return name
+	}
+	name := s.typ.Name()
+	if pkg := s.typ.PkgPath(); pkg != "" {
+		name = pkg[strings.LastIndex(pkg, "/")+1:] + "_" + name
+	}
+	if name == "" {
+		name = "anon"
+	}
+	for base, n := name, 2; g.taken[name]; n++ {
+		name = fmt.Sprintf("%s%d", base, n)
+	}
+	g.taken[name] = true
+	g.names[s] = name // before the fields, for recursive types.
+
+	read := &strings.Builder{}
+	fmt.Fprintf(read, "\tfunction read_%s(r) {\n\t\tconst o = {};\n", name)
+	write := &strings.Builder{}
+	fmt.Fprintf(write, "\tfunction write_%s(w, o) {\n\t\to = o || {};\n", name)
+	for _, f := range s.fields {
+		key := jsKey(f.name)
+		if f.omitEmpty && f.schema.kind != kindStruct && f.schema.kind != kindArray {
+			// Like JSON, leave out empty values.
+			fmt.Fprintf(read, "\t\t{\n\t\t\tconst v = %s;\n\t\t\tif (%s) {\n\t\t\t\to%s = v;\n\t\t\t}\n\t\t}\n", g.read(f.schema, "r"), notEmpty(f.schema, "v"), key)
+		} else {
+			fmt.Fprintf(read, "\t\to%s = %s;\n", key, g.read(f.schema, "r"))
+		}
+		fmt.Fprintf(write, "\t\t%s;\n", g.write(f.schema, "w", "o"+key))
+	}
+	read.WriteString("\t\treturn o;\n\t}\n\n")
+	write.WriteString("\t}\n\n")
+	g.funcs = append(g.funcs, read.String(), write.String())
+	return name
+}
+
+// jsKey is the property access of a field name.
+func jsKey(name string) string {
+	for i, c := range name {
+		if !(c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && c >= '0' && c <= '9') {
+			return fmt.Sprintf("[%q]", name)
+		}
+	}
+	return "." + name
+}
+
+func notEmpty(s *schema, v string) string {
+	switch s.kind {
+	case kindBool:
+		return v
+	case kindString:
+		return v + ` !== ""`
+	case kindBytes, kindSlice:
+		return v + " !== null && " + v + ".length > 0"
+	case kindMap:
+		return v + " !== null && Object.keys(" + v + ").length > 0"
+	case kindPtr:
+		return v + " !== null"
+	default:
+		return v + " !== 0"
+	}
+}
+
+// read is an expression reading a value of s with the reader r.
+func (g *jsGen) read(s *schema, r string) string {
+	switch s.kind {
+	case kindBool:
+		return r + ".bool()"
+	case kindInt:
+		return r + ".varint()"
+	case kindUint:
+		return r + ".uvarint()"
+	case kindFloat32:
+		return r + ".float32()"
+	case kindFloat64:
+		return r + ".float64()"
+	case kindString:
+		return r + ".string()"
+	case kindBytes:
+		return r + ".bytes()"
+	case kindSlice:
+		return fmt.Sprintf("%s.list((r) => %s)", r, g.read(s.elem, "r"))
+	case kindArray:
+		return fmt.Sprintf("%s.array(%d, (r) => %s)", r, s.typ.Len(), g.read(s.elem, "r"))
+	case kindMap:
+		return fmt.Sprintf("%s.map((r) => %s, (r) => %s)", r, g.read(s.key, "r"), g.read(s.elem, "r"))
+	case kindPtr:
+		return fmt.Sprintf("%s.ptr((r) => %s)", r, g.read(s.elem, "r"))
+	default:
+		return fmt.Sprintf("read_%s(%s)", g.name(s), r)
+	}
+}
+
+// write is a statement writing the value v of s with the writer w.
+func (g *jsGen) write(s *schema, w string, v string) string {
+	switch s.kind {
+	case kindBool:
+		return fmt.Sprintf("%s.bool(%s)", w, v)
+	case kindInt:
+		return fmt.Sprintf("%s.varint(%s)", w, v)
+	case kindUint:
+		return fmt.Sprintf("%s.uvarint(%s)", w, v)
+	case kindFloat32:
+		return fmt.Sprintf("%s.float32(%s)", w, v)
+	case kindFloat64:
+		return fmt.Sprintf("%s.float64(%s)", w, v)
+	case kindString:
+		return fmt.Sprintf("%s.string(%s)", w, v)
+	case kindBytes:
+		return fmt.Sprintf("%s.bytes(%s)", w, v)
+	case kindSlice:
+		return fmt.Sprintf("%s.list(%s, (w, v) => %s)", w, v, g.write(s.elem, "w", "v"))
+	case kindArray:
+		return fmt.Sprintf("%s.array(%d, %s, (w, v) => %s)", w, s.typ.Len(), v, g.write(s.elem, "w", "v"))
+	case kindMap:
+		return fmt.Sprintf("%s.map(%s, (w, v) => %s, (w, v) => %s)", w, v, g.write(s.key, "w", "v"), g.write(s.elem, "w", "v"))
+	case kindPtr:
+		return fmt.Sprintf("%s.ptr(%s, (w, v) => %s)", w, v, g.write(s.elem, "w", "v"))
+	default:
+		return fmt.Sprintf("write_%s(%s, %s)", g.name(s), w, v)
+	}
+}
+
+// jsRuntime is the reader and writer every generated function uses, formatted with the Version.
+// Integers are JS numbers, exact up to 2^53 like JSON.parse.
+const jsRuntime = `	const version = %d;
+	const textDecoder = new TextDecoder();
+	const textEncoder = new TextEncoder();
+
+	class Reader {
+		constructor(bytes, fingerprint) {
+			this.b = bytes;
+			this.v = new DataView(bytes.buffer, bytes.byteOffset, bytes.byteLength);
+			this.p = 0;
+			if (this.byte() != version) {
+				throw new Error("wire: message is of another version of the format");
+			}
+			this.need(4);
+			if (this.v.getUint32(this.p, true) != fingerprint) {
+				throw new Error("wire: message is of a different type, or wire.js is out of date");
+			}
+			this.p += 4;
+		}
+		need(n) {
+			if (this.p + n > this.b.length) {
+				throw new Error("wire: message is cut short");
+			}
+		}
+		end() {
+			if (this.p != this.b.length) {
+				throw new Error("wire: " + (this.b.length - this.p) + " bytes left over");
+			}
+		}
+		byte() {
+			this.need(1);
+			return this.b[this.p++];
+		}
+		bool() {
+			return this.byte() != 0;
+		}
+		uvarint() {
+			let x = 0, scale = 1, b;
+			do {
+				b = this.byte();
+				x += (b & 0x7f) * scale;
+				scale *= 128;
+			} while (b & 0x80);
+			return x;
+		}
+		varint() {
+			const u = this.uvarint();
+			return u %% 2 ? -(u + 1) / 2 : u / 2;
+		}
+		float32() {
+			this.need(4);
+			const x = this.v.getFloat32(this.p, true);
+			this.p += 4;
+			return x;
+		}
+		float64() {
+			this.need(8);
+			const x = this.v.getFloat64(this.p, true);
+			this.p += 8;
+			return x;
+		}
+		string() {
+			const n = this.uvarint();
+			this.need(n);
+			const s = textDecoder.decode(this.b.subarray(this.p, this.p + n));
+			this.p += n;
+			return s;
+		}
+		bytes() {
+			const n = this.uvarint();
+			if (n == 0) {
+				return null;
+			}
+			this.need(n - 1);
+			const out = this.b.slice(this.p, this.p + n - 1);
+			this.p += n - 1;
+			return out;
+		}
+		list(read) {
+			const n = this.uvarint();
+			if (n == 0) {
+				return null;
+			}
+			const out = new Array(n - 1);
+			for (let i = 0; i < n - 1; i++) {
+				out[i] = read(this);
+			}
+			return out;
+		}
+		array(n, read) {
+			const out = new Array(n);
+			for (let i = 0; i < n; i++) {
+				out[i] = read(this);
+			}
+			return out;
+		}
+		map(readKey, readValue) {
+			const n = this.uvarint();
+			if (n == 0) {
+				return null;
+			}
+			const out = {};
+			for (let i = 0; i < n - 1; i++) {
+				const k = readKey(this);
+				out[k] = readValue(this);
+			}
+			return out;
+		}
+		ptr(read) {
+			return this.bool() ? read(this) : null;
+		}
+	}
+
+	// Writer takes what JSON would: numbers as strings, and null or missing values as the zero value.
+	class Writer {
+		constructor(fingerprint) {
+			this.b = new Uint8Array(256);
+			this.v = new DataView(this.b.buffer);
+			this.p = 0;
+			this.byte(version);
+			this.grow(4);
+			this.v.setUint32(this.p, fingerprint, true);
+			this.p += 4;
+		}
+		grow(n) {
+			if (this.p + n <= this.b.length) {
+				return;
+			}
+			let size = this.b.length * 2;
+			while (size < this.p + n) {
+				size *= 2;
+			}
+			const b = new Uint8Array(size);
+			b.set(this.b);
+			this.b = b;
+			this.v = new DataView(b.buffer);
+		}
+		done() {
+			return this.b.slice(0, this.p);
+		}
+		byte(x) {
+			this.grow(1);
+			this.b[this.p++] = x;
+		}
+		bool(x) {
+			this.byte(x ? 1 : 0);
+		}
+		uvarint(x) {
+			x = Math.max(0, Math.trunc(Number(x)) || 0);
+			while (x >= 128) {
+				this.byte(x %% 128 + 128);
+				x = Math.floor(x / 128);
+			}
+			this.byte(x);
+		}
+		varint(x) {
+			x = Math.trunc(Number(x)) || 0;
+			this.uvarint(x < 0 ? -2 * x - 1 : 2 * x);
+		}
+		float32(x) {
+			this.grow(4);
+			this.v.setFloat32(this.p, Number(x) || 0, true);
+			this.p += 4;
+		}
+		float64(x) {
+			this.grow(8);
+			this.v.setFloat64(this.p, Number(x) || 0, true);
+			this.p += 8;
+		}
+		string(x) {
+			const s = textEncoder.encode(x == null ? "" : String(x));
+			this.uvarint(s.length);
+			this.grow(s.length);
+			this.b.set(s, this.p);
+			this.p += s.length;
+		}
+		bytes(x) {
+			if (x == null) {
+				this.byte(0);
+				return;
+			}
+			this.uvarint(x.length + 1);
+			this.grow(x.length);
+			this.b.set(x, this.p);
+			this.p += x.length;
+		}
+		list(x, write) {
+			if (x == null) {
+				this.byte(0);
+				return;
+			}
+			this.uvarint(x.length + 1);
+			for (const v of x) {
+				write(this, v);
+			}
+		}
+		array(n, x, write) {
+			for (let i = 0; i < n; i++) {
+				write(this, x == null ? undefined : x[i]);
+			}
+		}
+		map(x, writeKey, writeValue) {
+			if (x == null) {
+				this.byte(0);
+				return;
+			}
+			const keys = Object.keys(x);
+			this.uvarint(keys.length + 1);
+			for (const k of keys) {
+				writeKey(this, k);
+				writeValue(this, x[k]);
+			}
+		}
+		ptr(x, write) {
+			if (x == null) {
+				this.byte(0);
+				return;
+			}
+			this.byte(1);
+			write(this, x);
+		}
+	}
+
+`
@@ -0,0 +1,585 @@
+// Package wire is a compact binary form of the sim's requests and results, for the wasm bridge
+// and the HTTP API, with JS decoders and encoders generated from the same Go types.
+//
+// The schema is the Go type itself. Values are written field by field in declaration order,
+// without names, taking the fields encoding/json would (embedded structs are flattened and
+// json:"-" fields left out):
+//
+//	bool            1 byte
+//	int kinds       zigzag varint
+//	uint kinds      varint
+//	float32/64      4/8 bytes, little endian
+//	string          varint length, then UTF-8
+//	slice, map      varint length+1 (0 for nil), then the elements (key then value for a map)
+//	[]byte          varint length+1 (0 for nil), then the bytes
+//	array           the elements
+//	pointer         1 byte, 0 for nil, then the value
+//
+// A message starts with the format Version and a fingerprint of its type's schema, so a decoder
+// generated from other types fails instead of reading garbage. Decoded JS values have the same
+// shape as JSON.parse of the Go type's JSON, except byte slices are Uint8Arrays.
+package wire
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Version is the first byte of every message, bumped when the encoding itself changes.
+const Version = 1
+
+// ContentType is the media type of a message over HTTP.
+const ContentType = "application/vnd.wowsim.wire"
+
+type kind int
+
+const (
+	kindBool kind = iota
+	kindInt
+	kindUint
+	kindFloat32
+	kindFloat64
+	kindString
+	kindBytes
+	kindSlice
+	kindArray
+	kindMap
+	kindPtr
+	kindStruct
+)
+
+// schema is how a Go type is written.
+type schema struct {
+	typ    reflect.Type
+	kind   kind
+	elem   *schema // of a slice, array, map value or pointer.
+	key    *schema // of a map.
+	fields []field // of a struct.
+}
+
+type field struct {
+	name      string // the JSON name.
+	index     []int
+	omitEmpty bool
+	schema    *schema
+}
+
+var (
+	schemaMu sync.Mutex
+	schemas  = map[reflect.Type]*schema{}
+)
+
+// schemaOf returns the schema of t, an error if t has a type with no binary form (like an interface).
+func schemaOf(t reflect.Type) (*schema, error) {
+	schemaMu.Lock()
+	defer schemaMu.Unlock()
+	building := map[reflect.Type]*schema{}
+	s, err := buildSchema(t, building)
+	if err != nil {
+		return nil, err
+	}
+	for t, s := range building {
+		schemas[t] = s
+	}
+	return s, nil
+}
+
+// buildSchema must be called with schemaMu locked. building has the schemas of this call, only kept once
+// every one of them is complete.
+func buildSchema(t reflect.Type, building map[reflect.Type]*schema) (*schema, error) {
+	if s, ok := schemas[t]; ok {
+		return s, nil
+	}
+	if s, ok := building[t]; ok {
+		return s, nil // a recursive type, filled in further up.
+	}
+	s := &schema{typ: t}
+	building[t] = s
+	var err error
+	switch t.Kind() {
+	case reflect.Bool:
+		s.kind = kindBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s.kind = kindInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		s.kind = kindUint
+	case reflect.Float32:
+		s.kind = kindFloat32
+	case reflect.Float64:
+		s.kind = kindFloat64
+	case reflect.String:
+		s.kind = kindString
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			s.kind = kindBytes
+			break
+		}
+		s.kind = kindSlice
+		s.elem, err = buildSchema(t.Elem(), building)
+	case reflect.Array:
+		s.kind = kindArray
+		s.elem, err = buildSchema(t.Elem(), building)
+	case reflect.Map:
+		s.kind = kindMap
+		switch t.Key().Kind() {
+		case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		default:
+			return nil, fmt.Errorf("wire: map keys of %s must be strings or integers", t)
+		}
+		if s.key, err = buildSchema(t.Key(), building); err == nil {
+			s.elem, err = buildSchema(t.Elem(), building)
+		}
+	case reflect.Ptr:
+		s.kind = kindPtr
+		s.elem, err = buildSchema(t.Elem(), building)
+	case reflect.Struct:
+		s.kind = kindStruct
+		s.fields, err = structFields(t, building)
+	default:
+		return nil, fmt.Errorf("wire: %s has no binary form", t)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// structFields returns the fields encoding/json would use, in the order they are declared:
+// embedded structs are flattened, and of fields with the same name the least nested one wins.
+func structFields(t reflect.Type, building map[reflect.Type]*schema) ([]field, error) {
+	type candidate struct {
+		field
+		depth  int
+		tagged bool
+	}
+	var all []candidate
+	var walk func(t reflect.Type, index []int, depth int) error
+	walk = func(t reflect.Type, index []int, depth int) error {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, opts := tag, ""
+			if comma := strings.Index(tag, ","); comma >= 0 {
+				name, opts = tag[:comma], tag[comma+1:]
+			}
+			idx := append(append([]int(nil), index...), i)
+			if f.Anonymous && name == "" {
+				if f.Type.Kind() == reflect.Ptr {
+					return fmt.Errorf("wire: embedded pointer %s in %s has no binary form", f.Type, t)
+				}
+				if f.Type.Kind() == reflect.Struct {
+					if err := walk(f.Type, idx, depth+1); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+			if f.PkgPath != "" {
+				continue // unexported.
+			}
+			c := candidate{field: field{name: name, index: idx}, depth: depth, tagged: name != ""}
+			if c.name == "" {
+				c.name = f.Name
+			}
+			for _, opt := range strings.Split(opts, ",") {
+				if opt == "omitempty" {
+					c.omitEmpty = true
+				}
+			}
+			all = append(all, c)
+		}
+		return nil
+	}
+	if err := walk(t, nil, 0); err != nil {
+		return nil, err
+	}
+
+	var fields []field
+	for _, c := range all {
+		dominant := true
+		for _, o := range all {
+			if o.name != c.name || reflect.DeepEqual(o.index, c.index) {
+				continue
+			}
+			if o.depth < c.depth || o.depth == c.depth && (o.tagged || !c.tagged) {
+				dominant = false
+				break
+			}
+		}
+		if !dominant {
+			continue
+		}
+		s, err := buildSchema(t.FieldByIndex(c.index).Type, building)
+		if err != nil {
+			return nil, err
+		}
+		c.schema = s
+		fields = append(fields, c.field)
+	}
+	return fields, nil
+}
+
+// describe writes the schema for its fingerprint. Structs are described once, by name after that.
+func (s *schema) describe(b *strings.Builder, seen map[*schema]bool) {
+	switch s.kind {
+	case kindBool:
+		b.WriteString("bool")
+	case kindInt:
+		b.WriteString("int")
+	case kindUint:
+		b.WriteString("uint")
+	case kindFloat32:
+		b.WriteString("float32")
+	case kindFloat64:
+		b.WriteString("float64")
+	case kindString:
+		b.WriteString("string")
+	case kindBytes:
+		b.WriteString("bytes")
+	case kindSlice:
+		b.WriteString("[]")
+		s.elem.describe(b, seen)
+	case kindArray:
+		fmt.Fprintf(b, "[%d]", s.typ.Len())
+		s.elem.describe(b, seen)
+	case kindMap:
+		b.WriteString("map[")
+		s.key.describe(b, seen)
+		b.WriteString("]")
+		s.elem.describe(b, seen)
+	case kindPtr:
+		b.WriteString("*")
+		s.elem.describe(b, seen)
+	case kindStruct:
+		b.WriteString(s.typ.String())
+		if seen[s] {
+			return
+		}
+		seen[s] = true
+		b.WriteString("{")
+		for _, f := range s.fields {
+			b.WriteString(f.name)
+			if f.omitEmpty {
+				b.WriteString(",omitempty")
+			}
+			b.WriteString(" ")
+			f.schema.describe(b, seen)
+			b.WriteString(";")
+		}
+		b.WriteString("}")
+	}
+}
+
+func (s *schema) fingerprint() uint32 {
+	b := &strings.Builder{}
+	s.describe(b, map[*schema]bool{})
+	h := fnv.New32a()
+	h.Write([]byte(b.String()))
+	return h.Sum32()
+}
+
+// Marshal writes v as a message.
+func Marshal(v interface{}) ([]byte, error) {
+	s, err := schemaOf(reflect.TypeOf(v))
+	if err != nil {
+		return nil, err
+	}
+	e := &encoder{buf: make([]byte, 5, 256)}
+	e.buf[0] = Version
+	binary.LittleEndian.PutUint32(e.buf[1:], s.fingerprint())
+	e.value(s, reflect.ValueOf(v))
+	return e.buf, nil
+}
+
+type encoder struct {
+	buf     []byte
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (e *encoder) uvarint(x uint64) {
+	n := binary.PutUvarint(e.scratch[:], x)
+	e.buf = append(e.buf, e.scratch[:n]...)
+}
+
+func (e *encoder) varint(x int64) {
+	n := binary.PutVarint(e.scratch[:], x)
+	e.buf = append(e.buf, e.scratch[:n]...)
+}
+
+func (e *encoder) value(s *schema, v reflect.Value) {
+	switch s.kind {
+	case kindBool:
+		if v.Bool() {
+			e.buf = append(e.buf, 1)
+		} else {
+			e.buf = append(e.buf, 0)
+		}
+	case kindInt:
+		e.varint(v.Int())
+	case kindUint:
+		e.uvarint(v.Uint())
+	case kindFloat32:
+		e.buf = append(e.buf, 0, 0, 0, 0)
+		binary.LittleEndian.PutUint32(e.buf[len(e.buf)-4:], math.Float32bits(float32(v.Float())))
+	case kindFloat64:
+		e.buf = append(e.buf, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.LittleEndian.PutUint64(e.buf[len(e.buf)-8:], math.Float64bits(v.Float()))
+	case kindString:
+		e.uvarint(uint64(v.Len()))
+		e.buf = append(e.buf, v.String()...)
+	case kindBytes:
+		if v.IsNil() {
+			e.uvarint(0)
+			return
+		}
+		e.uvarint(uint64(v.Len()) + 1)
+		for i := 0; i < v.Len(); i++ {
+			e.buf = append(e.buf, byte(v.Index(i).Uint()))
+		}
+	case kindSlice:
+		if v.IsNil() {
+			e.uvarint(0)
+			return
+		}
+		e.uvarint(uint64(v.Len()) + 1)
+		for i := 0; i < v.Len(); i++ {
+			e.value(s.elem, v.Index(i))
+		}
+	case kindArray:
+		for i := 0; i < v.Len(); i++ {
+			e.value(s.elem, v.Index(i))
+		}
+	case kindMap:
+		if v.IsNil() {
+			e.uvarint(0)
+			return
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return lessKey(keys[i], keys[j]) }) // so the same map is the same bytes.
+		e.uvarint(uint64(len(keys)) + 1)
+		for _, k := range keys {
+			e.value(s.key, k)
+			e.value(s.elem, v.MapIndex(k))
+		}
+	case kindPtr:
+		if v.IsNil() {
+			e.buf = append(e.buf, 0)
+			return
+		}
+		e.buf = append(e.buf, 1)
+		e.value(s.elem, v.Elem())
+	case kindStruct:
+		for _, f := range s.fields {
+			e.value(f.schema, v.FieldByIndex(f.index))
+		}
+	}
+}
+
+func lessKey(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.String:
+		return a.String() < b.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	default:
+		return a.Uint() < b.Uint()
+	}
+}
+
+// Unmarshal reads a message written by Marshal (or a generated JS encoder) into v, which must be a pointer
+// to the same type. A message of another type or a damaged one is an error.
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("wire: Unmarshal needs a non-nil pointer, not %T", v)
+	}
+	s, err := schemaOf(rv.Type().Elem())
+	if err != nil {
+		return err
+	}
+	if len(data) < 5 {
+		return errTruncated
+	}
+	if data[0] != Version {
+		return fmt.Errorf("wire: message is version %d, not %d", data[0], Version)
+	}
+	if binary.LittleEndian.Uint32(data[1:]) != s.fingerprint() {
+		return fmt.Errorf("wire: message isn't a %s, or is from a different version of it", s.typ)
+	}
+	d := &decoder{data: data[5:]}
+	d.value(s, rv.Elem())
+	if d.err == nil && len(d.data) > 0 {
+		d.err = fmt.Errorf("wire: %d bytes left over after the %s", len(d.data), s.typ)
+	}
+	return d.err
+}
+
+var errTruncated = errors.New("wire: message is cut short")
+
+// decoder keeps the first error, reading nothing after it.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+	d.data = nil
+}
+
+func (d *decoder) take(n uint64) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > uint64(len(d.data)) {
+		d.fail(errTruncated)
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	x, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail(errTruncated)
+		return 0
+	}
+	d.data = d.data[n:]
+	return x
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	x, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.fail(errTruncated)
+		return 0
+	}
+	d.data = d.data[n:]
+	return x
+}
+
+// length reads the length of a slice or map, -1 for nil. Every element takes at least a byte,
+// so a length over the bytes left is damaged and isn't allocated.
+func (d *decoder) length() int {
+	n := d.uvarint()
+	if n == 0 {
+		return -1
+	}
+	if n-1 > uint64(len(d.data)) {
+		d.fail(errTruncated)
+		return -1
+	}
+	return int(n - 1)
+}
+
+func (d *decoder) value(s *schema, v reflect.Value) {
+	if d.err != nil {
+		return
+	}
+	switch s.kind {
+	case kindBool:
+		if b := d.take(1); b != nil {
+			v.SetBool(b[0] != 0)
+		}
+	case kindInt:
+		x := d.varint()
+		if v.OverflowInt(x) {
+			d.fail(fmt.Errorf("wire: %d overflows %s", x, v.Type()))
+			return
+		}
+		v.SetInt(x)
+	case kindUint:
+		x := d.uvarint()
+		if v.OverflowUint(x) {
+			d.fail(fmt.Errorf("wire: %d overflows %s", x, v.Type()))
+			return
+		}
+		v.SetUint(x)
+	case kindFloat32:
+		if b := d.take(4); b != nil {
+			v.SetFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(b))))
+		}
+	case kindFloat64:
+		if b := d.take(8); b != nil {
+			v.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(b)))
+		}
+	case kindString:
+		v.SetString(string(d.take(d.uvarint())))
+	case kindBytes:
+		n := d.length()
+		if n < 0 {
+			v.Set(reflect.Zero(v.Type()))
+			return
+		}
+		b := d.take(uint64(n))
+		out := reflect.MakeSlice(v.Type(), n, n)
+		for i, x := range b {
+			out.Index(i).SetUint(uint64(x))
+		}
+		v.Set(out)
+	case kindSlice:
+		n := d.length()
+		if n < 0 {
+			v.Set(reflect.Zero(v.Type()))
+			return
+		}
+		out := reflect.MakeSlice(v.Type(), n, n)
+		for i := 0; i < n; i++ {
+			d.value(s.elem, out.Index(i))
+		}
+		v.Set(out)
+	case kindArray:
+		for i := 0; i < v.Len(); i++ {
+			d.value(s.elem, v.Index(i))
+		}
+	case kindMap:
+		n := d.length()
+		if n < 0 {
+			v.Set(reflect.Zero(v.Type()))
+			return
+		}
+		out := reflect.MakeMapWithSize(v.Type(), n)
+		for i := 0; i < n && d.err == nil; i++ {
+			key := reflect.New(s.typ.Key()).Elem()
+			d.value(s.key, key)
+			val := reflect.New(s.typ.Elem()).Elem()
+			d.value(s.elem, val)
+			out.SetMapIndex(key, val)
+		}
+		v.Set(out)
+	case kindPtr:
+		b := d.take(1)
+		if b == nil || b[0] == 0 {
+			v.Set(reflect.Zero(v.Type()))
+			return
+		}
+		p := reflect.New(s.typ.Elem())
+		d.value(s.elem, p.Elem())
+		v.Set(p)
+	case kindStruct:
+		for _, f := range s.fields {
+			d.value(f.schema, v.FieldByIndex(f.index))
+		}
+	}
+}
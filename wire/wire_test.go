@@ -0,0 +1,84 @@
+package wire
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type inner struct {
+	Shared string // shadowed by outer.Shared.
+	Kept   float32
+}
+
+type sample struct {
+	inner
+	Shared  int
+	Name    string `json:"name"`
+	Neg     int64
+	Big     uint64
+	Flag    bool
+	Bytes   []byte
+	Floats  []float64
+	Nil     []string
+	ByID    map[int32]string
+	Ptr     *inner
+	NilPtr  *inner
+	Arr     [2]int
+	Skipped func() `json:"-"`
+	private int
+}
+
+func TestRoundTrip(t *testing.T) {
+	in := sample{
+		inner:  inner{Shared: "lost", Kept: 1.5},
+		Shared: 7, Name: "Tidefury Helm ✓", Neg: -1 << 40, Big: 1<<64 - 1, Flag: true,
+		Bytes: []byte{0, 1, 255}, Floats: []float64{0.1, -2},
+		ByID: map[int32]string{-3: "a", 9: "b"}, Ptr: &inner{Kept: -1}, Arr: [2]int{1, -1},
+	}
+	data, err := Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := sample{}
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	in.inner.Shared = "" // not written, like JSON.
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("round trip changed the value:\nin  %+v\nout %+v", in, out)
+	}
+	if again, _ := Marshal(out); !bytes.Equal(again, data) {
+		t.Fatalf("the same value marshalled differently")
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	data, err := Marshal(sample{Name: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name string
+		data []byte
+		into interface{}
+		err  string
+	}{
+		{"other type", data, &inner{}, "isn't a wire.inner"},
+		{"other version", append([]byte{Version + 1}, data[1:]...), &sample{}, "version"},
+		{"cut short", data[:len(data)-1], &sample{}, "cut short"},
+		{"left over", append(data, 0), &sample{}, "left over"},
+		{"not a pointer", data, sample{}, "non-nil pointer"},
+	}
+	for _, c := range cases {
+		err := Unmarshal(c.data, c.into)
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%s: expected an error with %q, got %v", c.name, c.err, err)
+		}
+	}
+
+	if _, err := Marshal(struct{ Any interface{} }{}); err == nil {
+		t.Errorf("expected an interface field to have no binary form")
+	}
+}